@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplWrite bool
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "work with APL rotations in text format",
+	Long:  "work with APL rotations in text format, see sim/core/apl_text.go for the syntax",
+}
+
+var aplFmtCmd = &cobra.Command{
+	Use:   "fmt [files]",
+	Short: "reformat APL text files",
+	Long:  "reformat APL text files, printing the result unless --write is set. Comments are not kept, use notes instead.",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, path := range args {
+			if err := formatAPLFile(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var aplConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "convert an APL rotation between protojson (.json) and text format",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return convertAPLFile(infile, outfile)
+	},
+}
+
+func init() {
+	aplFmtCmd.Flags().BoolVarP(&aplWrite, "write", "w", false, "write the result back to the source files")
+
+	aplConvertCmd.Flags().StringVar(&infile, "infile", "", "location of input file, converted to text if it ends in .json and to protojson otherwise")
+	aplConvertCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	aplConvertCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplFmtCmd)
+	aplCmd.AddCommand(aplConvertCmd)
+}
+
+func readAPLText(path string) (*proto.APLRotation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	rotation, err := core.APLRotationFromText(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	return rotation, nil
+}
+
+func formatAPLFile(path string) error {
+	rotation, err := readAPLText(path)
+	if err != nil {
+		return err
+	}
+
+	text := core.FormatAPLRotation(rotation)
+	if !aplWrite {
+		fmt.Print(text)
+		return nil
+	}
+	return os.WriteFile(path, []byte(text), 0666)
+}
+
+func convertAPLFile(inPath string, outPath string) error {
+	var output string
+	if strings.HasSuffix(inPath, ".json") {
+		data, err := os.ReadFile(inPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", inPath, err)
+		}
+		rotation := &proto.APLRotation{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, rotation); err != nil {
+			return fmt.Errorf("failed to parse %q: %w", inPath, err)
+		}
+		output = core.FormatAPLRotation(rotation)
+	} else {
+		rotation, err := readAPLText(inPath)
+		if err != nil {
+			return err
+		}
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "\t"}.Marshal(rotation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rotation: %w", err)
+		}
+		output = string(data) + "\n"
+	}
+
+	if outPath == "" {
+		fmt.Print(output)
+		return nil
+	}
+	return os.WriteFile(outPath, []byte(output), 0666)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplTuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "search for the best values of tunable constants in a rotation",
+	Run:   aplTuneMain,
+}
+
+func init() {
+	aplTuneCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (APLTuningRequest in protojson format)")
+	aplTuneCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	aplTuneCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	aplTuneCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplTuneCmd)
+}
+
+func aplTuneMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.APLTuningRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.RunAPLTuningAsync(input, reporter, "cmd-apl-tune")
+
+	var finalResult *proto.APLTuningResult
+	for v := range reporter {
+		if v.FinalAplTuningResult != nil {
+			finalResult = v.FinalAplTuningResult
+			break
+		}
+		if verbose {
+			fmt.Printf("Tuning Progress: %d / %d sims\n", v.CompletedSims, v.TotalSims)
+		}
+	}
+
+	if verbose && finalResult.Error == nil {
+		fmt.Printf("DPS: %.2f -> %.2f (± %.2f, 95%% CI)\n", finalResult.InitialDps, finalResult.TunedDps, finalResult.DpsDeltaCi95)
+		for _, value := range finalResult.Values {
+			fmt.Printf("  %s: %s -> %s\n", value.Uuid.Value, value.InitialValue, value.BestValue)
+		}
+	}
+
+	writeOutput(finalResult)
+}
@@ -8,13 +8,16 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/encounters/scripted"
 	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
 )
 
 var (
-	infile  string
-	outfile string
-	verbose bool
+	infile           string
+	outfile          string
+	verbose          bool
+	encounterScripts string
 )
 
 var simCmd = &cobra.Command{
@@ -27,10 +30,17 @@ func init() {
 	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
 	simCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	simCmd.Flags().StringVar(&encounterScripts, "encounterscripts", "", "directory of EncounterScript files (protojson) to load as preset encounters")
 	simCmd.MarkFlagRequired("infile")
 }
 
 func simMain(cmd *cobra.Command, args []string) {
+	if encounterScripts != "" {
+		if err := scripted.LoadEncounterScripts(encounterScripts); err != nil {
+			log.Fatalf("failed to load encounter scripts: %s", err)
+		}
+	}
+
 	data, err := os.ReadFile(infile)
 	if err != nil {
 		log.Fatalf("failed to load input json file %q: %v", infile, err)
@@ -41,7 +51,6 @@ func simMain(cmd *cobra.Command, args []string) {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
 
-	var output []byte
 	reporter := make(chan *proto.ProgressMetrics, 10)
 	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
 
@@ -56,7 +65,11 @@ func simMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	output, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(finalResult)
+	writeOutput(finalResult)
+}
+
+func writeOutput(result goproto.Message) {
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
 	if err != nil {
 		log.Fatalf("failed to marshal final results: %s", err)
 	}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "sim all gear combinations from a bulk request",
+	Run:   bulkMain,
+}
+
+func init() {
+	bulkCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (BulkSimRequest in protojson format)")
+	bulkCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	bulkCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	bulkCmd.MarkFlagRequired("infile")
+}
+
+func bulkMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.BulkSimRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.RunBulkSimAsync(input, reporter, "cmd-bulk-sim")
+
+	var finalResult *proto.BulkSimResult
+	for v := range reporter {
+		if v.FinalBulkResult != nil {
+			finalResult = v.FinalBulkResult
+			break
+		}
+		if verbose {
+			fmt.Printf("Bulk Progress: %d / %d sims\n", v.CompletedSims, v.TotalSims)
+		}
+	}
+
+	writeOutput(finalResult)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	baselineFile string
+	variantFile  string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "compare a baseline and a variant sim using common random numbers",
+	Run:   compareMain,
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&baselineFile, "baseline", "", "location of baseline input file (RaidSimRequest in protojson format)")
+	compareCmd.Flags().StringVar(&variantFile, "variant", "", "location of variant input file (RaidSimRequest in protojson format)")
+	compareCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	compareCmd.Flags().BoolVar(&verbose, "verbose", false, "print a summary of the differences before the full results")
+	compareCmd.MarkFlagRequired("baseline")
+	compareCmd.MarkFlagRequired("variant")
+}
+
+func loadRaidSimRequest(path string) *proto.RaidSimRequest {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", path, err)
+	}
+	request := &proto.RaidSimRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, request)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %s", path, err)
+	}
+	return request
+}
+
+func compareMain(cmd *cobra.Command, args []string) {
+	result := core.CompareSims(&proto.CompareSimsRequest{
+		Baseline: loadRaidSimRequest(baselineFile),
+		Variant:  loadRaidSimRequest(variantFile),
+	})
+	if result.Error != nil {
+		log.Fatalf("compare failed: %s", result.Error.Message)
+	}
+
+	if verbose {
+		fmt.Printf("DPS delta: %+.2f ± %.2f (95%% CI)\n", result.DpsDelta, result.DpsDeltaCi95)
+		for _, action := range result.Actions {
+			damageDelta := action.VariantDamage - action.BaselineDamage
+			castsDelta := action.VariantCasts - action.BaselineCasts
+			if damageDelta == 0 && castsDelta == 0 {
+				continue
+			}
+			fmt.Printf("  %s %v: damage %+.0f, casts %+.2f\n", action.UnitName, core.ProtoToActionID(action.Id), damageDelta, castsDelta)
+		}
+	}
+
+	writeOutput(result)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var reforgeCmd = &cobra.Command{
+	Use:   "reforge",
+	Short: "optimize reforges for the gear in a reforge request",
+	Run:   reforgeMain,
+}
+
+func init() {
+	reforgeCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (ReforgeOptimizeRequest in protojson format)")
+	reforgeCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	reforgeCmd.MarkFlagRequired("infile")
+}
+
+func reforgeMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.ReforgeOptimizeRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	writeOutput(core.OptimizeReforges(input))
+}
@@ -16,7 +16,11 @@ var rootCmd = &cobra.Command{
 func Execute(version string) {
 	rootCmd.AddCommand(newVersionCommand(version))
 	rootCmd.AddCommand(simCmd)
+	rootCmd.AddCommand(bulkCmd)
+	rootCmd.AddCommand(reforgeCmd)
+	rootCmd.AddCommand(compareCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(aplCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -593,6 +593,145 @@ func NewProcDamageEffect(config ProcDamageEffect) {
 	})
 }
 
+type ProcSpellType byte
+
+const (
+	ProcSpellTypeDamage ProcSpellType = iota
+	ProcSpellTypeAbsorb
+	ProcSpellTypeMana
+)
+
+// Item procs which deal damage, shield the wearer or return mana instead of
+// granting stats. These items have no parsed proc ItemEffect, so the proc
+// rate and values are given directly.
+type ProcSpellEffect struct {
+	Name               string
+	ItemID             int32
+	SpellID            int32
+	Type               ProcSpellType
+	Callback           core.AuraCallback
+	ProcMask           core.ProcMask
+	Outcome            core.HitOutcome
+	RequireDamageDealt bool
+
+	ProcChance float64
+	Rppm       core.RPPMConfig
+	ICD        time.Duration
+
+	// Values scale with the item level if a coefficient is given, otherwise
+	// BaseValue is used. Variance is the total spread around the value.
+	Coefficient      float64
+	BaseValue        float64
+	Variance         float64
+	BonusCoefficient float64
+
+	School   core.SpellSchool // Damage procs only
+	Duration time.Duration    // Absorb procs only
+}
+
+func NewProcSpellEffectWithVariants(config ProcSpellEffect, variants []ItemVariant) {
+	var maxItemID int32
+
+	for _, variant := range variants {
+		maxItemID = max(maxItemID, variant.ItemID)
+	}
+
+	for _, variant := range variants {
+		config.Name = variant.ItemName
+		config.ItemID = variant.ItemID
+		core.AddEffectsToTest = (config.ItemID == maxItemID)
+		NewProcSpellEffect(config)
+	}
+
+	core.AddEffectsToTest = true
+}
+
+func NewProcSpellEffect(config ProcSpellEffect) {
+	// Soft fail to allow for overrides for bad effects
+	if core.HasItemEffect(config.ItemID) {
+		return
+	}
+
+	core.NewItemEffect(config.ItemID, func(agent core.Agent, state proto.ItemLevelState) {
+		character := agent.GetCharacter()
+		actionID := core.ActionID{SpellID: config.SpellID}
+
+		value := config.BaseValue
+		if config.Coefficient != 0 {
+			value = core.GetItemEffectScaling(config.ItemID, config.Coefficient, state)
+		}
+		minValue := value * (1 - config.Variance/2)
+		maxValue := value * (1 + config.Variance/2)
+
+		var onProc func(sim *core.Simulation)
+		switch config.Type {
+		case ProcSpellTypeDamage:
+			damageSpell := character.RegisterSpell(core.SpellConfig{
+				ActionID:    actionID,
+				SpellSchool: config.School,
+				ProcMask:    core.ProcMaskEmpty,
+
+				DamageMultiplier: 1,
+				CritMultiplier:   character.DefaultCritMultiplier(),
+				ThreatMultiplier: 1,
+				BonusCoefficient: config.BonusCoefficient,
+
+				ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+					spell.CalcAndDealDamage(sim, target, sim.Roll(minValue, maxValue), spell.OutcomeMagicHitAndCrit)
+				},
+			})
+			onProc = func(sim *core.Simulation) {
+				damageSpell.Cast(sim, character.CurrentTarget)
+			}
+		case ProcSpellTypeAbsorb:
+			// Self-shield as there is no healing sim
+			shield := character.NewDamageAbsorptionAura(core.AbsorptionAuraConfig{
+				Aura: core.Aura{
+					Label:    config.Name + " Shield",
+					ActionID: actionID,
+					Duration: config.Duration,
+				},
+				ShieldStrengthCalculator: func(_ *core.Unit) float64 {
+					return value
+				},
+			})
+			onProc = func(sim *core.Simulation) {
+				shield.Activate(sim)
+			}
+		case ProcSpellTypeMana:
+			if !character.HasManaBar() {
+				return
+			}
+			manaMetrics := character.NewManaMetrics(actionID)
+			onProc = func(sim *core.Simulation) {
+				character.AddMana(sim, sim.Roll(minValue, maxValue), manaMetrics)
+			}
+		}
+
+		var dpm *core.DynamicProcManager
+		if config.Rppm.PPM > 0 {
+			dpm = character.NewRPPMProcManager(config.ItemID, false, false, config.ProcMask, config.Rppm)
+		}
+
+		triggerAura := character.MakeProcTriggerAura(core.ProcTrigger{
+			ActionID:           core.ActionID{ItemID: config.ItemID},
+			Name:               config.Name,
+			Callback:           config.Callback,
+			ProcMask:           config.ProcMask,
+			Outcome:            config.Outcome,
+			RequireDamageDealt: config.RequireDamageDealt,
+			ProcChance:         config.ProcChance,
+			DPM:                dpm,
+			ICD:                config.ICD,
+			Handler: func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
+				onProc(sim)
+			},
+		})
+
+		character.ItemSwap.RegisterProc(config.ItemID, triggerAura)
+	})
+}
+
 // Takes in the SpellResult for the triggering spell, and returns the total damage
 // of a *fresh* Ignite triggered by that spell. Roll-over damage
 // calculations for existing Ignites are handled internally.
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestMaxIterations(t *testing.T) {
+	if v := MaxIterations(&proto.SimOptions{Iterations: 100, MaxIterations: 500}); v != 100 {
+		t.Fatalf("Expected max_iterations to be ignored without a target, got %d", v)
+	}
+	if v := MaxIterations(&proto.SimOptions{Iterations: 100, TargetStdevOfMean: 1}); v != DefaultMaxIterations {
+		t.Fatalf("Expected the default cap, got %d", v)
+	}
+	if v := MaxIterations(&proto.SimOptions{Iterations: 100, MaxIterations: 50, TargetStdevOfMean: 1}); v != 100 {
+		t.Fatalf("Expected iterations to act as a minimum, got %d", v)
+	}
+}
+
+func TestSplitAdaptiveSimRequest(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			Iterations:        100,
+			MaxIterations:     1001,
+			TargetStdevOfMean: 2,
+			RandomSeed:        10,
+		},
+	}
+
+	splitRes := SplitSimRequestForConcurrency(request, 4)
+	if splitRes.ErrorResult != "" {
+		t.Fatalf("Split failed: %s", splitRes.ErrorResult)
+	}
+
+	expectedSeed := int64(10)
+	for i, split := range splitRes.Requests {
+		options := split.SimOptions
+		expectedMax := int32(250)
+		if i == 0 {
+			expectedMax = 251
+		}
+
+		if options.Iterations != 25 || options.MaxIterations != expectedMax {
+			t.Fatalf("Split %d: expected 25-%d iterations, got %d-%d", i, expectedMax, options.Iterations, options.MaxIterations)
+		}
+		// Halving the standard error of 4 equal splits gives the requested precision.
+		if !WithinToleranceFloat64(4, options.TargetStdevOfMean, 0.0001) {
+			t.Fatalf("Split %d: expected a target of 4, got %0.4f", i, options.TargetStdevOfMean)
+		}
+		// Seeds must not overlap even if every split runs to its cap.
+		if options.RandomSeed != expectedSeed {
+			t.Fatalf("Split %d: expected seed %d, got %d", i, expectedSeed, options.RandomSeed)
+		}
+		expectedSeed += int64(expectedMax)
+	}
+}
+
+func TestAdaptiveIterationsConverged(t *testing.T) {
+	// The fake healer deals no damage, so raid DPS has no variance and the
+	// sim should stop as soon as the minimum iterations are done.
+	result := RunRaidSim(&proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Healer",
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_RestorationShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 30,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations:        10,
+			MaxIterations:     1000,
+			TargetStdevOfMean: 1,
+			RandomSeed:        100,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	if result.IterationsDone != 10 {
+		t.Fatalf("Expected the sim to stop after 10 iterations, got %d", result.IterationsDone)
+	}
+	if result.RaidDpsStdevOfMean != 0 {
+		t.Fatalf("Expected no error in the mean, got %0.4f", result.RaidDpsStdevOfMean)
+	}
+}
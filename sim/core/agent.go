@@ -21,8 +21,15 @@ type Agent interface {
 
 	// Updates the input Buffs to include raid-wide buffs provided by this Agent.
 	AddRaidBuffs(raidBuffs *proto.RaidBuffs)
+	// Updates the input Buffs to include raid-wide buffs provided by this Agent
+	// which are otherwise taken from the raid config. Only called when buffs
+	// come from the raid composition.
+	AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs)
 	// Updates the input Buffs to include party-wide buffs provided by this Agent.
 	AddPartyBuffs(partyBuffs *proto.PartyBuffs)
+	// Updates the input Debuffs to include the debuffs this Agent applies to
+	// enemies with its own spells.
+	AddRaidDebuffs(debuffs *proto.Debuffs)
 
 	// All talent stats / auras should be added within this callback. This makes sure
 	// talents are applied at the right time so we can calculate groups of stats.
@@ -147,9 +154,12 @@ var agentFactories = make(map[string]AgentFactory)
 var specSetters = make(map[string]SpecSetter)
 var configSpecs = make(map[string]proto.Spec)
 
+func specTypeName(player *proto.Player) string {
+	return reflect.TypeOf(player.GetSpec()).Elem().Name()
+}
+
 func PlayerProtoToSpec(player *proto.Player) proto.Spec {
-	typeName := reflect.TypeOf(player.GetSpec()).Elem().Name()
-	return configSpecs[typeName]
+	return configSpecs[specTypeName(player)]
 }
 
 func RegisterAgentFactory(emptyOptions interface{}, spec proto.Spec, factory AgentFactory, specSetter SpecSetter) {
@@ -166,7 +176,7 @@ func RegisterAgentFactory(emptyOptions interface{}, spec proto.Spec, factory Age
 
 // Constructs a new Agent.
 func NewAgent(party *Party, partyIndex int, player *proto.Player) Agent {
-	typeName := reflect.TypeOf(player.GetSpec()).Elem().Name()
+	typeName := specTypeName(player)
 
 	factory, ok := agentFactories[typeName]
 	if !ok {
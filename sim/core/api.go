@@ -23,6 +23,10 @@ func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
 	}
 }
 
+func ValidateGear(request *proto.ValidateGearRequest) *proto.ValidateGearResult {
+	return ValidateEquipment(request.Player)
+}
+
 /**
  * Returns stat weights and EP values, with standard deviations, for all stats.
  */
@@ -109,6 +113,111 @@ func RunRaidSimConcurrentAsync(request *proto.RaidSimRequest, progress chan *pro
 	}()
 }
 
+/**
+ * Sweeps one or two stats over a range and returns the DPS/HPS at every point.
+ */
+func RunStatPlot(request *proto.StatPlotRequest) *proto.StatPlotResult {
+	return runStatPlot(request, nil, simsignals.CreateSignals())
+}
+
+func RunStatPlotAsync(request *proto.StatPlotRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	runAsync(requestId, progress, func(signals simsignals.Signals) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalStatPlotResult: runStatPlot(request, progress, signals)}
+	}, func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalStatPlotResult: &proto.StatPlotResult{Error: errorOutcome}}
+	})
+}
+
+/**
+ * Searches for the best values of tunable constants in a player's rotation.
+ */
+func RunAPLTuning(request *proto.APLTuningRequest) *proto.APLTuningResult {
+	return runAPLTuning(request, nil, simsignals.CreateSignals())
+}
+
+func RunAPLTuningAsync(request *proto.APLTuningRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	runAsync(requestId, progress, func(signals simsignals.Signals) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalAplTuningResult: runAPLTuning(request, progress, signals)}
+	}, func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalAplTuningResult: &proto.APLTuningResult{Error: errorOutcome}}
+	})
+}
+
+/**
+ * Searches for the usage times of a tank's defensive cooldowns which minimize TMI or chance of death.
+ */
+func RunDefensivePlan(request *proto.DefensivePlanRequest) *proto.DefensivePlanResult {
+	return runDefensivePlan(request, nil, simsignals.CreateSignals())
+}
+
+func RunDefensivePlanAsync(request *proto.DefensivePlanRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	runAsync(requestId, progress, func(signals simsignals.Signals) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalDefensivePlanResult: runDefensivePlan(request, progress, signals)}
+	}, func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalDefensivePlanResult: &proto.DefensivePlanResult{Error: errorOutcome}}
+	})
+}
+
+/**
+ * Searches for the raid composition with the highest raid DPS from a pool of candidate players.
+ */
+func RunRaidCompOptimize(request *proto.RaidCompOptimizeRequest) *proto.RaidCompOptimizeResult {
+	return runRaidCompOptimize(request, nil, simsignals.CreateSignals())
+}
+
+func RunRaidCompOptimizeAsync(request *proto.RaidCompOptimizeRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	runAsync(requestId, progress, func(signals simsignals.Signals) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalRaidCompResult: runRaidCompOptimize(request, progress, signals)}
+	}, func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalRaidCompResult: &proto.RaidCompOptimizeResult{Error: errorOutcome}}
+	})
+}
+
+/**
+ * Runs an individual sim for every gear combination in the bulk settings and ranks the results.
+ */
+func RunBulkSim(request *proto.BulkSimRequest) *proto.BulkSimResult {
+	return runBulkSim(request, nil, simsignals.CreateSignals())
+}
+
+func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	runAsync(requestId, progress, func(signals simsignals.Signals) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalBulkResult: runBulkSim(request, progress, signals)}
+	}, func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalBulkResult: &proto.BulkSimResult{Error: errorOutcome}}
+	})
+}
+
+/**
+ * Picks reforges (and optionally gems) for the equipped gear which maximize the weighted stat value, respecting stat caps.
+ */
+func OptimizeReforges(request *proto.ReforgeOptimizeRequest) *proto.ReforgeOptimizeResult {
+	return optimizeReforges(request)
+}
+
+/**
+ * Runs a baseline and a variant raid sim with common random numbers and reports the differences between them.
+ */
+func CompareSims(request *proto.CompareSimsRequest) *proto.CompareSimsResult {
+	return runCompareSims(request, simsignals.CreateSignals())
+}
+
+// Runs a multi-sim API in the background, registered with the signal API so it
+// can be aborted. The result of run is sent as the final progress message.
+func runAsync(requestId string, progress chan *proto.ProgressMetrics, run func(signals simsignals.Signals) *proto.ProgressMetrics, errorMetrics func(errorOutcome *proto.ErrorOutcome) *proto.ProgressMetrics) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- errorMetrics(&proto.ErrorOutcome{
+			Message: "Couldn't register for signal API: " + err.Error(),
+		})
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		progress <- run(signals)
+	}()
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
@@ -46,6 +46,11 @@ type APLRotation struct {
 	prepullIdxMap      []int
 	priorityListIdxMap []int
 	groupListIdxMap    [][]int
+
+	config *proto.APLRotation
+
+	// Runtime statistics, only set when SimOptions.ProfileApl is enabled.
+	profiler *aplProfiler
 }
 
 type APLGroup struct {
@@ -111,6 +116,7 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 	groupsConfig := config.Groups
 	rotation := &APLRotation{
 		unit:                    unit,
+		config:                  config,
 		prepullValidations:      make([][]*proto.APLValidation, len(config.PrepullActions)),
 		priorityListValidations: make([][]*proto.APLValidation, len(config.PriorityList)),
 		groupListValidations:    make([][][]*proto.APLValidation, len(groupsConfig)),
@@ -383,6 +389,9 @@ func (rot *APLRotation) reset(sim *Simulation) {
 	rot.inLoop = false
 	rot.interruptChannelIf = nil
 	rot.allowChannelRecastOnInterrupt = false
+	if rot.profiler != nil {
+		rot.profiler.reset()
+	}
 	for _, action := range rot.allAPLActions() {
 		action.impl.Reset(sim)
 	}
@@ -414,7 +423,16 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	apl.unit.UpdatePosition(sim)
 	for nextAction := apl.getNextAction(sim); nextAction != nil; i, nextAction = i+1, apl.getNextAction(sim) {
 		if i > 1000 {
-			panic(fmt.Sprintf("[USER_ERROR] Infinite loop detected, current action:\n%s", nextAction))
+			err := &UserError{
+				Code:    proto.ErrorCode_ErrorCodeAplInfiniteLoop,
+				Unit:    apl.unit.protoReference(),
+				AplUuid: nextAction.uuid,
+				Message: fmt.Sprintf("Infinite loop detected, current action:\n%s", nextAction),
+			}
+			if apl.unit.Type == PlayerUnit {
+				err.FieldPath = playerFieldPath(apl.unit.Index, "rotation")
+			}
+			panic(err)
 		}
 
 		nextAction.Execute(sim)
@@ -9,6 +9,12 @@ import (
 type APLAction struct {
 	condition APLValue
 	impl      APLActionImpl
+
+	// UUID of the action, or of its condition for rotations which don't set one.
+	uuid *proto.UUID
+
+	// Only set for profiled top level and group actions.
+	profiler *aplActionProfiler
 }
 
 func (action *APLAction) Finalize(rot *APLRotation) {
@@ -24,10 +30,16 @@ func (action *APLAction) Finalize(rot *APLRotation) {
 }
 
 func (action *APLAction) IsReady(sim *Simulation) bool {
+	if action.profiler != nil {
+		return action.profiler.isReady(sim, action)
+	}
 	return (action.condition == nil || action.condition.GetBool(sim)) && action.impl.IsReady(sim)
 }
 
 func (action *APLAction) Execute(sim *Simulation) {
+	if action.profiler != nil {
+		action.profiler.recordExecution(sim)
+	}
 	action.impl.Execute(sim)
 }
 
@@ -150,6 +162,11 @@ func (rot *APLRotation) newAPLAction(config *proto.APLAction) *APLAction {
 	action := &APLAction{
 		condition: rot.coerceTo(rot.newAPLValue(config.Condition), proto.APLValueType_ValueTypeBool),
 		impl:      impl,
+		uuid:      config.GetUuid(),
+	}
+	// Older rotations only have a UUID on the condition.
+	if action.uuid == nil {
+		action.uuid = config.Condition.GetUuid()
 	}
 
 	return action
@@ -172,6 +189,8 @@ func (rot *APLRotation) newAPLActionImpl(config *proto.APLAction) APLActionImpl
 		return rot.newActionCastSpell(config.GetCastSpell())
 	case *proto.APLAction_CastFriendlySpell:
 		return rot.newActionCastFriendlySpell(config.GetCastFriendlySpell())
+	case *proto.APLAction_Interrupt:
+		return rot.newActionInterrupt(config.GetInterrupt())
 	case *proto.APLAction_ChannelSpell:
 		return rot.newActionChannelSpell(config.GetChannelSpell())
 	case *proto.APLAction_Multidot:
@@ -505,3 +505,51 @@ func (action *APLActionAutocastOtherCooldowns) PostFinalize(rot *APLRotation) {
 		rot.ValidationMessage(proto.LogLevel_Information, "%s will cast the following spells: %s", action, StringFromActionIDs(actionIDs))
 	}
 }
+
+type APLActionInterrupt struct {
+	defaultAPLActionImpl
+	spells []*Spell
+	target UnitReference
+}
+
+func (rot *APLRotation) newActionInterrupt(config *proto.APLActionInterrupt) APLActionImpl {
+	target := rot.GetTargetUnit(config.Target)
+	if target.Get() == nil {
+		return nil
+	}
+	spells := FilterSlice(rot.unit.Spellbook, func(spell *Spell) bool {
+		return spell.Flags.Matches(SpellFlagInterrupt)
+	})
+	if len(spells) == 0 {
+		rot.ValidationMessage(proto.LogLevel_Warning, "%s has no interrupt spell", rot.unit.Label)
+		return nil
+	}
+	return &APLActionInterrupt{
+		spells: spells,
+		target: target,
+	}
+}
+
+// Returns the first interrupt spell which can be cast, if the target is
+// casting something which can be interrupted.
+func (action *APLActionInterrupt) readySpell(sim *Simulation) *Spell {
+	target := action.target.Get()
+	if !target.IsCastingInterruptible(sim) {
+		return nil
+	}
+	for _, spell := range action.spells {
+		if spell.CanCast(sim, target) {
+			return spell
+		}
+	}
+	return nil
+}
+func (action *APLActionInterrupt) IsReady(sim *Simulation) bool {
+	return action.readySpell(sim) != nil
+}
+func (action *APLActionInterrupt) Execute(sim *Simulation) {
+	action.readySpell(sim).Cast(sim, action.target.Get())
+}
+func (action *APLActionInterrupt) String() string {
+	return "Interrupt"
+}
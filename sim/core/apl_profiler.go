@@ -0,0 +1,225 @@
+package core
+
+import (
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Why an APL action wasn't ready when it was checked.
+type aplBlockReason int
+
+const (
+	aplBlockedByCondition aplBlockReason = iota
+	aplBlockedByCooldown
+	aplBlockedByResources
+	aplBlockedByGCD
+	aplBlockedByCasting
+	aplBlockedByOther
+	numAPLBlockReasons
+)
+
+// Runtime statistics for a single top level or group action, enabled with
+// SimOptions.ProfileApl.
+type aplActionProfiler struct {
+	uuid  *proto.UUID
+	spell *Spell
+
+	// State for the current iteration, negative when unset.
+	lastExecutionAt time.Duration
+	poolingSince    time.Duration
+
+	// Totals across all iterations.
+	evaluations           int64
+	conditionTrue         int64
+	executions            int64
+	blocked               [numAPLBlockReasons]int64
+	timeBetweenExecutions time.Duration
+	numIntervals          int64
+	poolingTime           time.Duration
+}
+
+type aplProfiler struct {
+	priorityList []*aplActionProfiler
+	groups       [][]*aplActionProfiler
+}
+
+func newAPLActionProfiler(action *APLAction) *aplActionProfiler {
+	profiler := &aplActionProfiler{
+		uuid:            action.uuid,
+		lastExecutionAt: -1,
+		poolingSince:    -1,
+	}
+
+	switch impl := action.impl.(type) {
+	case *APLActionCastSpell:
+		profiler.spell = impl.spell
+	case *APLActionCastFriendlySpell:
+		profiler.spell = impl.spell
+	case *APLActionChannelSpell:
+		profiler.spell = impl.spell
+	}
+
+	action.profiler = profiler
+	return profiler
+}
+
+// Attaches profilers to the priority list and group actions. Must be called
+// before the first iteration.
+func (rot *APLRotation) enableProfiling() {
+	if rot == nil || rot.profiler != nil {
+		return
+	}
+
+	config := rot.config
+	rot.profiler = &aplProfiler{
+		priorityList: make([]*aplActionProfiler, len(config.PriorityList)),
+		groups:       make([][]*aplActionProfiler, len(rot.groups)),
+	}
+	for i, action := range rot.priorityList {
+		configIdx := rot.priorityListIdxMap[i]
+		rot.profiler.priorityList[configIdx] = newAPLActionProfiler(action)
+	}
+
+	for groupIdx, group := range rot.groups {
+		configIdx := slices.IndexFunc(config.Groups, func(groupConfig *proto.APLGroup) bool { return groupConfig.Name == group.name })
+		if configIdx == -1 {
+			continue
+		}
+		groupConfig := config.Groups[configIdx]
+		rot.profiler.groups[groupIdx] = make([]*aplActionProfiler, len(groupConfig.Actions))
+		for i, action := range group.actions {
+			configIdx := rot.groupListIdxMap[groupIdx][i]
+			rot.profiler.groups[groupIdx][configIdx] = newAPLActionProfiler(action)
+		}
+	}
+}
+
+func (raid *Raid) enableAPLProfiling() {
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			character := player.GetCharacter()
+			character.Rotation.enableProfiling()
+			for _, pet := range character.Pets {
+				pet.Rotation.enableProfiling()
+			}
+		}
+	}
+}
+
+func (profiler *aplProfiler) reset() {
+	for _, actionProfiler := range profiler.allActions() {
+		actionProfiler.lastExecutionAt = -1
+		actionProfiler.poolingSince = -1
+	}
+}
+
+func (profiler *aplProfiler) allActions() []*aplActionProfiler {
+	actions := FilterSlice(profiler.priorityList, func(actionProfiler *aplActionProfiler) bool { return actionProfiler != nil })
+	for _, group := range profiler.groups {
+		actions = append(actions, FilterSlice(group, func(actionProfiler *aplActionProfiler) bool { return actionProfiler != nil })...)
+	}
+	return actions
+}
+
+// Same as APLAction.IsReady, but records the result.
+func (profiler *aplActionProfiler) isReady(sim *Simulation, action *APLAction) bool {
+	profiler.evaluations++
+
+	if action.condition != nil && !action.condition.GetBool(sim) {
+		profiler.blocked[aplBlockedByCondition]++
+		profiler.poolingSince = -1
+		return false
+	}
+	profiler.conditionTrue++
+
+	if action.impl.IsReady(sim) {
+		return true
+	}
+
+	reason := profiler.blockReason(sim)
+	profiler.blocked[reason]++
+	if reason != aplBlockedByResources {
+		// Only time spent continuously waiting on resources counts as pooling.
+		profiler.poolingSince = -1
+	} else if profiler.poolingSince < 0 {
+		profiler.poolingSince = sim.CurrentTime
+	}
+	return false
+}
+
+func (profiler *aplActionProfiler) blockReason(sim *Simulation) aplBlockReason {
+	spell := profiler.spell
+	if spell == nil {
+		return aplBlockedByOther
+	}
+
+	switch {
+	case !BothTimersReady(spell.CD.Timer, spell.SharedCD.Timer, sim) || (spell.MaxCharges > 0 && spell.charges == 0):
+		return aplBlockedByCooldown
+	case spell.Cost != nil && !spell.Cost.MeetsRequirement(sim, spell):
+		return aplBlockedByResources
+	case spell.DefaultCast.GCD > 0 && !spell.Unit.GCD.IsReady(sim):
+		return aplBlockedByGCD
+	case spell.Unit.Hardcast.Expires > sim.CurrentTime || spell.Unit.IsChanneling():
+		return aplBlockedByCasting
+	default:
+		return aplBlockedByOther
+	}
+}
+
+func (profiler *aplActionProfiler) recordExecution(sim *Simulation) {
+	profiler.executions++
+
+	if profiler.lastExecutionAt >= 0 {
+		profiler.timeBetweenExecutions += sim.CurrentTime - profiler.lastExecutionAt
+		profiler.numIntervals++
+	}
+	profiler.lastExecutionAt = sim.CurrentTime
+
+	if profiler.poolingSince >= 0 {
+		profiler.poolingTime += sim.CurrentTime - profiler.poolingSince
+		profiler.poolingSince = -1
+	}
+}
+
+func (profiler *aplActionProfiler) toProto(numIterations float64) *proto.APLActionProfile {
+	if profiler == nil {
+		return &proto.APLActionProfile{}
+	}
+
+	actionProfile := &proto.APLActionProfile{
+		Uuid:                  profiler.uuid,
+		EvaluationsAvg:        float64(profiler.evaluations) / numIterations,
+		ConditionTrueAvg:      float64(profiler.conditionTrue) / numIterations,
+		ExecutionsAvg:         float64(profiler.executions) / numIterations,
+		BlockedByConditionAvg: float64(profiler.blocked[aplBlockedByCondition]) / numIterations,
+		BlockedByCooldownAvg:  float64(profiler.blocked[aplBlockedByCooldown]) / numIterations,
+		BlockedByResourcesAvg: float64(profiler.blocked[aplBlockedByResources]) / numIterations,
+		BlockedByGcdAvg:       float64(profiler.blocked[aplBlockedByGCD]) / numIterations,
+		BlockedByCastingAvg:   float64(profiler.blocked[aplBlockedByCasting]) / numIterations,
+		BlockedByOtherAvg:     float64(profiler.blocked[aplBlockedByOther]) / numIterations,
+	}
+	if profiler.numIntervals > 0 {
+		actionProfile.TimeBetweenExecutionsAvg = profiler.timeBetweenExecutions.Seconds() / float64(profiler.numIntervals)
+	}
+	if profiler.executions > 0 {
+		actionProfile.PoolingTimeAvg = profiler.poolingTime.Seconds() / float64(profiler.executions)
+	}
+	return actionProfile
+}
+
+func (profiler *aplProfiler) toProto(numIterations int) *proto.APLProfile {
+	n := float64(max(numIterations, 1))
+	return &proto.APLProfile{
+		PriorityList: MapSlice(profiler.priorityList, func(actionProfiler *aplActionProfiler) *proto.APLActionProfile {
+			return actionProfiler.toProto(n)
+		}),
+		Groups: MapSlice(profiler.groups, func(group []*aplActionProfiler) *proto.APLGroupProfile {
+			return &proto.APLGroupProfile{Actions: MapSlice(group, func(actionProfiler *aplActionProfiler) *proto.APLActionProfile {
+				return actionProfiler.toProto(n)
+			})}
+		}),
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLProfiler(t *testing.T) {
+	rotation, err := APLRotationFromText(`
+		type TypeAPL
+		action hidden wait(5s)
+		action wait(2s) if current_time >= 10s
+		action wait(1s)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse rotation: %s", err)
+	}
+	// Profiles use the action UUID, or the condition UUID for actions without one.
+	rotation.PriorityList[1].Action.Condition.Uuid = &proto.UUID{Value: "conditional"}
+	rotation.PriorityList[2].Action.Uuid = &proto.UUID{Value: "filler"}
+
+	result := RunRaidSim(&proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Healer",
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_RestorationShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Rotation:  rotation,
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 30,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 10,
+			RandomSeed: 100,
+			ProfileApl: true,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	profile := result.RaidMetrics.Parties[0].Players[0].AplProfile
+	if profile == nil || len(profile.PriorityList) != 3 {
+		t.Fatalf("Expected a profile entry for each priority list item, got %v", profile)
+	}
+	if hidden := profile.PriorityList[0]; hidden.EvaluationsAvg != 0 {
+		t.Fatalf("Expected hidden items to be left empty, got %v", hidden)
+	}
+
+	conditional := profile.PriorityList[1]
+	if conditional.Uuid.GetValue() != "conditional" {
+		t.Fatalf("Expected the conditional wait to use its condition UUID, got %v", conditional.Uuid)
+	}
+	if conditional.BlockedByConditionAvg == 0 || conditional.ExecutionsAvg == 0 {
+		t.Fatalf("Expected the conditional wait to be blocked early and executed later, got %v", conditional)
+	}
+	if conditional.EvaluationsAvg != conditional.ConditionTrueAvg+conditional.BlockedByConditionAvg {
+		t.Fatalf("Expected every evaluation to either pass or fail the condition, got %v", conditional)
+	}
+	if !WithinToleranceFloat64(2, conditional.TimeBetweenExecutionsAvg, 0.001) {
+		t.Fatalf("Expected 2s between conditional waits, got %0.3f", conditional.TimeBetweenExecutionsAvg)
+	}
+
+	filler := profile.PriorityList[2]
+	if filler.Uuid.GetValue() != "filler" {
+		t.Fatalf("Expected the filler wait to use its action UUID, got %v", filler.Uuid)
+	}
+	if !WithinToleranceFloat64(1, filler.TimeBetweenExecutionsAvg, 0.001) {
+		t.Fatalf("Expected 1s between filler waits, got %0.3f", filler.TimeBetweenExecutionsAvg)
+	}
+
+	// Profiling is opt-in.
+	if metrics := RunRaidSim(&proto.RaidSimRequest{
+		Raid:       SinglePlayerRaidProto(&proto.Player{Class: proto.Class_ClassShaman, Spec: &proto.Player_RestorationShaman{}, Equipment: &proto.EquipmentSpec{}, Rotation: rotation}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter:  &proto.Encounter{Targets: []*proto.Target{{Level: 93}}, Duration: 30},
+		SimOptions: &proto.SimOptions{Iterations: 1},
+	}); metrics.Error != nil || metrics.RaidMetrics.Parties[0].Players[0].AplProfile != nil {
+		t.Fatalf("Expected no profile without SimOptions.ProfileApl")
+	}
+}
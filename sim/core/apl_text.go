@@ -0,0 +1,1043 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Compact text form of an APLRotation, which round-trips losslessly through
+// APLRotationFromText and FormatAPLRotation. For example:
+//
+//	type TypeAPL
+//	variable "Execute" = is_execute_phase(threshold=E20)
+//	prepull at -1s: cast_spell(other:OtherActionPotion)
+//	action cast_spell(34914) if !dot_is_active(34914) && current_time < 10s
+//	action hidden notes "Filler" cast_spell(15407)
+//	group aoe {
+//		action channel_spell(48045, interrupt_if=gcd_is_ready, allow_recast=true)
+//	}
+//
+// Actions and values are written as calls named after their proto field, with
+// the lowest numbered field passed positionally and the rest by name. Values
+// also support the operators || && == != < <= > >= + - * / and !, constants
+// are written bare when they look like a number, duration or percentage, and
+// ActionIDs are written as 12345 (spell), item:12345 or other:OtherActionName,
+// with an optional #tag. Any other message is written as {field=value, ...}.
+//
+// Comments start with // and run to the end of the line. They aren't part of
+// the rotation and are dropped by the formatter, so use notes for anything
+// which should be kept.
+
+var aplTextLexer = lexer.MustSimple([]lexer.SimpleRule{
+	{Name: "Comment", Pattern: `//[^\n]*`},
+	{Name: "Whitespace", Pattern: `\s+`},
+	{Name: "String", Pattern: `"(\\.|[^"\\])*"`},
+	{Name: "ActionIDPrefix", Pattern: `(spell|item|other):`},
+	{Name: "Keyword", Pattern: `(type|simple|variable|prepull|action|group|hidden|notes|at|if)\b`},
+	{Name: "Number", Pattern: `(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?(ms|s|m|h|%)?`},
+	{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+	{Name: "Operator", Pattern: `==|!=|<=|>=|&&|\|\||[-+*/<>!=(){}\[\],:#@]`},
+})
+
+var aplTextParser = participle.MustBuild[aplTextFile](
+	participle.Lexer(aplTextLexer),
+	participle.Elide("Whitespace", "Comment"),
+	participle.Unquote("String"),
+	participle.UseLookahead(2),
+)
+
+var aplTextKeywords = []string{"type", "simple", "variable", "prepull", "action", "group", "hidden", "notes", "at", "if"}
+
+var (
+	aplTextIdent     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	aplTextBareConst = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?(ms|s|m|h|%)?$`)
+)
+
+var (
+	aplValueDescriptor  = (&proto.APLValue{}).ProtoReflect().Descriptor()
+	aplActionDescriptor = (&proto.APLAction{}).ProtoReflect().Descriptor()
+	actionIDDescriptor  = (&proto.ActionID{}).ProtoReflect().Descriptor()
+)
+
+type aplTextFile struct {
+	Statements []*aplTextStatement `parser:"@@*"`
+}
+
+type aplTextStatement struct {
+	Pos lexer.Position
+
+	Type     *aplTextType     `parser:"  @@"`
+	Simple   *aplTextExpr     `parser:"| 'simple' @@"`
+	Variable *aplTextVariable `parser:"| @@"`
+	Prepull  *aplTextPrepull  `parser:"| @@"`
+	Action   *aplTextListItem `parser:"| @@"`
+	Group    *aplTextGroup    `parser:"| @@"`
+}
+
+type aplTextType struct {
+	Pos lexer.Position
+
+	Name string `parser:"'type' @(Ident | Number)"`
+}
+
+type aplTextVariable struct {
+	Name  string       `parser:"'variable' @(Ident | String)"`
+	Value *aplTextExpr `parser:"( '=' @@ )?"`
+}
+
+type aplTextPrepull struct {
+	Hidden bool         `parser:"'prepull' @'hidden'?"`
+	DoAt   *aplTextExpr `parser:"( 'at' @@ )?"`
+	Action *aplTextExpr `parser:"':' @@?"`
+}
+
+type aplTextListItem struct {
+	Hidden bool         `parser:"'action' @'hidden'?"`
+	Notes  *string      `parser:"( 'notes' @String )?"`
+	Action *aplTextExpr `parser:"@@?"`
+}
+
+type aplTextGroup struct {
+	Name       string              `parser:"'group' @(Ident | String) '{'"`
+	Statements []*aplTextStatement `parser:"@@* '}'"`
+}
+
+// A value, or an action with an optional condition.
+type aplTextExpr struct {
+	Pos lexer.Position
+
+	Value     *aplTextOr `parser:"@@"`
+	Condition *aplTextOr `parser:"( 'if' @@ )?"`
+}
+
+type aplTextOr struct {
+	Pos lexer.Position
+
+	Terms []*aplTextAnd `parser:"@@ ( '||' @@ )*"`
+}
+
+type aplTextAnd struct {
+	Pos lexer.Position
+
+	Terms []*aplTextCmp `parser:"@@ ( '&&' @@ )*"`
+}
+
+type aplTextCmp struct {
+	Pos lexer.Position
+
+	Lhs *aplTextSum `parser:"@@"`
+	Op  string      `parser:"( @('==' | '!=' | '<=' | '>=' | '<' | '>')"`
+	Rhs *aplTextSum `parser:"  @@ )?"`
+}
+
+type aplTextSum struct {
+	Pos lexer.Position
+
+	First *aplTextProduct   `parser:"@@"`
+	Rest  []*aplTextSumTerm `parser:"@@*"`
+}
+
+type aplTextSumTerm struct {
+	Pos lexer.Position
+
+	Op   string          `parser:"@('+' | '-')"`
+	Term *aplTextProduct `parser:"@@"`
+}
+
+type aplTextProduct struct {
+	Pos lexer.Position
+
+	First *aplTextUnary         `parser:"@@"`
+	Rest  []*aplTextProductTerm `parser:"@@*"`
+}
+
+type aplTextProductTerm struct {
+	Pos lexer.Position
+
+	Op   string        `parser:"@('*' | '/')"`
+	Term *aplTextUnary `parser:"@@"`
+}
+
+type aplTextUnary struct {
+	Pos lexer.Position
+
+	Not     *aplTextUnary   `parser:"  '!' @@"`
+	Primary *aplTextPrimary `parser:"| @@"`
+}
+
+type aplTextPrimary struct {
+	Pos lexer.Position
+
+	Number   *aplTextNumber   `parser:"( @@"`
+	ActionID *aplTextActionID `parser:"| @@"`
+	String   *string          `parser:"| @String"`
+	Paren    *aplTextOr       `parser:"| '(' @@ ')'"`
+	Message  *aplTextArgs     `parser:"| '{' @@ '}'"`
+	List     *aplTextList     `parser:"| '[' @@ ']'"`
+	Call     *aplTextCall     `parser:"| @@ )"`
+	UUID     *string          `parser:"( '@' @String )?"`
+}
+
+type aplTextNumber struct {
+	Value string  `parser:"@('-'? Number)"`
+	Tag   *string `parser:"( '#' @('-'? Number) )?"`
+}
+
+type aplTextActionID struct {
+	Kind string  `parser:"@ActionIDPrefix"`
+	ID   string  `parser:"@('-'? Number | Ident)"`
+	Tag  *string `parser:"( '#' @('-'? Number) )?"`
+}
+
+type aplTextList struct {
+	Elems []*aplTextExpr `parser:"( @@ ( ',' @@ )* )?"`
+}
+
+type aplTextCall struct {
+	Name string       `parser:"@Ident"`
+	Args *aplTextArgs `parser:"( '(' @@ ')' )?"`
+}
+
+type aplTextArgs struct {
+	Args []*aplTextArg `parser:"( @@ ( ',' @@ )* )?"`
+}
+
+type aplTextArg struct {
+	Pos lexer.Position
+
+	Name  *string      `parser:"( @(Ident | Keyword) '=' )?"`
+	Value *aplTextExpr `parser:"@@"`
+}
+
+// Returns the primary expression if there are no operators, otherwise nil.
+func (or *aplTextOr) primary() *aplTextPrimary {
+	if len(or.Terms) != 1 || len(or.Terms[0].Terms) != 1 {
+		return nil
+	}
+	cmp := or.Terms[0].Terms[0]
+	if cmp.Rhs != nil || len(cmp.Lhs.Rest) != 0 || len(cmp.Lhs.First.Rest) != 0 {
+		return nil
+	}
+	return cmp.Lhs.First.First.Primary
+}
+
+func aplTextError(pos lexer.Position, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", pos, fmt.Sprintf(format, args...))
+}
+
+// Parses a rotation written in the APL text format. Errors include the line
+// and column of the offending token.
+func APLRotationFromText(text string) (*proto.APLRotation, error) {
+	file, err := aplTextParser.ParseString("", text)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &proto.APLRotation{}
+	for _, statement := range file.Statements {
+		switch {
+		case statement.Type != nil:
+			value, err := aplTextEnumValue(statement.Type.Pos, proto.APLRotation_TypeUnknown.Descriptor(), statement.Type.Name)
+			if err != nil {
+				return nil, err
+			}
+			rotation.Type = proto.APLRotation_Type(value)
+		case statement.Simple != nil:
+			rotation.Simple = &proto.SimpleRotation{}
+			if err := aplTextToMessage(statement.Simple, rotation.Simple.ProtoReflect()); err != nil {
+				return nil, err
+			}
+		case statement.Variable != nil:
+			variable, err := statement.Variable.toProto()
+			if err != nil {
+				return nil, err
+			}
+			rotation.ValueVariables = append(rotation.ValueVariables, variable)
+		case statement.Prepull != nil:
+			prepullAction := &proto.APLPrepullAction{Hide: statement.Prepull.Hidden}
+			if statement.Prepull.DoAt != nil {
+				prepullAction.DoAtValue = &proto.APLValue{}
+				if err := aplTextToMessage(statement.Prepull.DoAt, prepullAction.DoAtValue.ProtoReflect()); err != nil {
+					return nil, err
+				}
+			}
+			if statement.Prepull.Action != nil {
+				prepullAction.Action = &proto.APLAction{}
+				if err := aplTextToMessage(statement.Prepull.Action, prepullAction.Action.ProtoReflect()); err != nil {
+					return nil, err
+				}
+			}
+			rotation.PrepullActions = append(rotation.PrepullActions, prepullAction)
+		case statement.Action != nil:
+			listItem, err := statement.Action.toProto()
+			if err != nil {
+				return nil, err
+			}
+			rotation.PriorityList = append(rotation.PriorityList, listItem)
+		case statement.Group != nil:
+			group := &proto.APLGroup{Name: statement.Group.Name}
+			for _, groupStatement := range statement.Group.Statements {
+				switch {
+				case groupStatement.Variable != nil:
+					variable, err := groupStatement.Variable.toProto()
+					if err != nil {
+						return nil, err
+					}
+					group.Variables = append(group.Variables, variable)
+				case groupStatement.Action != nil:
+					listItem, err := groupStatement.Action.toProto()
+					if err != nil {
+						return nil, err
+					}
+					group.Actions = append(group.Actions, listItem)
+				default:
+					return nil, aplTextError(groupStatement.Pos, "groups can only contain actions and variables")
+				}
+			}
+			rotation.Groups = append(rotation.Groups, group)
+		}
+	}
+
+	return rotation, nil
+}
+
+func (variable *aplTextVariable) toProto() (*proto.APLValueVariable, error) {
+	valueVariable := &proto.APLValueVariable{Name: variable.Name}
+	if variable.Value != nil {
+		valueVariable.Value = &proto.APLValue{}
+		if err := aplTextToMessage(variable.Value, valueVariable.Value.ProtoReflect()); err != nil {
+			return nil, err
+		}
+	}
+	return valueVariable, nil
+}
+
+func (item *aplTextListItem) toProto() (*proto.APLListItem, error) {
+	listItem := &proto.APLListItem{Hide: item.Hidden}
+	if item.Notes != nil {
+		listItem.Notes = *item.Notes
+	}
+	if item.Action != nil {
+		listItem.Action = &proto.APLAction{}
+		if err := aplTextToMessage(item.Action, listItem.Action.ProtoReflect()); err != nil {
+			return nil, err
+		}
+	}
+	return listItem, nil
+}
+
+// Fills msg, which must be empty, from an expression.
+func aplTextToMessage(expr *aplTextExpr, msg protoreflect.Message) error {
+	if msg.Descriptor() == aplActionDescriptor {
+		if err := aplTextOrToMessage(expr.Value, msg); err != nil {
+			return err
+		}
+		if expr.Condition != nil {
+			condition, err := aplTextValue(expr.Condition)
+			if err != nil {
+				return err
+			}
+			action := msg.Interface().(*proto.APLAction)
+			if action.Condition != nil {
+				return aplTextError(expr.Condition.Pos, "action already has a condition")
+			}
+			action.Condition = condition
+		}
+		return nil
+	}
+
+	if expr.Condition != nil {
+		return aplTextError(expr.Condition.Pos, "unexpected 'if', only actions can have conditions")
+	}
+	return aplTextOrToMessage(expr.Value, msg)
+}
+
+func aplTextOrToMessage(or *aplTextOr, msg protoreflect.Message) error {
+	if msg.Descriptor() == aplValueDescriptor {
+		value, err := aplTextValue(or)
+		if err != nil {
+			return err
+		}
+		googleProto.Merge(msg.Interface(), value)
+		return nil
+	}
+
+	primary := or.primary()
+	if primary == nil {
+		return aplTextError(or.Pos, "operators can only be used in values, not %s", msg.Descriptor().Name())
+	}
+	return aplTextPrimaryToMessage(primary, msg)
+}
+
+func aplTextPrimaryToMessage(primary *aplTextPrimary, msg protoreflect.Message) error {
+	desc := msg.Descriptor()
+	if primary.UUID != nil && desc != aplValueDescriptor {
+		return aplTextError(primary.Pos, "only values can have a uuid")
+	}
+
+	switch {
+	case primary.Paren != nil:
+		return aplTextOrToMessage(primary.Paren, msg)
+	case primary.Message != nil:
+		return aplTextFillArgs(primary.Message, msg)
+	}
+
+	switch desc {
+	case actionIDDescriptor:
+		return aplTextActionIDToMessage(primary, msg.Interface().(*proto.ActionID))
+	case aplValueDescriptor:
+		value := msg.Interface().(*proto.APLValue)
+		switch {
+		case primary.Number != nil:
+			if primary.Number.Tag != nil {
+				return aplTextError(primary.Pos, "unexpected tag on a constant")
+			}
+			value.Value = &proto.APLValue_Const{Const: &proto.APLValueConst{Val: primary.Number.Value}}
+			return nil
+		case primary.String != nil:
+			value.Value = &proto.APLValue_Const{Const: &proto.APLValueConst{Val: *primary.String}}
+			return nil
+		case primary.Call != nil && primary.Call.Args == nil && (primary.Call.Name == "true" || primary.Call.Name == "false"):
+			value.Value = &proto.APLValue_Const{Const: &proto.APLValueConst{Val: primary.Call.Name}}
+			return nil
+		}
+	}
+
+	if primary.Call == nil || (desc != aplValueDescriptor && desc != aplActionDescriptor) {
+		return aplTextError(primary.Pos, "expected {...} for %s", desc.Name())
+	}
+
+	field := desc.Fields().ByName(protoreflect.Name(primary.Call.Name))
+	if field == nil || field.ContainingOneof() == nil || field.Message() == nil {
+		return aplTextError(primary.Pos, "unknown %s %q", desc.Name(), primary.Call.Name)
+	}
+	if primary.Call.Args == nil {
+		msg.Set(field, protoreflect.ValueOfMessage(msg.NewField(field).Message()))
+		return nil
+	}
+	return aplTextFillArgs(primary.Call.Args, msg.Mutable(field).Message())
+}
+
+func aplTextActionIDToMessage(primary *aplTextPrimary, actionID *proto.ActionID) error {
+	var kind, id string
+	var tag *string
+	switch {
+	case primary.Number != nil:
+		kind, id, tag = "spell:", primary.Number.Value, primary.Number.Tag
+	case primary.ActionID != nil:
+		kind, id, tag = primary.ActionID.Kind, primary.ActionID.ID, primary.ActionID.Tag
+	default:
+		return aplTextError(primary.Pos, "expected an action ID")
+	}
+
+	if kind == "other:" {
+		value, err := aplTextEnumValue(primary.Pos, proto.OtherAction_OtherActionNone.Descriptor(), id)
+		if err != nil {
+			return err
+		}
+		actionID.RawId = &proto.ActionID_OtherId{OtherId: proto.OtherAction(value)}
+	} else {
+		value, err := strconv.ParseInt(id, 10, 32)
+		if err != nil {
+			return aplTextError(primary.Pos, "invalid action ID %q", id)
+		}
+		if kind == "item:" {
+			actionID.RawId = &proto.ActionID_ItemId{ItemId: int32(value)}
+		} else {
+			actionID.RawId = &proto.ActionID_SpellId{SpellId: int32(value)}
+		}
+	}
+
+	if tag != nil {
+		value, err := strconv.ParseInt(*tag, 10, 32)
+		if err != nil {
+			return aplTextError(primary.Pos, "invalid tag %q", *tag)
+		}
+		actionID.Tag = int32(value)
+	}
+	return nil
+}
+
+// Fills the fields of msg from call arguments. Positional arguments go to the
+// lowest numbered field, all of them if that field is repeated.
+func aplTextFillArgs(args *aplTextArgs, msg protoreflect.Message) error {
+	desc := msg.Descriptor()
+	fields := aplTextSortedFields(desc)
+
+	for i, arg := range args.Args {
+		var field protoreflect.FieldDescriptor
+		if arg.Name != nil {
+			field = desc.Fields().ByName(protoreflect.Name(*arg.Name))
+			if field == nil {
+				return aplTextError(arg.Pos, "unknown field %q in %s", *arg.Name, desc.Name())
+			}
+		} else {
+			if len(fields) == 0 || (i > 0 && !(fields[0].IsList() && args.Args[i-1].Name == nil)) {
+				return aplTextError(arg.Pos, "unexpected positional argument, use field=value")
+			}
+			field = fields[0]
+			if field.IsList() {
+				if err := aplTextAppend(arg.Value, msg.Mutable(field).List(), field); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if msg.Has(field) {
+			return aplTextError(arg.Pos, "duplicate field %q in %s", field.Name(), desc.Name())
+		}
+		if oneof := field.ContainingOneof(); oneof != nil && msg.WhichOneof(oneof) != nil {
+			return aplTextError(arg.Pos, "%q conflicts with %q in %s", field.Name(), msg.WhichOneof(oneof).Name(), desc.Name())
+		}
+		if err := aplTextSetField(arg.Value, msg, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func aplTextSortedFields(desc protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	fields := make([]protoreflect.FieldDescriptor, desc.Fields().Len())
+	for i := range fields {
+		fields[i] = desc.Fields().Get(i)
+	}
+	slices.SortFunc(fields, func(a, b protoreflect.FieldDescriptor) int {
+		return int(a.Number() - b.Number())
+	})
+	return fields
+}
+
+func aplTextSetField(expr *aplTextExpr, msg protoreflect.Message, field protoreflect.FieldDescriptor) error {
+	switch {
+	case field.IsMap():
+		return aplTextError(expr.Pos, "map field %q is not supported", field.Name())
+	case field.IsList():
+		primary := expr.Value.primary()
+		if expr.Condition != nil || primary == nil || primary.List == nil || primary.UUID != nil {
+			return aplTextError(expr.Pos, "expected [...] for %q", field.Name())
+		}
+		list := msg.Mutable(field).List()
+		for _, elem := range primary.List.Elems {
+			if err := aplTextAppend(elem, list, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	case field.Message() != nil:
+		fieldMsg := msg.NewField(field).Message()
+		if err := aplTextToMessage(expr, fieldMsg); err != nil {
+			return err
+		}
+		msg.Set(field, protoreflect.ValueOfMessage(fieldMsg))
+		return nil
+	default:
+		value, err := aplTextScalar(expr, field)
+		if err != nil {
+			return err
+		}
+		msg.Set(field, value)
+		return nil
+	}
+}
+
+func aplTextAppend(expr *aplTextExpr, list protoreflect.List, field protoreflect.FieldDescriptor) error {
+	if field.Message() != nil {
+		elem := list.NewElement()
+		if err := aplTextToMessage(expr, elem.Message()); err != nil {
+			return err
+		}
+		list.Append(elem)
+		return nil
+	}
+
+	value, err := aplTextScalar(expr, field)
+	if err != nil {
+		return err
+	}
+	list.Append(value)
+	return nil
+}
+
+func aplTextScalar(expr *aplTextExpr, field protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	primary := expr.Value.primary()
+	if expr.Condition != nil || primary == nil || primary.UUID != nil {
+		return protoreflect.Value{}, aplTextError(expr.Pos, "expected a %s for %q", field.Kind(), field.Name())
+	}
+
+	var ident, number string
+	switch {
+	case primary.Call != nil && primary.Call.Args == nil:
+		ident = primary.Call.Name
+	case primary.Number != nil && primary.Number.Tag == nil:
+		number = primary.Number.Value
+	}
+	invalid := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, aplTextError(primary.Pos, "expected a %s for %q", field.Kind(), field.Name())
+	}
+
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		if primary.String == nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfString(*primary.String), nil
+	case protoreflect.BoolKind:
+		if ident != "true" && ident != "false" {
+			return invalid()
+		}
+		return protoreflect.ValueOfBool(ident == "true"), nil
+	case protoreflect.EnumKind:
+		if ident == "" {
+			ident = number
+		}
+		value, err := aplTextEnumValue(primary.Pos, field.Enum(), ident)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfEnum(value), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		value, err := strconv.ParseInt(number, 10, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfInt32(int32(value)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		value, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfInt64(value), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		value, err := strconv.ParseUint(number, 10, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfUint32(uint32(value)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		value, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfUint64(value), nil
+	case protoreflect.FloatKind:
+		value, err := strconv.ParseFloat(number, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfFloat32(float32(value)), nil
+	case protoreflect.DoubleKind:
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfFloat64(value), nil
+	}
+	return protoreflect.Value{}, aplTextError(primary.Pos, "%s field %q is not supported", field.Kind(), field.Name())
+}
+
+func aplTextEnumValue(pos lexer.Position, enum protoreflect.EnumDescriptor, name string) (protoreflect.EnumNumber, error) {
+	if value := enum.Values().ByName(protoreflect.Name(name)); value != nil {
+		return value.Number(), nil
+	}
+	if number, err := strconv.ParseInt(name, 10, 32); err == nil {
+		return protoreflect.EnumNumber(number), nil
+	}
+	return 0, aplTextError(pos, "unknown %s %q", enum.Name(), name)
+}
+
+func aplTextValue(or *aplTextOr) (*proto.APLValue, error) {
+	if len(or.Terms) == 1 {
+		return aplTextAndValue(or.Terms[0])
+	}
+	vals := make([]*proto.APLValue, len(or.Terms))
+	for i, term := range or.Terms {
+		val, err := aplTextAndValue(term)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}, nil
+}
+
+func aplTextAndValue(and *aplTextAnd) (*proto.APLValue, error) {
+	if len(and.Terms) == 1 {
+		return aplTextCmpValue(and.Terms[0])
+	}
+	vals := make([]*proto.APLValue, len(and.Terms))
+	for i, term := range and.Terms {
+		val, err := aplTextCmpValue(term)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}, nil
+}
+
+var aplTextCompareOps = map[string]proto.APLValueCompare_ComparisonOperator{
+	"==": proto.APLValueCompare_OpEq,
+	"!=": proto.APLValueCompare_OpNe,
+	"<":  proto.APLValueCompare_OpLt,
+	"<=": proto.APLValueCompare_OpLe,
+	">":  proto.APLValueCompare_OpGt,
+	">=": proto.APLValueCompare_OpGe,
+}
+
+var aplTextMathOps = map[string]proto.APLValueMath_MathOperator{
+	"+": proto.APLValueMath_OpAdd,
+	"-": proto.APLValueMath_OpSub,
+	"*": proto.APLValueMath_OpMul,
+	"/": proto.APLValueMath_OpDiv,
+}
+
+func aplTextCmpValue(cmp *aplTextCmp) (*proto.APLValue, error) {
+	lhs, err := aplTextSumValue(cmp.Lhs)
+	if err != nil || cmp.Rhs == nil {
+		return lhs, err
+	}
+	rhs, err := aplTextSumValue(cmp.Rhs)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{Op: aplTextCompareOps[cmp.Op], Lhs: lhs, Rhs: rhs}}}, nil
+}
+
+func aplTextSumValue(sum *aplTextSum) (*proto.APLValue, error) {
+	value, err := aplTextProductValue(sum.First)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range sum.Rest {
+		rhs, err := aplTextProductValue(term.Term)
+		if err != nil {
+			return nil, err
+		}
+		value = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: aplTextMathOps[term.Op], Lhs: value, Rhs: rhs}}}
+	}
+	return value, nil
+}
+
+func aplTextProductValue(product *aplTextProduct) (*proto.APLValue, error) {
+	value, err := aplTextUnaryValue(product.First)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range product.Rest {
+		rhs, err := aplTextUnaryValue(term.Term)
+		if err != nil {
+			return nil, err
+		}
+		value = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: aplTextMathOps[term.Op], Lhs: value, Rhs: rhs}}}
+	}
+	return value, nil
+}
+
+func aplTextUnaryValue(unary *aplTextUnary) (*proto.APLValue, error) {
+	if unary.Not == nil {
+		return aplTextPrimaryValue(unary.Primary)
+	}
+	val, err := aplTextUnaryValue(unary.Not)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: val}}}, nil
+}
+
+func aplTextPrimaryValue(primary *aplTextPrimary) (*proto.APLValue, error) {
+	value := &proto.APLValue{}
+	if primary.Paren != nil {
+		val, err := aplTextValue(primary.Paren)
+		if err != nil {
+			return nil, err
+		}
+		value = val
+	} else if err := aplTextPrimaryToMessage(primary, value.ProtoReflect()); err != nil {
+		return nil, err
+	}
+
+	if primary.UUID != nil {
+		if value.Uuid != nil {
+			return nil, aplTextError(primary.Pos, "value already has a uuid")
+		}
+		value.Uuid = &proto.UUID{Value: *primary.UUID}
+	}
+	return value, nil
+}
+
+// Operator precedence of formatted values, from loosest to tightest.
+const (
+	aplTextPrecOr = iota + 1
+	aplTextPrecAnd
+	aplTextPrecCmp
+	aplTextPrecSum
+	aplTextPrecProduct
+	aplTextPrecUnary
+	aplTextPrecPrimary
+)
+
+// Formats a rotation in the APL text format, one statement per line.
+func FormatAPLRotation(rotation *proto.APLRotation) string {
+	var sb strings.Builder
+
+	if rotation.Type != proto.APLRotation_TypeUnknown {
+		fmt.Fprintf(&sb, "type %s\n", aplTextEnumName(rotation.Type.Descriptor(), rotation.Type.Number()))
+	}
+	if rotation.Simple != nil {
+		fmt.Fprintf(&sb, "simple %s\n", formatAPLTextMessage(rotation.Simple.ProtoReflect()))
+	}
+	for _, variable := range rotation.ValueVariables {
+		sb.WriteString(formatAPLTextVariable(variable))
+	}
+	for _, prepullAction := range rotation.PrepullActions {
+		sb.WriteString("prepull")
+		if prepullAction.Hide {
+			sb.WriteString(" hidden")
+		}
+		if prepullAction.DoAtValue != nil {
+			sb.WriteString(" at " + formatAPLTextValue(prepullAction.DoAtValue, 0))
+		}
+		sb.WriteString(":")
+		if prepullAction.Action != nil {
+			sb.WriteString(" " + formatAPLTextAction(prepullAction.Action))
+		}
+		sb.WriteString("\n")
+	}
+	for _, listItem := range rotation.PriorityList {
+		sb.WriteString(formatAPLTextListItem(listItem))
+	}
+	for _, group := range rotation.Groups {
+		fmt.Fprintf(&sb, "group %s {\n", formatAPLTextName(group.Name))
+		for _, variable := range group.Variables {
+			sb.WriteString("\t" + formatAPLTextVariable(variable))
+		}
+		for _, listItem := range group.Actions {
+			sb.WriteString("\t" + formatAPLTextListItem(listItem))
+		}
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+func formatAPLTextName(name string) string {
+	if aplTextIdent.MatchString(name) && !slices.Contains(aplTextKeywords, name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func formatAPLTextVariable(variable *proto.APLValueVariable) string {
+	if variable.Value == nil {
+		return fmt.Sprintf("variable %s\n", formatAPLTextName(variable.Name))
+	}
+	return fmt.Sprintf("variable %s = %s\n", formatAPLTextName(variable.Name), formatAPLTextValue(variable.Value, 0))
+}
+
+func formatAPLTextListItem(listItem *proto.APLListItem) string {
+	var sb strings.Builder
+	sb.WriteString("action")
+	if listItem.Hide {
+		sb.WriteString(" hidden")
+	}
+	if listItem.Notes != "" {
+		sb.WriteString(" notes " + strconv.Quote(listItem.Notes))
+	}
+	if listItem.Action != nil {
+		sb.WriteString(" " + formatAPLTextAction(listItem.Action))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func formatAPLTextAction(action *proto.APLAction) string {
+	msg := action.ProtoReflect()
+	field := msg.WhichOneof(aplActionDescriptor.Oneofs().ByName("action"))
+	if field == nil {
+		return formatAPLTextMessage(msg)
+	}
+
+	text := formatAPLTextCall(field, msg.Get(field).Message())
+	if action.Condition != nil {
+		text += " if " + formatAPLTextValue(action.Condition, 0)
+	}
+	return text
+}
+
+// Formats a value, wrapping it in parentheses if its precedence is below minPrec.
+func formatAPLTextValue(value *proto.APLValue, minPrec int) string {
+	text, prec := formatAPLTextValueWithoutUUID(value)
+	if value.Uuid != nil {
+		if prec < aplTextPrecPrimary {
+			text = "(" + text + ")"
+		}
+		text, prec = fmt.Sprintf("%s@%s", text, strconv.Quote(value.Uuid.Value)), aplTextPrecPrimary
+	}
+	if prec < minPrec {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func formatAPLTextValueWithoutUUID(value *proto.APLValue) (string, int) {
+	join := func(vals []*proto.APLValue, sep string, prec int) (string, bool) {
+		if len(vals) < 2 || slices.Contains(vals, nil) {
+			return "", false
+		}
+		texts := make([]string, len(vals))
+		for i, val := range vals {
+			texts[i] = formatAPLTextValue(val, prec+1)
+		}
+		return strings.Join(texts, sep), true
+	}
+
+	switch v := value.Value.(type) {
+	case *proto.APLValue_Const:
+		if v.Const != nil {
+			if aplTextBareConst.MatchString(v.Const.Val) || v.Const.Val == "true" || v.Const.Val == "false" {
+				return v.Const.Val, aplTextPrecPrimary
+			}
+			return strconv.Quote(v.Const.Val), aplTextPrecPrimary
+		}
+	case *proto.APLValue_Or:
+		if text, ok := join(v.Or.GetVals(), " || ", aplTextPrecOr); ok {
+			return text, aplTextPrecOr
+		}
+	case *proto.APLValue_And:
+		if text, ok := join(v.And.GetVals(), " && ", aplTextPrecAnd); ok {
+			return text, aplTextPrecAnd
+		}
+	case *proto.APLValue_Not:
+		if v.Not.GetVal() != nil {
+			return "!" + formatAPLTextValue(v.Not.Val, aplTextPrecUnary), aplTextPrecUnary
+		}
+	case *proto.APLValue_Cmp:
+		if v.Cmp.GetLhs() != nil && v.Cmp.GetRhs() != nil {
+			for op, cmpOp := range aplTextCompareOps {
+				if cmpOp == v.Cmp.Op {
+					return fmt.Sprintf("%s %s %s", formatAPLTextValue(v.Cmp.Lhs, aplTextPrecCmp+1), op, formatAPLTextValue(v.Cmp.Rhs, aplTextPrecCmp+1)), aplTextPrecCmp
+				}
+			}
+		}
+	case *proto.APLValue_Math:
+		if v.Math.GetLhs() != nil && v.Math.GetRhs() != nil {
+			for op, mathOp := range aplTextMathOps {
+				if mathOp == v.Math.Op {
+					prec := TernaryInt(op == "+" || op == "-", aplTextPrecSum, aplTextPrecProduct)
+					return fmt.Sprintf("%s %s %s", formatAPLTextValue(v.Math.Lhs, prec), op, formatAPLTextValue(v.Math.Rhs, prec+1)), prec
+				}
+			}
+		}
+	}
+
+	msg := value.ProtoReflect()
+	field := msg.WhichOneof(aplValueDescriptor.Oneofs().ByName("value"))
+	if field == nil {
+		return formatAPLTextMessage(msg), aplTextPrecPrimary
+	}
+	return formatAPLTextCall(field, msg.Get(field).Message()), aplTextPrecPrimary
+}
+
+// Formats an action or value as name(positional, field=value, ...).
+func formatAPLTextCall(field protoreflect.FieldDescriptor, msg protoreflect.Message) string {
+	fields := aplTextSortedFields(msg.Descriptor())
+	var args []string
+	for i, argField := range fields {
+		if !msg.Has(argField) {
+			continue
+		}
+		if i > 0 {
+			args = append(args, fmt.Sprintf("%s=%s", argField.Name(), formatAPLTextField(argField, msg.Get(argField))))
+		} else if argField.IsList() {
+			list := msg.Get(argField).List()
+			for j := range list.Len() {
+				args = append(args, formatAPLTextSingular(argField, list.Get(j)))
+			}
+		} else {
+			args = append(args, formatAPLTextSingular(argField, msg.Get(argField)))
+		}
+	}
+
+	if len(args) == 0 {
+		return string(field.Name())
+	}
+	return fmt.Sprintf("%s(%s)", field.Name(), strings.Join(args, ", "))
+}
+
+// Formats any message as {field=value, ...}, or an ActionID in its compact form.
+func formatAPLTextMessage(msg protoreflect.Message) string {
+	if actionID, ok := msg.Interface().(*proto.ActionID); ok && actionID.RawId != nil {
+		var text string
+		switch id := actionID.RawId.(type) {
+		case *proto.ActionID_SpellId:
+			text = strconv.Itoa(int(id.SpellId))
+		case *proto.ActionID_ItemId:
+			text = fmt.Sprintf("item:%d", id.ItemId)
+		case *proto.ActionID_OtherId:
+			text = "other:" + aplTextEnumName(id.OtherId.Descriptor(), id.OtherId.Number())
+		}
+		if actionID.Tag != 0 {
+			text += fmt.Sprintf("#%d", actionID.Tag)
+		}
+		return text
+	}
+
+	var args []string
+	for _, field := range aplTextSortedFields(msg.Descriptor()) {
+		if msg.Has(field) {
+			args = append(args, fmt.Sprintf("%s=%s", field.Name(), formatAPLTextField(field, msg.Get(field))))
+		}
+	}
+	return "{" + strings.Join(args, ", ") + "}"
+}
+
+func formatAPLTextField(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if !field.IsList() {
+		return formatAPLTextSingular(field, value)
+	}
+	list := value.List()
+	elems := make([]string, list.Len())
+	for i := range elems {
+		elems[i] = formatAPLTextSingular(field, list.Get(i))
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func formatAPLTextSingular(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch msg := value.Message().Interface().(type) {
+		case *proto.APLValue:
+			return formatAPLTextValue(msg, 0)
+		case *proto.APLAction:
+			return formatAPLTextAction(msg)
+		}
+		return formatAPLTextMessage(value.Message())
+	case protoreflect.EnumKind:
+		return aplTextEnumName(field.Enum(), value.Enum())
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	}
+	return value.String()
+}
+
+func aplTextEnumName(enum protoreflect.EnumDescriptor, number protoreflect.EnumNumber) string {
+	if value := enum.Values().ByNumber(number); value != nil {
+		return string(value.Name())
+	}
+	return strconv.Itoa(int(number))
+}
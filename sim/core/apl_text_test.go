@@ -0,0 +1,117 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestAPLTextRoundTripsRepoRotations(t *testing.T) {
+	files, err := filepath.Glob("../../ui/*/*/apls/*.apl.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find APL files: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %s", file, err)
+		}
+		// Some rotations still carry fields which were since removed from the proto.
+		rotation := &proto.APLRotation{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, rotation); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %s", file, err)
+		}
+
+		text := FormatAPLRotation(rotation)
+		parsed, err := APLRotationFromText(text)
+		if err != nil {
+			t.Fatalf("Failed to parse formatted %s: %s\n%s", file, err, text)
+		}
+		if !googleProto.Equal(rotation, parsed) {
+			t.Fatalf("%s did not round trip, got:\n%s", file, FormatAPLRotation(parsed))
+		}
+		if reformatted := FormatAPLRotation(parsed); reformatted != text {
+			t.Fatalf("%s formatting is not stable", file)
+		}
+	}
+}
+
+func TestAPLTextParse(t *testing.T) {
+	rotation, err := APLRotationFromText(`
+		type TypeAPL
+		// Comments are ignored.
+		prepull at -1s: cast_spell(other:OtherActionPotion)
+		action cast_spell(12345) if aura_is_active(item:76093#2) && current_energy > 60 || !gcd_is_ready
+		action hidden notes "Filler" wait(1.5s - 0.5 * 2)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+
+	expected := &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		PrepullActions: []*proto.APLPrepullAction{{
+			DoAtValue: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "-1s"}}},
+			Action: &proto.APLAction{Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+				SpellId: &proto.ActionID{RawId: &proto.ActionID_OtherId{OtherId: proto.OtherAction_OtherActionPotion}},
+			}}},
+		}},
+		PriorityList: []*proto.APLListItem{
+			{Action: &proto.APLAction{
+				Condition: &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: []*proto.APLValue{
+					{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: []*proto.APLValue{
+						{Value: &proto.APLValue_AuraIsActive{AuraIsActive: &proto.APLValueAuraIsActive{
+							AuraId: &proto.ActionID{RawId: &proto.ActionID_ItemId{ItemId: 76093}, Tag: 2},
+						}}},
+						{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+							Op:  proto.APLValueCompare_OpGt,
+							Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentEnergy{CurrentEnergy: &proto.APLValueCurrentEnergy{}}},
+							Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "60"}}},
+						}}},
+					}}}},
+					{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: &proto.APLValue{Value: &proto.APLValue_GcdIsReady{GcdIsReady: &proto.APLValueGCDIsReady{}}}}}},
+				}}}},
+				Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+					SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 12345}},
+				}},
+			}},
+			{Hide: true, Notes: "Filler", Action: &proto.APLAction{Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{
+				Duration: &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+					Op:  proto.APLValueMath_OpSub,
+					Lhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1.5s"}}},
+					Rhs: &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+						Op:  proto.APLValueMath_OpMul,
+						Lhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "0.5"}}},
+						Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "2"}}},
+					}}},
+				}}},
+			}}}},
+		},
+	}
+	if !googleProto.Equal(expected, rotation) {
+		t.Fatalf("Unexpected rotation:\n%s", FormatAPLRotation(rotation))
+	}
+}
+
+func TestAPLTextErrorPositions(t *testing.T) {
+	for text, position := range map[string]string{
+		"type TypeAPL\naction cast_spell(12345))":         "2:25",
+		"action cast_spell(12345)\naction not_a_value(1)": "2:8",
+		"action wait(1s, 2s)":                             "1:17",
+		"action cast_spell(spell_id=1) if 1 if 2":         "1:36",
+	} {
+		_, err := APLRotationFromText(text)
+		if err == nil {
+			t.Fatalf("Expected an error parsing %q", text)
+		}
+		if !strings.HasPrefix(err.Error(), position+":") {
+			t.Fatalf("Expected an error at %s parsing %q, got: %s", position, text, err)
+		}
+	}
+}
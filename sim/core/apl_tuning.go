@@ -0,0 +1,292 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultAPLTuningPasses = 3
+	MaxAPLTuningCandidates = 100
+)
+
+// Splits a numeric constant such as "2.5s" or "95%" into its number and unit.
+var aplTuningConstRegex = regexp.MustCompile(`^\s*(-?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)\s*(ms|s|m|h|%)?\s*$`)
+
+type aplTunable struct {
+	uuid       *proto.UUID
+	initial    string
+	candidates []string
+	values     []float64
+}
+
+// Calls f for every APLValue nested anywhere in msg which has a UUID.
+func forEachAPLValueWithUUID(msg protoreflect.Message, f func(value *proto.APLValue)) {
+	if value, ok := msg.Interface().(*proto.APLValue); ok && value.Uuid != nil {
+		f(value)
+	}
+
+	msg.Range(func(field protoreflect.FieldDescriptor, fieldValue protoreflect.Value) bool {
+		switch {
+		case field.Message() == nil || field.IsMap():
+		case field.IsList():
+			list := fieldValue.List()
+			for i := range list.Len() {
+				forEachAPLValueWithUUID(list.Get(i).Message(), f)
+			}
+		default:
+			forEachAPLValueWithUUID(fieldValue.Message(), f)
+		}
+		return true
+	})
+}
+
+// Returns the values to try for a tunable constant, formatted in the unit of
+// its initial value. The initial value is always included.
+func newAPLTunable(config *proto.APLTunableConst, initial string) (*aplTunable, error) {
+	match := aplTuningConstRegex.FindStringSubmatch(initial)
+	if match == nil {
+		return nil, fmt.Errorf("constant %q is not a number", initial)
+	}
+	initialValue, _ := strconv.ParseFloat(match[1], 64)
+	unit := match[2]
+
+	if config.Step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	if config.Max < config.Min {
+		return nil, fmt.Errorf("max must not be less than min")
+	}
+	numSteps := int(math.Floor((config.Max-config.Min)/config.Step+1e-9)) + 1
+	if numSteps > MaxAPLTuningCandidates {
+		return nil, fmt.Errorf("%d values to try, the maximum is %d", numSteps, MaxAPLTuningCandidates)
+	}
+
+	tunable := &aplTunable{uuid: config.Uuid, initial: initial}
+	hasInitial := false
+	for i := range numSteps {
+		// Rounding avoids values like 0.30000000000000004.
+		value := math.Round((config.Min+float64(i)*config.Step)*1e9) / 1e9
+		candidate := strconv.FormatFloat(value, 'f', -1, 64) + unit
+		if math.Abs(value-initialValue) < 1e-9 {
+			candidate = initial
+			hasInitial = true
+		}
+		tunable.candidates = append(tunable.candidates, candidate)
+		tunable.values = append(tunable.values, value)
+	}
+	if !hasInitial {
+		tunable.candidates = append(tunable.candidates, initial)
+		tunable.values = append(tunable.values, initialValue)
+	}
+	return tunable, nil
+}
+
+// Search for the best values of the tunable constants in a rotation, using
+// coordinate descent: each pass tries every value of one tunable at a time
+// while holding the others at their best values so far.
+func runAPLTuning(request *proto.APLTuningRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.APLTuningResult {
+	errorResult := func(format string, args ...any) *proto.APLTuningResult {
+		return &proto.APLTuningResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if request.BaseSettings == nil || request.BaseSettings.Raid == nil || request.BaseSettings.SimOptions == nil {
+		return errorResult("APL tuning request is missing base settings!")
+	}
+	baseRequest := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if len(baseRequest.Raid.Parties) == 0 || len(baseRequest.Raid.Parties[0].Players) == 0 {
+		return errorResult("APL tuning request has no player!")
+	}
+	if baseRequest.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+	if len(request.Tunables) == 0 {
+		return errorResult("APL tuning request has no tunable constants!")
+	}
+
+	rotation := baseRequest.Raid.Parties[0].Players[0].Rotation
+	if rotation == nil {
+		return errorResult("Player has no rotation to tune!")
+	}
+	constsByUUID := map[string]*proto.APLValue{}
+	forEachAPLValueWithUUID(rotation.ProtoReflect(), func(value *proto.APLValue) {
+		constsByUUID[value.Uuid.Value] = value
+	})
+
+	tunables := make([]*aplTunable, len(request.Tunables))
+	tunableIdxByUUID := map[string]int{}
+	for i, config := range request.Tunables {
+		if config.Uuid == nil || config.Uuid.Value == "" {
+			return errorResult("Tunable %d has no UUID!", i)
+		}
+		if _, ok := tunableIdxByUUID[config.Uuid.Value]; ok {
+			return errorResult("Tunable %s is listed more than once!", config.Uuid.Value)
+		}
+		value, ok := constsByUUID[config.Uuid.Value]
+		if !ok || value.GetConst() == nil {
+			return errorResult("Tunable %s is not a constant in the rotation!", config.Uuid.Value)
+		}
+		tunable, err := newAPLTunable(config, value.GetConst().Val)
+		if err != nil {
+			return errorResult("Invalid tunable %s: %s", config.Uuid.Value, err)
+		}
+		tunables[i] = tunable
+		tunableIdxByUUID[config.Uuid.Value] = i
+	}
+
+	maxPasses := request.MaxPasses
+	if maxPasses <= 0 {
+		maxPasses = DefaultAPLTuningPasses
+	}
+
+	// Every sim uses the same seed and labeled RNG, so that differences
+	// between candidates come from the rotation rather than luck.
+	if baseRequest.SimOptions.RandomSeed == 0 {
+		baseRequest.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseRequest.SimOptions.UseLabeledRands = true
+	baseRequest.SimOptions.SaveAllValues = true
+	baseRequest.SimOptions.TargetStdevOfMean = 0
+	baseRequest.SimOptions.Debug = false
+	baseRequest.SimOptions.DebugFirstIteration = false
+
+	buildRotation := func(values []string) *proto.APLRotation {
+		tunedRotation := googleProto.Clone(rotation).(*proto.APLRotation)
+		forEachAPLValueWithUUID(tunedRotation.ProtoReflect(), func(value *proto.APLValue) {
+			if idx, ok := tunableIdxByUUID[value.Uuid.Value]; ok {
+				value.GetConst().Val = values[idx]
+			}
+		})
+		return tunedRotation
+	}
+
+	numCandidates := 0
+	for _, tunable := range tunables {
+		numCandidates += len(tunable.candidates)
+	}
+	simsTotal := int32(1 + numCandidates*int(maxPasses))
+	var simsRun int32 = 0
+
+	// Sims are cached by their tunable values, so points revisited by later
+	// passes are free.
+	cache := map[string]*proto.DistributionMetrics{}
+	valuesKey := func(values []string) string {
+		return strings.Join(values, "\x00")
+	}
+
+	// Returns the DPS distribution of the tuned player for each set of values.
+	evaluate := func(points [][]string) ([]*proto.DistributionMetrics, *proto.ErrorOutcome) {
+		var queued [][]string
+		queuedKeys := map[string]bool{}
+		for _, point := range points {
+			key := valuesKey(point)
+			if _, ok := cache[key]; !ok && !queuedKeys[key] {
+				queuedKeys[key] = true
+				queued = append(queued, point)
+			}
+		}
+
+		errorOutcome := runSimBatch(len(queued), func(pointIdx int) *proto.RaidSimRequest {
+			pointRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+			pointRequest.Raid.Parties[0].Players[0].Rotation = buildRotation(queued[pointIdx])
+			return pointRequest
+		}, func(pointIdx int, result *proto.RaidSimResult) {
+			cache[valuesKey(queued[pointIdx])] = result.RaidMetrics.Parties[0].Players[0].Dps
+
+			simsRun++
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     simsTotal * baseRequest.SimOptions.Iterations,
+					CompletedIterations: simsRun * baseRequest.SimOptions.Iterations,
+					CompletedSims:       simsRun,
+					TotalSims:           simsTotal,
+				}
+			}
+		}, signals)
+		if errorOutcome != nil {
+			return nil, errorOutcome
+		}
+
+		return MapSlice(points, func(point []string) *proto.DistributionMetrics { return cache[valuesKey(point)] }), nil
+	}
+
+	// Returns the values to try for one tunable, holding the others fixed.
+	coordinatePoints := func(current []string, tunableIdx int) [][]string {
+		return MapSlice(tunables[tunableIdx].candidates, func(candidate string) []string {
+			point := append([]string(nil), current...)
+			point[tunableIdx] = candidate
+			return point
+		})
+	}
+
+	initial := MapSlice(tunables, func(tunable *aplTunable) string { return tunable.initial })
+	initialDps, err := evaluate([][]string{initial})
+	if err != nil {
+		return &proto.APLTuningResult{Error: err}
+	}
+
+	current := append([]string(nil), initial...)
+	currentDps := initialDps[0]
+	for range maxPasses {
+		changed := false
+		for i := range tunables {
+			dists, err := evaluate(coordinatePoints(current, i))
+			if err != nil {
+				return &proto.APLTuningResult{Error: err}
+			}
+			for j, dps := range dists {
+				// Only move on a strict improvement, so that ties keep the current value.
+				if dps.Avg > currentDps.Avg {
+					current[i] = tunables[i].candidates[j]
+					currentDps = dps
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	result := &proto.APLTuningResult{
+		TunedRotation: buildRotation(current),
+		InitialDps:    initialDps[0].Avg,
+		TunedDps:      currentDps.Avg,
+	}
+	_, result.DpsDeltaCi95 = pairedDeltaCI95(initialDps[0], currentDps)
+
+	for i, tunable := range tunables {
+		dists, err := evaluate(coordinatePoints(current, i))
+		if err != nil {
+			return &proto.APLTuningResult{Error: err}
+		}
+
+		tunedConst := &proto.APLTunedConst{
+			Uuid:         tunable.uuid,
+			InitialValue: tunable.initial,
+			BestValue:    current[i],
+			ValueCiLow:   math.Inf(1),
+			ValueCiHigh:  math.Inf(-1),
+		}
+		for j, dps := range dists {
+			// Values which aren't significantly worse than the best one.
+			if delta, ci95 := pairedDeltaCI95(currentDps, dps); delta+ci95 >= 0 {
+				tunedConst.ValueCiLow = min(tunedConst.ValueCiLow, tunable.values[j])
+				tunedConst.ValueCiHigh = max(tunedConst.ValueCiHigh, tunable.values[j])
+			}
+		}
+		result.Values = append(result.Values, tunedConst)
+	}
+
+	result.SimsRun = simsRun
+	return result
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLTunableCandidates(t *testing.T) {
+	tunable, err := newAPLTunable(&proto.APLTunableConst{Min: 0.1, Max: 0.5, Step: 0.2}, "0.4s")
+	if err != nil {
+		t.Fatalf("Failed to create tunable: %s", err)
+	}
+	expected := []string{"0.1s", "0.3s", "0.5s", "0.4s"}
+	if len(tunable.candidates) != len(expected) {
+		t.Fatalf("Expected candidates %v, got %v", expected, tunable.candidates)
+	}
+	for i := range expected {
+		if tunable.candidates[i] != expected[i] {
+			t.Fatalf("Expected candidates %v, got %v", expected, tunable.candidates)
+		}
+	}
+
+	// The initial value keeps its original formatting when it is on the grid.
+	if tunable, _ := newAPLTunable(&proto.APLTunableConst{Min: 80, Max: 100, Step: 10}, "90.0%"); len(tunable.candidates) != 3 || tunable.candidates[1] != "90.0%" {
+		t.Fatalf("Expected the initial value to replace its grid point, got %v", tunable.candidates)
+	}
+
+	for name, config := range map[string]*proto.APLTunableConst{
+		"no step":         {Min: 0, Max: 10},
+		"empty range":     {Min: 10, Max: 0, Step: 1},
+		"too many values": {Min: 0, Max: 1000, Step: 1},
+	} {
+		if _, err := newAPLTunable(config, "5"); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+	if _, err := newAPLTunable(&proto.APLTunableConst{Min: 0, Max: 10, Step: 1}, "true"); err == nil {
+		t.Fatalf("Expected an error for a non-numeric constant")
+	}
+}
+
+func TestAPLTuning(t *testing.T) {
+	rotation, err := APLRotationFromText(`
+		type TypeAPL
+		action wait(2s@"wait") if current_time@"now" > 1s@"start"
+		action wait(1s)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse rotation: %s", err)
+	}
+
+	baseSettings := &proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Healer",
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_RestorationShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Rotation:  rotation,
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 30,
+		},
+		SimOptions: &proto.SimOptions{Iterations: 5, RandomSeed: 100},
+	}
+
+	for name, tunables := range map[string][]*proto.APLTunableConst{
+		"no tunables":   nil,
+		"unknown uuid":  {{Uuid: &proto.UUID{Value: "missing"}, Min: 0, Max: 1, Step: 1}},
+		"not constant":  {{Uuid: &proto.UUID{Value: "now"}, Min: 0, Max: 1, Step: 1}},
+		"duplicate":     {{Uuid: &proto.UUID{Value: "start"}, Min: 0, Max: 1, Step: 1}, {Uuid: &proto.UUID{Value: "start"}, Min: 0, Max: 1, Step: 1}},
+		"invalid range": {{Uuid: &proto.UUID{Value: "wait"}, Min: 1, Max: 0, Step: 1}},
+	} {
+		if result := RunAPLTuning(&proto.APLTuningRequest{BaseSettings: baseSettings, Tunables: tunables}); result.Error == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+
+	// The fake healer deals no damage, so every value ties: the search should
+	// keep the initial values and consider the whole range equally good.
+	result := RunAPLTuning(&proto.APLTuningRequest{
+		BaseSettings: baseSettings,
+		Tunables: []*proto.APLTunableConst{
+			{Uuid: &proto.UUID{Value: "wait"}, Min: 1, Max: 3, Step: 0.5},
+			{Uuid: &proto.UUID{Value: "start"}, Min: 0, Max: 10, Step: 5},
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Tuning failed: %s", result.Error.Message)
+	}
+	if len(result.Values) != 2 || result.Values[0].BestValue != "2s" || result.Values[1].BestValue != "1s" {
+		t.Fatalf("Expected ties to keep the initial values, got %v", result.Values)
+	}
+	if result.Values[0].ValueCiLow != 1 || result.Values[0].ValueCiHigh != 3 {
+		t.Fatalf("Expected the whole range to be within the confidence interval, got %v", result.Values[0])
+	}
+	// The initial sim, then 4 + 3 new candidates in the first pass, which changes nothing.
+	if result.SimsRun != 8 {
+		t.Fatalf("Expected cached points to be reused, got %d sims", result.SimsRun)
+	}
+	if result.TunedRotation == nil || result.TunedRotation.PriorityList[0].Action.GetWait().Duration.GetConst().Val != "2s" {
+		t.Fatalf("Expected the tuned rotation to hold the best values")
+	}
+}
@@ -100,6 +100,10 @@ func (rot *APLRotation) newAPLValueWithContext(config *proto.APLValue, groupVari
 		value = rot.newValueIsExecutePhase(config.GetIsExecutePhase(), config.Uuid)
 	case *proto.APLValue_NumberTargets:
 		value = rot.newValueNumberTargets(config.GetNumberTargets(), config.Uuid)
+	case *proto.APLValue_TargetTimeToDie:
+		value = rot.newValueTargetTimeToDie(config.GetTargetTimeToDie(), config.Uuid)
+	case *proto.APLValue_TargetTimeToPercent:
+		value = rot.newValueTargetTimeToPercent(config.GetTargetTimeToPercent(), config.Uuid)
 
 	// Boss
 	case *proto.APLValue_BossSpellIsCasting:
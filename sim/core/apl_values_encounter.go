@@ -92,6 +92,75 @@ func (value *APLValueNumberTargets) String() string {
 	return "Num Active Targets"
 }
 
+type APLValueTargetTimeToDie struct {
+	DefaultAPLValueImpl
+	target UnitReference
+}
+
+func (rot *APLRotation) newValueTargetTimeToDie(config *proto.APLValueTargetTimeToDie, uuid *proto.UUID) APLValue {
+	target := rot.GetTargetUnit(config.TargetUnit)
+	if !rot.validateTimeToDieTarget(target, uuid) {
+		return nil
+	}
+	return &APLValueTargetTimeToDie{
+		target: target,
+	}
+}
+func (value *APLValueTargetTimeToDie) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTargetTimeToDie) GetDuration(sim *Simulation) time.Duration {
+	return sim.GetTargetByIndex(value.target.Get().Index).TimeToDie(sim)
+}
+func (value *APLValueTargetTimeToDie) String() string {
+	return "Target Time to Die"
+}
+
+type APLValueTargetTimeToPercent struct {
+	DefaultAPLValueImpl
+	target        UnitReference
+	healthPercent APLValue
+}
+
+func (rot *APLRotation) newValueTargetTimeToPercent(config *proto.APLValueTargetTimeToPercent, uuid *proto.UUID) APLValue {
+	target := rot.GetTargetUnit(config.TargetUnit)
+	if !rot.validateTimeToDieTarget(target, uuid) {
+		return nil
+	}
+	healthPercent := rot.coerceTo(rot.newAPLValue(config.HealthPercent), proto.APLValueType_ValueTypeFloat)
+	if healthPercent == nil {
+		return nil
+	}
+	return &APLValueTargetTimeToPercent{
+		target:        target,
+		healthPercent: healthPercent,
+	}
+}
+func (value *APLValueTargetTimeToPercent) GetInnerValues() []APLValue {
+	return []APLValue{value.healthPercent}
+}
+func (value *APLValueTargetTimeToPercent) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTargetTimeToPercent) GetDuration(sim *Simulation) time.Duration {
+	return sim.GetTargetByIndex(value.target.Get().Index).TimeToPercent(sim, value.healthPercent.GetFloat(sim))
+}
+func (value *APLValueTargetTimeToPercent) String() string {
+	return fmt.Sprintf("Target Time to %s Health", value.healthPercent)
+}
+
+func (rot *APLRotation) validateTimeToDieTarget(target UnitReference, uuid *proto.UUID) bool {
+	resolvedTarget := target.Get()
+	if resolvedTarget == nil {
+		return false
+	}
+	if resolvedTarget.Type != EnemyUnit {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "%s is not an enemy target", resolvedTarget.Label)
+		return false
+	}
+	return true
+}
+
 type APLValueIsExecutePhase struct {
 	DefaultAPLValueImpl
 	threshold proto.APLValueIsExecutePhase_ExecutePhaseThreshold
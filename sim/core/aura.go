@@ -206,6 +206,9 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
+	if sim.CombatLog != nil {
+		aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraStacks)
+	}
 	if aura.OnStacksChange != nil {
 		aura.OnStacksChange(aura, sim, oldStacks, newStacks)
 	}
@@ -641,6 +644,14 @@ restart:
 	}
 }
 
+func (aura *Aura) logEvent(sim *Simulation, eventType proto.CombatLogEventType) {
+	aura.Unit.LogEvent(sim, nil, &proto.CombatLogEvent{
+		Type:     eventType,
+		ActionId: aura.ActionID.ToProto(),
+		Stacks:   aura.stacks,
+	})
+}
+
 // Adds a new aura to the simulation. If an aura with the same ID already
 // exists it will be replaced with the new one.
 func (aura *Aura) Activate(sim *Simulation) {
@@ -649,6 +660,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+			aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraRefreshed)
+		}
 		aura.Refresh(sim)
 		return
 	}
@@ -739,6 +753,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraGained)
+	}
 
 	// don't invoke possible callbacks until the internal state is consistent
 	if aura.OnGain != nil {
@@ -761,13 +778,16 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		}
 	}
 
-	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
+	if (sim.Log != nil || sim.CombatLog != nil) && !aura.ActionID.IsEmptyAction() {
 		// fix logging timestamps for lazy aura expiration
 		oldTime := sim.CurrentTime
 		sim.CurrentTime = min(sim.CurrentTime, aura.expires)
 		if sim.Log != nil {
 			aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil {
+			aura.logEvent(sim, proto.CombatLogEventType_CombatLogEventAuraFaded)
+		}
 		sim.CurrentTime = oldTime
 	}
 
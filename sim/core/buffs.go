@@ -888,7 +888,7 @@ func registerVigilanceCD(agent Agent, numWarriors int32) {
 		return
 	}
 
-	buffAura := VigilanceAura(agent.GetCharacter(), -1)
+	buffAura := VigilanceAura(&agent.GetCharacter().Unit, -1)
 
 	registerExternalConsecutiveCDApproximation(
 		agent,
@@ -911,7 +911,7 @@ func registerVigilanceCD(agent Agent, numWarriors int32) {
 		numWarriors)
 }
 
-func VigilanceAura(character *Character, actionTag int32) *Aura {
+func VigilanceAura(character *Unit, actionTag int32) *Aura {
 	actionID := ActionID{SpellID: VigilanceSpellID, Tag: actionTag}
 
 	return character.GetOrRegisterAura(Aura{
@@ -932,7 +932,7 @@ func registerPainSuppressionCD(agent Agent, numPainSuppressions int32) {
 		return
 	}
 
-	psAura := PainSuppressionAura(agent.GetCharacter(), -1)
+	psAura := PainSuppressionAura(&agent.GetCharacter().Unit, -1)
 
 	registerExternalConsecutiveCDApproximation(
 		agent,
@@ -955,7 +955,7 @@ func registerPainSuppressionCD(agent Agent, numPainSuppressions int32) {
 		numPainSuppressions)
 }
 
-func PainSuppressionAura(character *Character, actionTag int32) *Aura {
+func PainSuppressionAura(character *Unit, actionTag int32) *Aura {
 	actionID := ActionID{SpellID: 33206, Tag: actionTag}
 
 	return character.GetOrRegisterAura(Aura{
@@ -976,17 +976,7 @@ func registerGuardianSpiritCD(agent Agent, numGuardianSpirits int32) {
 		return
 	}
 
-	character := agent.GetCharacter()
-	gsAura := GuardianSpiritAura(character, -1)
-	healthMetrics := character.NewHealthMetrics(ActionID{SpellID: 47788})
-
-	character.AddDynamicDamageTakenModifier(func(sim *Simulation, _ *Spell, result *SpellResult, isPeriodic bool) {
-		if (result.Damage >= character.CurrentHealth()) && gsAura.IsActive() {
-			result.Damage = character.CurrentHealth()
-			character.GainHealth(sim, 0.5*character.MaxHealth(), healthMetrics)
-			gsAura.Deactivate(sim)
-		}
-	})
+	gsAura := GuardianSpiritAura(&agent.GetCharacter().Unit, -1)
 
 	registerExternalConsecutiveCDApproximation(
 		agent,
@@ -1009,15 +999,101 @@ func registerGuardianSpiritCD(agent Agent, numGuardianSpirits int32) {
 		numGuardianSpirits)
 }
 
-func GuardianSpiritAura(character *Character, actionTag int32) *Aura {
+func GuardianSpiritAura(character *Unit, actionTag int32) *Aura {
 	actionID := ActionID{SpellID: 47788, Tag: actionTag}
 
-	return character.GetOrRegisterAura(Aura{
+	gsAura := character.GetOrRegisterAura(Aura{
 		Label:    "GuardianSpirit-" + actionID.String(),
 		Tag:      GuardianSpiritAuraTag,
 		ActionID: actionID,
 		Duration: GuardianSpiritDuration,
 	}).AttachMultiplicativePseudoStatBuff(&character.PseudoStats.HealingTakenMultiplier, 1.4)
+
+	if character.HasHealthBar() {
+		healthMetrics := character.NewHealthMetrics(actionID)
+
+		character.AddDynamicDamageTakenModifier(func(sim *Simulation, _ *Spell, result *SpellResult, isPeriodic bool) {
+			if (result.Damage >= character.CurrentHealth()) && gsAura.IsActive() {
+				result.Damage = character.CurrentHealth()
+				character.GainHealth(sim, 0.5*character.MaxHealth(), healthMetrics)
+				gsAura.Deactivate(sim)
+			}
+		})
+	}
+
+	return gsAura
+}
+
+var HandOfSacrificeAuraTag = "HandOfSacrifice"
+
+const HandOfSacrificeDuration = time.Second * 12
+const HandOfSacrificeCD = time.Minute * 2
+
+// Transfers 30% of the damage the character takes to the paladin, dealt by
+// transferSpell so it shows up in the paladin's metrics. Fades once the
+// paladin's maximum health has been transferred.
+func HandOfSacrificeAura(character *Unit, transferSpell *Spell, actionTag int32) *Aura {
+	actionID := ActionID{SpellID: 6940, Tag: actionTag}
+	paladin := transferSpell.Unit
+
+	var totalTransferred float64
+	hosAura := character.GetOrRegisterAura(Aura{
+		Label:    "HandOfSacrifice-" + actionID.String(),
+		Tag:      HandOfSacrificeAuraTag,
+		ActionID: actionID,
+		Duration: HandOfSacrificeDuration,
+		OnGain: func(aura *Aura, sim *Simulation) {
+			totalTransferred = 0
+		},
+	})
+
+	character.AddDynamicDamageTakenModifier(func(sim *Simulation, _ *Spell, result *SpellResult, isPeriodic bool) {
+		if !hosAura.IsActive() || result.Damage <= 0 {
+			return
+		}
+
+		transferred := min(result.Damage*0.3, paladin.MaxHealth()-totalTransferred)
+		result.Damage -= transferred
+		totalTransferred += transferred
+		transferSpell.CalcAndDealDamage(sim, paladin, transferred, transferSpell.OutcomeAlwaysHit)
+
+		if totalTransferred >= paladin.MaxHealth() {
+			hosAura.Deactivate(sim)
+		}
+	})
+
+	return hosAura
+}
+
+var PowerInfusionAuraTag = "PowerInfusion"
+
+const PowerInfusionDuration = time.Second * 20
+const PowerInfusionCD = time.Minute * 2
+
+func PowerInfusionAura(character *Unit, actionTag int32) *Aura {
+	actionID := ActionID{SpellID: 10060, Tag: actionTag}
+
+	aura := character.GetOrRegisterAura(Aura{
+		Label:    "PowerInfusion-" + actionID.String(),
+		Tag:      PowerInfusionAuraTag,
+		ActionID: actionID,
+		Duration: PowerInfusionDuration,
+	}).AttachSpellMod(SpellModConfig{
+		Kind:       SpellMod_DamageDone_Pct,
+		FloatValue: 0.05,
+	}).AttachMultiplyCastSpeed(1.2)
+
+	aura.NewExclusiveEffect("ManaCost", true, ExclusiveEffect{
+		Priority: -20,
+		OnGain: func(ee *ExclusiveEffect, sim *Simulation) {
+			ee.Aura.Unit.PseudoStats.SpellCostPercentModifier -= 20
+		},
+		OnExpire: func(ee *ExclusiveEffect, sim *Simulation) {
+			ee.Aura.Unit.PseudoStats.SpellCostPercentModifier += 20
+		},
+	})
+
+	return aura
 }
 
 var RallyingCryAuraTag = "RallyingCry"
@@ -0,0 +1,398 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Upper bound on the number of gear combinations a single bulk request may expand to.
+const MaxBulkSimCombinations = 100_000
+
+// A group of item slots whose contents are chosen together, e.g. both ring
+// slots or the main hand / off hand pair. Each option holds one ItemSpec per
+// slot, in the same order as slots.
+type bulkSlotGroup struct {
+	slots   []proto.ItemSlot
+	options [][]*proto.ItemSpec
+}
+
+type bulkCombo struct {
+	player     *proto.Player
+	itemsAdded []*proto.ItemSpecWithSlot
+}
+
+var bulkPairedSlots = [][]proto.ItemSlot{
+	{proto.ItemSlot_ItemSlotFinger1, proto.ItemSlot_ItemSlotFinger2},
+	{proto.ItemSlot_ItemSlotTrinket1, proto.ItemSlot_ItemSlotTrinket2},
+}
+
+func getEquippedItemSpec(player *proto.Player, slot proto.ItemSlot) *proto.ItemSpec {
+	if player.Equipment == nil || int(slot) >= len(player.Equipment.Items) {
+		return nil
+	}
+	if spec := player.Equipment.Items[slot]; spec != nil && spec.Id != 0 {
+		return spec
+	}
+	return nil
+}
+
+// Groups the bulk items by the slots they can go in. Currently equipped items
+// are always included as options, so every combination is a full gear set.
+func buildBulkSlotGroups(player *proto.Player, settings *proto.BulkSettings) ([]*bulkSlotGroup, error) {
+	isFuryWarrior := player.GetFuryWarrior() != nil
+
+	var slotItems [NumItemSlots][]*proto.ItemSpec
+	var mainHands, offHands []*proto.ItemSpec
+
+	for _, spec := range settings.Items {
+		item := GetItemByID(spec.Id)
+		if item == nil {
+			return nil, fmt.Errorf("no item with id: %d", spec.Id)
+		}
+
+		eligibleSlots := eligibleSlotsForItem(item, isFuryWarrior)
+		if len(eligibleSlots) == 0 {
+			return nil, fmt.Errorf("item %d (%s) cannot be equipped in any slot", item.ID, item.Name)
+		}
+
+		if item.Type == proto.ItemType_ItemTypeWeapon || item.Type == proto.ItemType_ItemTypeRanged {
+			if slices.Contains(eligibleSlots, proto.ItemSlot_ItemSlotMainHand) {
+				mainHands = append(mainHands, spec)
+			}
+			if slices.Contains(eligibleSlots, proto.ItemSlot_ItemSlotOffHand) {
+				offHands = append(offHands, spec)
+			}
+			continue
+		}
+
+		// Paired slots share a single list, keyed by the first slot.
+		slotItems[eligibleSlots[0]] = append(slotItems[eligibleSlots[0]], spec)
+	}
+
+	var groups []*bulkSlotGroup
+
+	for slot := proto.ItemSlot(0); slot < proto.ItemSlot_ItemSlotFinger1; slot++ {
+		if len(slotItems[slot]) == 0 {
+			continue
+		}
+
+		group := &bulkSlotGroup{slots: []proto.ItemSlot{slot}}
+		if equipped := getEquippedItemSpec(player, slot); equipped != nil {
+			group.options = append(group.options, []*proto.ItemSpec{equipped})
+		}
+		for _, spec := range slotItems[slot] {
+			group.options = append(group.options, []*proto.ItemSpec{spec})
+		}
+		groups = append(groups, group)
+	}
+
+	for _, slots := range bulkPairedSlots {
+		if len(slotItems[slots[0]]) == 0 {
+			continue
+		}
+
+		var pool []*proto.ItemSpec
+		for _, slot := range slots {
+			if equipped := getEquippedItemSpec(player, slot); equipped != nil {
+				pool = append(pool, equipped)
+			}
+		}
+		pool = append(pool, slotItems[slots[0]]...)
+
+		group := &bulkSlotGroup{slots: slots}
+		for i := 0; i < len(pool); i++ {
+			for j := i + 1; j < len(pool); j++ {
+				// Rings and trinkets are unique-equipped.
+				if pool[i].Id == pool[j].Id {
+					continue
+				}
+				group.options = append(group.options, []*proto.ItemSpec{pool[i], pool[j]})
+			}
+		}
+		if len(group.options) == 0 {
+			return nil, fmt.Errorf("at least 2 distinct items are needed for %s", slots[0].String())
+		}
+		groups = append(groups, group)
+	}
+
+	if len(mainHands)+len(offHands) > 0 {
+		if equipped := getEquippedItemSpec(player, proto.ItemSlot_ItemSlotMainHand); equipped != nil {
+			mainHands = append([]*proto.ItemSpec{equipped}, mainHands...)
+		}
+		if equipped := getEquippedItemSpec(player, proto.ItemSlot_ItemSlotOffHand); equipped != nil {
+			offHands = append([]*proto.ItemSpec{equipped}, offHands...)
+		}
+
+		group := &bulkSlotGroup{slots: []proto.ItemSlot{proto.ItemSlot_ItemSlotMainHand, proto.ItemSlot_ItemSlotOffHand}}
+		emptySlot := &proto.ItemSpec{}
+		for _, mh := range mainHands {
+			if !isFuryWarrior && GetItemByID(mh.Id).HandType == proto.HandType_HandTypeTwoHand {
+				group.options = append(group.options, []*proto.ItemSpec{mh, emptySlot})
+				continue
+			}
+
+			numOptions := len(group.options)
+			for _, oh := range offHands {
+				// The same copy of a one-hander can't be in both hands.
+				if oh == mh {
+					continue
+				}
+				group.options = append(group.options, []*proto.ItemSpec{mh, oh})
+			}
+			if len(group.options) == numOptions {
+				group.options = append(group.options, []*proto.ItemSpec{mh, emptySlot})
+			}
+		}
+		if len(mainHands) == 0 {
+			for _, oh := range offHands {
+				group.options = append(group.options, []*proto.ItemSpec{emptySlot, oh})
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+func countBulkCombinations(groups []*bulkSlotGroup) int {
+	numCombinations := 1
+	for _, group := range groups {
+		numCombinations *= len(group.options)
+		if numCombinations > MaxBulkSimCombinations {
+			return numCombinations
+		}
+	}
+	return numCombinations
+}
+
+type bulkSimSettings struct {
+	*proto.BulkSettings
+}
+
+func (settings *bulkSimSettings) defaultGemForColor(color proto.GemColor) int32 {
+	switch color {
+	case proto.GemColor_GemColorRed:
+		return settings.DefaultRedGem
+	case proto.GemColor_GemColorBlue:
+		return settings.DefaultBlueGem
+	case proto.GemColor_GemColorYellow:
+		return settings.DefaultYellowGem
+	case proto.GemColor_GemColorMeta:
+		return settings.DefaultMetaGem
+	case proto.GemColor_GemColorPrismatic:
+		return settings.DefaultPrismaticGem
+	}
+	return 0
+}
+
+// Returns the spec to use when placing a bulk item into a slot that currently
+// holds the given item. Missing gems are filled with the default gems, and
+// enchants, extra sockets and (optionally) upgrades carry over from the
+// replaced item.
+func (settings *bulkSimSettings) itemSpecForSlot(current *proto.ItemSpec, bulkSpec *proto.ItemSpec, challengeMode bool) *proto.ItemSpec {
+	spec := googleProto.Clone(bulkSpec).(*proto.ItemSpec)
+	item := GetItemByID(spec.Id)
+	spec.ChallengeMode = spec.ChallengeMode || challengeMode
+
+	if current != nil {
+		currentItem := GetItemByID(current.Id)
+		if currentItem != nil && currentItem.Type == item.Type && currentItem.HandType == item.HandType {
+			if spec.Enchant == 0 {
+				spec.Enchant = current.Enchant
+			}
+			if spec.Tinker == 0 {
+				spec.Tinker = current.Tinker
+			}
+		}
+		if settings.InheritUpgrades {
+			if _, ok := item.ScalingOptions[int32(current.UpgradeStep)]; ok || item.ScalingOptions == nil {
+				spec.UpgradeStep = current.UpgradeStep
+			}
+		}
+	}
+
+	numGems := max(len(item.GemSockets), len(spec.Gems))
+	if current != nil {
+		numGems = max(numGems, len(current.Gems))
+	}
+	gems := make([]int32, numGems)
+	copy(gems, spec.Gems)
+	for socketIdx, color := range item.GemSockets {
+		if gems[socketIdx] == 0 {
+			gems[socketIdx] = settings.defaultGemForColor(color)
+		}
+	}
+	// Prismatic sockets from belt buckles and professions belong to the slot rather than the item.
+	if current != nil {
+		for socketIdx := len(item.GemSockets); socketIdx < len(current.Gems); socketIdx++ {
+			if gems[socketIdx] == 0 {
+				gems[socketIdx] = current.Gems[socketIdx]
+			}
+		}
+	}
+	for len(gems) > 0 && gems[len(gems)-1] == 0 {
+		gems = gems[:len(gems)-1]
+	}
+	spec.Gems = gems
+
+	return spec
+}
+
+// Builds the player for the combination with the given index. Indices are
+// decoded as a mixed-radix number, one digit per slot group.
+func (settings *bulkSimSettings) buildCombo(basePlayer *proto.Player, groups []*bulkSlotGroup, comboIdx int) *bulkCombo {
+	player := googleProto.Clone(basePlayer).(*proto.Player)
+	if player.Equipment == nil {
+		player.Equipment = &proto.EquipmentSpec{}
+	}
+	for len(player.Equipment.Items) < int(NumItemSlots) {
+		player.Equipment.Items = append(player.Equipment.Items, &proto.ItemSpec{})
+	}
+
+	isEquipped := func(spec *proto.ItemSpec) bool {
+		return slices.Contains(basePlayer.Equipment.GetItems(), spec)
+	}
+
+	combo := &bulkCombo{player: player}
+	for _, group := range groups {
+		option := group.options[comboIdx%len(group.options)]
+		comboIdx /= len(group.options)
+
+		for i, slot := range group.slots {
+			spec := option[i]
+			if spec.Id == 0 || isEquipped(spec) {
+				player.Equipment.Items[slot] = googleProto.Clone(spec).(*proto.ItemSpec)
+				continue
+			}
+
+			newSpec := settings.itemSpecForSlot(getEquippedItemSpec(basePlayer, slot), spec, basePlayer.ChallengeMode)
+			player.Equipment.Items[slot] = newSpec
+			combo.itemsAdded = append(combo.itemsAdded, &proto.ItemSpecWithSlot{
+				Item: newSpec,
+				Slot: slot,
+			})
+		}
+	}
+
+	return combo
+}
+
+func bulkRankingMetric(um *proto.UnitMetrics, useHps bool) float64 {
+	if useHps {
+		return um.Hps.Avg
+	}
+	return um.Dps.Avg
+}
+
+// Run a bulk sim, simming every gear combination on a worker pool and ranking the results.
+func runBulkSim(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.BulkSimResult {
+	errorResult := func(format string, args ...any) *proto.BulkSimResult {
+		return &proto.BulkSimResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if request.BaseSettings == nil || request.BaseSettings.Raid == nil || request.BaseSettings.SimOptions == nil {
+		return errorResult("Bulk sim request is missing base settings!")
+	}
+	if request.BulkSettings == nil || len(request.BulkSettings.Items) == 0 {
+		return errorResult("Bulk sim request has no items to sim!")
+	}
+
+	baseRequest := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if len(baseRequest.Raid.Parties) == 0 || len(baseRequest.Raid.Parties[0].Players) == 0 {
+		return errorResult("Bulk sim request has no player!")
+	}
+	basePlayer := baseRequest.Raid.Parties[0].Players[0]
+
+	if request.BulkSettings.IterationsPerCombo > 0 {
+		baseRequest.SimOptions.Iterations = request.BulkSettings.IterationsPerCombo
+	}
+	if baseRequest.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+
+	// All combinations share a seed so that differences come from the gear rather than RNG.
+	if baseRequest.SimOptions.RandomSeed == 0 {
+		baseRequest.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseRequest.SimOptions.UseLabeledRands = true
+	baseRequest.SimOptions.Debug = false
+	baseRequest.SimOptions.DebugFirstIteration = false
+
+	settings := &bulkSimSettings{request.BulkSettings}
+	groups, err := buildBulkSlotGroups(basePlayer, request.BulkSettings)
+	if err != nil {
+		return errorResult("%s", err)
+	}
+
+	numCombinations := countBulkCombinations(groups)
+	if numCombinations > MaxBulkSimCombinations {
+		return errorResult("Too many combinations (%d), the maximum is %d!", numCombinations, MaxBulkSimCombinations)
+	}
+
+	// The first combo is always the currently equipped gear.
+	combos := []*bulkCombo{{player: basePlayer}}
+	for comboIdx := 0; comboIdx < numCombinations; comboIdx++ {
+		combo := settings.buildCombo(basePlayer, groups, comboIdx)
+		if len(combo.itemsAdded) == 0 && googleProto.Equal(combo.player.Equipment, basePlayer.Equipment) {
+			continue
+		}
+		combos = append(combos, combo)
+	}
+
+	simsTotal := int32(len(combos))
+	iterationsTotal := simsTotal * baseRequest.SimOptions.Iterations
+	var simsCompleted int32 = 0
+
+	buildComboRequest := func(comboIdx int) *proto.RaidSimRequest {
+		comboRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		comboRequest.Raid.Parties[0].Players[0] = combos[comboIdx].player
+		return comboRequest
+	}
+
+	comboResults := make([]*proto.BulkComboResult, len(combos))
+	errOutcome := runSimBatch(len(combos), buildComboRequest, func(comboIdx int, result *proto.RaidSimResult) {
+		combo := combos[comboIdx]
+		comboResults[comboIdx] = &proto.BulkComboResult{
+			ItemsAdded:  combo.itemsAdded,
+			Equipment:   combo.player.Equipment,
+			UnitMetrics: result.RaidMetrics.Parties[0].Players[0],
+		}
+
+		simsCompleted++
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     iterationsTotal,
+				CompletedIterations: simsCompleted * baseRequest.SimOptions.Iterations,
+				CompletedSims:       simsCompleted,
+				TotalSims:           simsTotal,
+			}
+		}
+	}, signals)
+	if errOutcome != nil {
+		return &proto.BulkSimResult{Error: errOutcome}
+	}
+
+	equippedResult := comboResults[0]
+	useHps := equippedResult.UnitMetrics.Dps.Avg == 0 && equippedResult.UnitMetrics.Hps.Avg > 0
+	ranked := slices.Clone(comboResults[1:])
+	slices.SortStableFunc(ranked, func(a, b *proto.BulkComboResult) int {
+		metricA := bulkRankingMetric(a.UnitMetrics, useHps)
+		metricB := bulkRankingMetric(b.UnitMetrics, useHps)
+		if metricA > metricB {
+			return -1
+		} else if metricA < metricB {
+			return 1
+		}
+		return 0
+	})
+
+	return &proto.BulkSimResult{
+		Results:            ranked,
+		EquippedGearResult: equippedResult,
+	}
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func bulkTestTrinket(id int32, spellPower float64) Item {
+	return Item{
+		ID:   id,
+		Type: proto.ItemType_ItemTypeTrinket,
+		ScalingOptions: map[int32]*proto.ScalingItemProperties{
+			int32(proto.ItemLevelState_Base): {Stats: stats.Stats{stats.SpellPower: spellPower}.ToProtoMap()},
+		},
+	}
+}
+
+func registerBulkTestItems(t *testing.T) {
+	addTestItems(t,
+		Item{ID: 9000001, Type: proto.ItemType_ItemTypeHead, GemSockets: []proto.GemColor{proto.GemColor_GemColorMeta, proto.GemColor_GemColorRed}},
+		Item{ID: 9000002, Type: proto.ItemType_ItemTypeHead, GemSockets: []proto.GemColor{proto.GemColor_GemColorMeta, proto.GemColor_GemColorBlue}},
+		bulkTestTrinket(9000003, 1000),
+		bulkTestTrinket(9000004, 2000),
+		bulkTestTrinket(9000005, 3000),
+		Item{ID: 9000006, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeTwoHand},
+		Item{ID: 9000007, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOneHand},
+		Item{ID: 9000008, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOffHand, WeaponType: proto.WeaponType_WeaponTypeShield},
+	)
+}
+
+func bulkTestPlayer() *proto.Player {
+	items := make([]*proto.ItemSpec, NumItemSlots)
+	for i := range items {
+		items[i] = &proto.ItemSpec{}
+	}
+	items[proto.ItemSlot_ItemSlotHead] = &proto.ItemSpec{Id: 9000001, Enchant: 123, Gems: []int32{1, 2}, UpgradeStep: proto.ItemLevelState_UpgradeStepTwo}
+	items[proto.ItemSlot_ItemSlotTrinket1] = &proto.ItemSpec{Id: 9000003}
+	items[proto.ItemSlot_ItemSlotTrinket2] = &proto.ItemSpec{Id: 9000004}
+	items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 9000006}
+	return &proto.Player{Equipment: &proto.EquipmentSpec{Items: items}}
+}
+
+func TestBulkSimCombinations(t *testing.T) {
+	registerBulkTestItems(t)
+
+	settings := &proto.BulkSettings{
+		Items: []*proto.ItemSpec{
+			{Id: 9000002},
+			{Id: 9000005},
+			{Id: 9000007},
+			{Id: 9000008},
+		},
+	}
+
+	groups, err := buildBulkSlotGroups(bulkTestPlayer(), settings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// Head: 2 options. Trinkets: 3 choose 2. Weapons: 2H alone, or the 1H with the shield.
+	if numCombos := countBulkCombinations(groups); numCombos != 2*3*2 {
+		t.Fatalf("Expected %d combinations, got %d", 2*3*2, numCombos)
+	}
+}
+
+func TestBulkSimItemSpecForSlot(t *testing.T) {
+	registerBulkTestItems(t)
+
+	settings := &bulkSimSettings{&proto.BulkSettings{
+		DefaultMetaGem:  10,
+		DefaultBlueGem:  11,
+		InheritUpgrades: true,
+	}}
+
+	player := bulkTestPlayer()
+	spec := settings.itemSpecForSlot(getEquippedItemSpec(player, proto.ItemSlot_ItemSlotHead), &proto.ItemSpec{Id: 9000002}, false)
+
+	if !slices.Equal(spec.Gems, []int32{10, 11}) {
+		t.Fatalf("Expected default gems to be applied, got %v", spec.Gems)
+	}
+	if spec.Enchant != 123 {
+		t.Fatalf("Expected enchant to carry over, got %d", spec.Enchant)
+	}
+	if spec.UpgradeStep != proto.ItemLevelState_UpgradeStepTwo {
+		t.Fatalf("Expected upgrade step to carry over, got %s", spec.UpgradeStep)
+	}
+}
+
+func TestBulkSimRanksCombos(t *testing.T) {
+	registerBulkTestItems(t)
+
+	items := make([]*proto.ItemSpec, NumItemSlots)
+	for i := range items {
+		items[i] = &proto.ItemSpec{}
+	}
+	items[proto.ItemSlot_ItemSlotTrinket1] = &proto.ItemSpec{Id: 9000003}
+	items[proto.ItemSlot_ItemSlotTrinket2] = &proto.ItemSpec{Id: 9000004}
+
+	player := &proto.Player{
+		Name:      "Caster",
+		Class:     proto.Class_ClassShaman,
+		Spec:      &proto.Player_ElementalShaman{},
+		Equipment: &proto.EquipmentSpec{Items: items},
+		Rotation:  fakeDotRotation(),
+	}
+
+	result := runBulkSim(&proto.BulkSimRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			Raid: SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+			Encounter: &proto.Encounter{
+				Targets:  []*proto.Target{{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon}},
+				Duration: 60,
+			},
+			SimOptions: &proto.SimOptions{Iterations: 5, RandomSeed: 100},
+		},
+		BulkSettings: &proto.BulkSettings{
+			Items: []*proto.ItemSpec{{Id: 9000005}},
+		},
+	}, nil, simsignals.CreateSignals())
+
+	if result.Error != nil {
+		t.Fatalf("Bulk sim failed: %s", result.Error.Message)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("Expected 2 combinations besides the equipped gear, got %d", len(result.Results))
+	}
+
+	// Each trinket adds spell power to the fake dot, so the best pair wins.
+	best := result.Results[0]
+	if len(best.ItemsAdded) != 1 || best.ItemsAdded[0].Item.Id != 9000005 || best.Equipment.Items[proto.ItemSlot_ItemSlotTrinket1].Id != 9000004 {
+		t.Errorf("Expected the 2000 and 3000 spell power trinkets to rank first, got %v", best.Equipment.Items[proto.ItemSlot_ItemSlotTrinket1:proto.ItemSlot_ItemSlotTrinket2+1])
+	}
+	equippedDps := result.EquippedGearResult.UnitMetrics.Dps.Avg
+	if !(best.UnitMetrics.Dps.Avg > result.Results[1].UnitMetrics.Dps.Avg && result.Results[1].UnitMetrics.Dps.Avg > equippedDps && equippedDps > 0) {
+		t.Errorf("Expected DPS to increase with spell power, got %0.1f, %0.1f and equipped %0.1f", best.UnitMetrics.Dps.Avg, result.Results[1].UnitMetrics.Dps.Avg, equippedDps)
+	}
+}
@@ -20,6 +20,9 @@ type Hardcast struct {
 	OnComplete func(*Simulation, *Unit)
 	Target     *Unit
 	CanMove    bool
+
+	// Whether the cast can be interrupted, see Unit.InterruptCast.
+	Interruptible bool
 }
 
 // Input for constructing the CastSpell function for a spell.
@@ -179,6 +182,14 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
 					spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			}
+			if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+				spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastStart, max(0, spell.CurCast.Cost), spell.CurCast.CastTime)
+			}
+
+			interruptible := spell.Flags.Matches(SpellFlagInterruptible)
+			if interruptible {
+				spell.Unit.Metrics.interruptibleCasts++
+			}
 
 			spell.Unit.Hardcast = Hardcast{
 				Expires:  sim.CurrentTime + spell.CurCast.CastTime,
@@ -187,6 +198,9 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 					if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
 						spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 					}
+					if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+						spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastComplete, 0, 0)
+					}
 
 					if !spell.CanCompleteCast(sim, target, true) {
 						return
@@ -214,8 +228,9 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 						spell.Unit.OnCastComplete(sim, spell)
 					}
 				},
-				Target:  target,
-				CanMove: spell.Flags&SpellFlagCanCastWhileMoving > 0,
+				Target:        target,
+				CanMove:       spell.Flags&SpellFlagCanCastWhileMoving > 0,
+				Interruptible: interruptible,
 			}
 
 			spell.Unit.newHardcastAction(sim)
@@ -227,6 +242,10 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastStart, max(0, spell.CurCast.Cost), spell.CurCast.CastTime)
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastComplete, 0, 0)
+		}
 
 		if spell.Cost != nil {
 			spell.Cost.SpendCost(sim, spell)
@@ -311,6 +330,10 @@ func (spell *Spell) makeCastFuncSimple() CastSuccessFunc {
 				spell.ActionID, 0.0, "0s", "0s")
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastStart, 0, 0)
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastComplete, 0, 0)
+		}
 
 		if spell.MaxCharges > 0 {
 			spell.ConsumeCharge(sim)
@@ -341,6 +364,10 @@ func (spell *Spell) makeCastFuncAutosOrProcs() CastSuccessFunc {
 				spell.ActionID, 0.0, "0s", "0s")
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastStart, 0, 0)
+			spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastComplete, 0, 0)
+		}
 
 		spell.applyEffects(sim, target)
 
@@ -361,6 +388,10 @@ func (spell *Spell) Proc(sim *Simulation, target *Unit) {
 			spell.ActionID, 0.0, "0s", "0s")
 		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastStart, 0, 0)
+		spell.logCastEvent(sim, target, proto.CombatLogEventType_CombatLogEventCastComplete, 0, 0)
+	}
 
 	spell.applyEffects(sim, target)
 
@@ -368,3 +399,13 @@ func (spell *Spell) Proc(sim *Simulation, target *Unit) {
 		spell.Unit.OnCastComplete(sim, spell)
 	}
 }
+
+// Adds a cast start or completion event to the structured combat log.
+func (spell *Spell) logCastEvent(sim *Simulation, target *Unit, eventType proto.CombatLogEventType, cost float64, castTime time.Duration) {
+	spell.Unit.LogEvent(sim, target, &proto.CombatLogEvent{
+		Type:     eventType,
+		ActionId: spell.ActionID.ToProto(),
+		Amount:   cost,
+		CastTime: castTime.Seconds(),
+	})
+}
@@ -392,8 +392,12 @@ func (character *Character) DefaultCritMultiplier() float64 {
 
 func (character *Character) AddRaidBuffs(_ *proto.RaidBuffs) {
 }
+func (character *Character) AddCompositionRaidBuffs(_ *proto.RaidBuffs) {
+}
 func (character *Character) AddPartyBuffs(partyBuffs *proto.PartyBuffs) {
 }
+func (character *Character) AddRaidDebuffs(_ *proto.Debuffs) {
+}
 
 func (character *Character) initialize(agent Agent) {
 	character.majorCooldownManager.initialize(character)
@@ -413,7 +417,7 @@ func (character *Character) initialize(agent Agent) {
 				return
 			}
 
-			if sim.Options.Interactive {
+			if sim.IsInteractive(&character.Unit) {
 				if character.GCD.IsReady(sim) {
 					sim.NeedsInput = true
 				}
@@ -698,6 +702,9 @@ func (character *Character) GetMetricsProto() *proto.UnitMetrics {
 	metrics.Name = character.Name
 	metrics.UnitIndex = character.UnitIndex
 	metrics.Auras = character.auraTracker.GetMetricsProto()
+	if character.Rotation != nil && character.Rotation.profiler != nil {
+		metrics.AplProfile = character.Rotation.profiler.toProto(character.Metrics.dps.n)
+	}
 
 	metrics.Pets = make([]*proto.UnitMetrics, len(character.Pets))
 	for i, pet := range character.Pets {
@@ -739,6 +746,9 @@ func (character *Character) GetMatchingItemProcAuras(statTypesToMatch []stats.St
 	})
 }
 
+// Each talent tier offers a choice of 3 talents.
+const NumTalentTiers = 6
+
 // Uses proto reflection to set fields in a talents proto (e.g. MageTalents,
 // WarriorTalents) based on a talentsStr.
 func FillTalentsProto(data protoreflect.Message, talentsStr string) {
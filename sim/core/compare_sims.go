@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+type compareKey struct {
+	unitName string
+	actionID ActionID
+}
+
+func runCompareSims(request *proto.CompareSimsRequest, signals simsignals.Signals) *proto.CompareSimsResult {
+	errorResult := func(format string, args ...any) *proto.CompareSimsResult {
+		return &proto.CompareSimsResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	for _, simRequest := range []*proto.RaidSimRequest{request.Baseline, request.Variant} {
+		if simRequest == nil || simRequest.Raid == nil || len(simRequest.Raid.Parties) == 0 || len(simRequest.Raid.Parties[0].Players) == 0 {
+			return errorResult("Compare sims request needs a baseline and a variant with at least one player!")
+		}
+	}
+	if request.Baseline.SimOptions == nil || request.Baseline.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+
+	// Both sims use the baseline options, so that iteration i of each sim
+	// starts from the same seed and the DPS values can be paired.
+	simOptions := googleProto.Clone(request.Baseline.SimOptions).(*proto.SimOptions)
+	if simOptions.RandomSeed == 0 {
+		simOptions.RandomSeed = time.Now().UnixNano()
+	}
+	simOptions.UseLabeledRands = true
+	simOptions.SaveAllValues = true
+	simOptions.Debug = false
+	simOptions.DebugFirstIteration = false
+	// Pairing needs both sims to run the same iterations.
+	simOptions.TargetStdevOfMean = 0
+
+	requests := make([]*proto.RaidSimRequest, 2)
+	for i, simRequest := range []*proto.RaidSimRequest{request.Baseline, request.Variant} {
+		requests[i] = googleProto.Clone(simRequest).(*proto.RaidSimRequest)
+		requests[i].SimOptions = simOptions
+	}
+
+	results := make([]*proto.RaidSimResult, 2)
+	done := make(chan int, 2)
+	for i := range requests {
+		go func() {
+			results[i] = RunSim(requests[i], nil, signals)
+			done <- i
+		}()
+	}
+	<-done
+	<-done
+
+	for _, result := range results {
+		if result.Error != nil {
+			return &proto.CompareSimsResult{Error: result.Error}
+		}
+	}
+
+	baselineUnit := results[0].RaidMetrics.Parties[0].Players[0]
+	variantUnit := results[1].RaidMetrics.Parties[0].Players[0]
+	dpsDelta, ci95 := pairedDeltaCI95(baselineUnit.Dps, variantUnit.Dps)
+	iterations := float64(simOptions.Iterations)
+
+	return &proto.CompareSimsResult{
+		Baseline:     results[0],
+		Variant:      results[1],
+		DpsDelta:     dpsDelta,
+		DpsDeltaCi95: ci95,
+		Actions:      compareActions(baselineUnit, variantUnit, iterations),
+		Auras:        compareAuras(baselineUnit, variantUnit),
+	}
+}
+
+// Returns the mean difference between two paired samples and the half-width
+// of its 95% confidence interval. Falls back to the difference of the means
+// when per-iteration values are unavailable.
+func pairedDeltaCI95(baseline *proto.DistributionMetrics, variant *proto.DistributionMetrics) (float64, float64) {
+	n := len(baseline.AllValues)
+	if n == 0 || n != len(variant.AllValues) {
+		return variant.Avg - baseline.Avg, 0
+	}
+
+	var agg aggregator
+	for i := range n {
+		agg.add(variant.AllValues[i] - baseline.AllValues[i])
+	}
+
+	mean, stdev := agg.meanAndStdDev()
+	if n < 2 || math.IsNaN(stdev) {
+		// Rounding can make the variance slightly negative when all the
+		// differences are equal.
+		return mean, 0
+	}
+
+	return mean, 1.96 * stdev / math.Sqrt(float64(n))
+}
+
+// Calls f for the unit and each of its pets.
+func forEachUnitMetrics(unit *proto.UnitMetrics, f func(unit *proto.UnitMetrics)) {
+	f(unit)
+	for _, pet := range unit.Pets {
+		f(pet)
+	}
+}
+
+func compareActions(baseline *proto.UnitMetrics, variant *proto.UnitMetrics, iterations float64) []*proto.ActionDelta {
+	var deltas []*proto.ActionDelta
+	deltasByKey := map[compareKey]*proto.ActionDelta{}
+
+	getDelta := func(unitName string, id *proto.ActionID) *proto.ActionDelta {
+		key := compareKey{unitName: unitName, actionID: ProtoToActionID(id)}
+		if delta, ok := deltasByKey[key]; ok {
+			return delta
+		}
+		delta := &proto.ActionDelta{Id: id, UnitName: unitName}
+		deltasByKey[key] = delta
+		deltas = append(deltas, delta)
+		return delta
+	}
+
+	totals := func(action *proto.ActionMetrics) (float64, float64) {
+		var damage, casts float64
+		for _, tam := range action.Targets {
+			damage += tam.Damage
+			casts += float64(tam.Casts)
+		}
+		return damage / iterations, casts / iterations
+	}
+
+	forEachUnitMetrics(baseline, func(unit *proto.UnitMetrics) {
+		for _, action := range unit.Actions {
+			delta := getDelta(unit.Name, action.Id)
+			delta.BaselineDamage, delta.BaselineCasts = totals(action)
+		}
+	})
+	forEachUnitMetrics(variant, func(unit *proto.UnitMetrics) {
+		for _, action := range unit.Actions {
+			delta := getDelta(unit.Name, action.Id)
+			delta.VariantDamage, delta.VariantCasts = totals(action)
+		}
+	})
+
+	// Biggest changes first.
+	slices.SortStableFunc(deltas, func(a, b *proto.ActionDelta) int {
+		return compareAbsDesc(a.VariantDamage-a.BaselineDamage, b.VariantDamage-b.BaselineDamage)
+	})
+	return deltas
+}
+
+func compareAuras(baseline *proto.UnitMetrics, variant *proto.UnitMetrics) []*proto.AuraDelta {
+	var deltas []*proto.AuraDelta
+	deltasByKey := map[compareKey]*proto.AuraDelta{}
+
+	getDelta := func(unitName string, id *proto.ActionID) *proto.AuraDelta {
+		key := compareKey{unitName: unitName, actionID: ProtoToActionID(id)}
+		if delta, ok := deltasByKey[key]; ok {
+			return delta
+		}
+		delta := &proto.AuraDelta{Id: id, UnitName: unitName}
+		deltasByKey[key] = delta
+		deltas = append(deltas, delta)
+		return delta
+	}
+
+	forEachUnitMetrics(baseline, func(unit *proto.UnitMetrics) {
+		for _, aura := range unit.Auras {
+			getDelta(unit.Name, aura.Id).BaselineUptimeSeconds = aura.UptimeSecondsAvg
+		}
+	})
+	forEachUnitMetrics(variant, func(unit *proto.UnitMetrics) {
+		for _, aura := range unit.Auras {
+			getDelta(unit.Name, aura.Id).VariantUptimeSeconds = aura.UptimeSecondsAvg
+		}
+	})
+
+	slices.SortStableFunc(deltas, func(a, b *proto.AuraDelta) int {
+		return compareAbsDesc(a.VariantUptimeSeconds-a.BaselineUptimeSeconds, b.VariantUptimeSeconds-b.BaselineUptimeSeconds)
+	})
+	return deltas
+}
+
+func compareAbsDesc(a float64, b float64) int {
+	if math.Abs(a) > math.Abs(b) {
+		return -1
+	} else if math.Abs(a) < math.Abs(b) {
+		return 1
+	}
+	return 0
+}
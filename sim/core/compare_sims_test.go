@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestPairedDeltaCI95(t *testing.T) {
+	baseline := &proto.DistributionMetrics{AllValues: []float64{100, 110, 90, 105}}
+	variant := &proto.DistributionMetrics{AllValues: []float64{102, 112, 92, 107}}
+
+	// Common random numbers mean the noise cancels out, leaving only the constant difference.
+	delta, ci95 := pairedDeltaCI95(baseline, variant)
+	if !WithinToleranceFloat64(2, delta, 0.0001) {
+		t.Fatalf("Expected a delta of 2, got %0.4f", delta)
+	}
+	if !WithinToleranceFloat64(0, ci95, 0.0001) {
+		t.Fatalf("Expected no uncertainty for a constant difference, got %0.4f", ci95)
+	}
+
+	variant.AllValues = []float64{101, 113, 92, 106}
+	delta, ci95 = pairedDeltaCI95(baseline, variant)
+	if !WithinToleranceFloat64(1.75, delta, 0.0001) || ci95 <= 0 {
+		t.Fatalf("Unexpected delta %0.4f ± %0.4f", delta, ci95)
+	}
+}
+
+func TestCompareActions(t *testing.T) {
+	spellA := ActionID{SpellID: 1}.ToProto()
+	spellB := ActionID{SpellID: 2}.ToProto()
+
+	baseline := &proto.UnitMetrics{
+		Name: "Player",
+		Actions: []*proto.ActionMetrics{
+			{Id: spellA, Targets: []*proto.TargetedActionMetrics{{Casts: 20, Damage: 2000}}},
+		},
+		Pets: []*proto.UnitMetrics{{
+			Name:    "Pet",
+			Actions: []*proto.ActionMetrics{{Id: spellA, Targets: []*proto.TargetedActionMetrics{{Casts: 10, Damage: 500}}}},
+		}},
+	}
+	variant := &proto.UnitMetrics{
+		Name: "Player",
+		Actions: []*proto.ActionMetrics{
+			{Id: spellA, Targets: []*proto.TargetedActionMetrics{{Casts: 20, Damage: 2200}}},
+			{Id: spellB, Targets: []*proto.TargetedActionMetrics{{Casts: 10, Damage: 1000}}},
+		},
+	}
+
+	deltas := compareActions(baseline, variant, 10)
+	if len(deltas) != 3 {
+		t.Fatalf("Expected 3 action deltas, got %d", len(deltas))
+	}
+
+	// Sorted by the size of the damage change.
+	if deltas[0].UnitName != "Player" || deltas[0].Id.GetSpellId() != 2 || deltas[0].VariantDamage != 100 || deltas[0].BaselineCasts != 0 {
+		t.Fatalf("Unexpected first delta: %v", deltas[0])
+	}
+	if deltas[1].UnitName != "Pet" || deltas[1].BaselineDamage != 50 || deltas[1].VariantDamage != 0 {
+		t.Fatalf("Unexpected second delta: %v", deltas[1])
+	}
+	if deltas[2].BaselineDamage != 200 || deltas[2].VariantDamage != 220 || deltas[2].VariantCasts != 2 {
+		t.Fatalf("Unexpected third delta: %v", deltas[2])
+	}
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Width of the buckets in health timelines and time to death histograms.
+const HealthTimelineBucketSize = time.Second * 5
+
+// Lowest health in each bucket is binned in steps of 5%.
+const numHealthTimelineBins = 21
+
+// Totals across all iterations of the damage taken from one source.
+type damageTakenMetrics struct {
+	spellSchool SpellSchool
+
+	hits    int32
+	crits   int32
+	blocks  int32
+	dodges  int32
+	parries int32
+	misses  int32
+
+	damage         float64
+	armorMitigated float64
+	blocked        float64
+	absorbed       float64
+	avoided        float64
+}
+
+func (unitMetrics *UnitMetrics) addDamageTaken(spell *Spell, result *SpellResult) {
+	if unitMetrics.damageTaken == nil {
+		unitMetrics.damageTaken = make(map[ActionID]*damageTakenMetrics)
+	}
+	dtm, ok := unitMetrics.damageTaken[spell.ActionID]
+	if !ok {
+		dtm = &damageTakenMetrics{spellSchool: spell.SpellSchool}
+		unitMetrics.damageTaken[spell.ActionID] = dtm
+	}
+
+	switch {
+	case result.Outcome.Matches(OutcomeDodge):
+		dtm.dodges++
+		dtm.avoided += result.PreOutcomeDamage
+		return
+	case result.Outcome.Matches(OutcomeParry):
+		dtm.parries++
+		dtm.avoided += result.PreOutcomeDamage
+		return
+	case result.Outcome.Matches(OutcomeMiss):
+		dtm.misses++
+		dtm.avoided += result.PreOutcomeDamage
+		return
+	case !result.Landed():
+		return
+	}
+
+	dtm.hits++
+	if result.DidCrit() {
+		dtm.crits++
+	}
+	if result.DidBlock() {
+		dtm.blocks++
+		dtm.blocked += result.BlockedDamage
+	}
+
+	dtm.damage += result.Damage
+	dtm.absorbed += max(0, result.PostOutcomeDamage-result.Damage)
+	// Armor is applied before the outcome, so crits and blocks don't change
+	// how much it prevented.
+	if result.PreOutcomeDamage > 0 && result.ArmorMultiplier > 0 {
+		dtm.armorMitigated += result.PreOutcomeDamage * (1/result.ArmorMultiplier - 1)
+	}
+}
+
+func (unitMetrics *UnitMetrics) damageTakenToProto(numIterations float64) []*proto.DamageTakenMetrics {
+	// Sorted by source, so the output doesn't depend on map order.
+	actionIDs := slices.SortedFunc(maps.Keys(unitMetrics.damageTaken), func(a, b ActionID) int {
+		return cmp.Or(
+			cmp.Compare(a.SpellID, b.SpellID),
+			cmp.Compare(a.ItemID, b.ItemID),
+			cmp.Compare(a.OtherID, b.OtherID),
+			cmp.Compare(a.Tag, b.Tag),
+		)
+	})
+
+	damageTaken := make([]*proto.DamageTakenMetrics, 0, len(actionIDs))
+	for _, actionID := range actionIDs {
+		dtm := unitMetrics.damageTaken[actionID]
+		damageTaken = append(damageTaken, &proto.DamageTakenMetrics{
+			Id:          actionID.ToProto(),
+			SpellSchool: int32(dtm.spellSchool),
+
+			HitsAvg:    float64(dtm.hits) / numIterations,
+			CritsAvg:   float64(dtm.crits) / numIterations,
+			BlocksAvg:  float64(dtm.blocks) / numIterations,
+			DodgesAvg:  float64(dtm.dodges) / numIterations,
+			ParriesAvg: float64(dtm.parries) / numIterations,
+			MissesAvg:  float64(dtm.misses) / numIterations,
+
+			DamageAvg:         dtm.damage / numIterations,
+			ArmorMitigatedAvg: dtm.armorMitigated / numIterations,
+			BlockedAvg:        dtm.blocked / numIterations,
+			AbsorbedAvg:       dtm.absorbed / numIterations,
+			AvoidedAvg:        dtm.avoided / numIterations,
+		})
+	}
+	return damageTaken
+}
+
+// Tracks the lowest health of a tank in each bucket of the fight, and when it died.
+type healthTimeline struct {
+	// Values for the current iteration, as fractions of maximum health.
+	current float64
+	lowest  []float64
+
+	// Aggregate values, indexed by bucket.
+	samples   []int32
+	lowestSum []float64
+	hist      [][numHealthTimelineBins]int32
+	deaths    []int32
+}
+
+func newHealthTimeline() *healthTimeline {
+	return &healthTimeline{current: 1}
+}
+
+func (timeline *healthTimeline) reset() {
+	timeline.current = 1
+	timeline.lowest = timeline.lowest[:0]
+}
+
+// Buckets include their end time, so that events at the very end of the
+// fight don't start a new bucket.
+func healthTimelineBucket(at time.Duration) int {
+	return int(max(at-1, 0) / HealthTimelineBucketSize)
+}
+
+// Fills buckets without health changes with the current health.
+func (timeline *healthTimeline) fillTo(bucket int) {
+	for len(timeline.lowest) <= bucket {
+		timeline.lowest = append(timeline.lowest, timeline.current)
+	}
+}
+
+func (timeline *healthTimeline) recordHealth(sim *Simulation, health float64, maxHealth float64) {
+	fraction := 0.0
+	if maxHealth > 0 {
+		fraction = Clamp(health/maxHealth, 0, 1)
+	}
+	if sim.CurrentTime >= 0 {
+		bucket := healthTimelineBucket(sim.CurrentTime)
+		timeline.fillTo(bucket)
+		timeline.lowest[bucket] = min(timeline.lowest[bucket], fraction)
+	}
+	timeline.current = fraction
+}
+
+func (timeline *healthTimeline) died(sim *Simulation) {
+	bucket := healthTimelineBucket(sim.CurrentTime)
+	for len(timeline.deaths) <= bucket {
+		timeline.deaths = append(timeline.deaths, 0)
+	}
+	timeline.deaths[bucket]++
+}
+
+func (timeline *healthTimeline) doneIteration(sim *Simulation) {
+	timeline.fillTo(healthTimelineBucket(sim.CurrentTime))
+
+	for len(timeline.samples) < len(timeline.lowest) {
+		timeline.samples = append(timeline.samples, 0)
+		timeline.lowestSum = append(timeline.lowestSum, 0)
+		timeline.hist = append(timeline.hist, [numHealthTimelineBins]int32{})
+	}
+	for i, lowest := range timeline.lowest {
+		timeline.samples[i]++
+		timeline.lowestSum[i] += lowest
+		timeline.hist[i][int(math.Round(lowest*(numHealthTimelineBins-1)))]++
+	}
+}
+
+func (timeline *healthTimeline) toProto() *proto.HealthTimeline {
+	timelineProto := &proto.HealthTimeline{
+		BucketSeconds: HealthTimelineBucketSize.Seconds(),
+		DeathTimeHist: timeline.deaths,
+	}
+	for i, samples := range timeline.samples {
+		timelineProto.Buckets = append(timelineProto.Buckets, &proto.HealthTimelineBucket{
+			Samples:                 samples,
+			LowestHealthPercentAvg:  timeline.lowestSum[i] / float64(samples) * 100,
+			LowestHealthPercentHist: timeline.hist[i][:],
+		})
+	}
+	return timelineProto
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func tankSimRequest(health float64) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			RandomSeed: 100,
+		},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Tank",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_RestorationShaman{},
+							Equipment: &proto.EquipmentSpec{},
+							Rotation:  &proto.APLRotation{},
+							BonusStats: &proto.UnitStats{
+								Stats: stats.Stats{stats.Health: health, stats.Armor: 20000, stats.DodgeRating: 5000}.ToProtoArray(),
+							},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+			Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{
+					Name:          "target",
+					Level:         93,
+					MobType:       proto.MobType_MobTypeDemon,
+					MinBaseDamage: 5000,
+					SwingSpeed:    2,
+				},
+			},
+			Duration: 60,
+		},
+	}
+}
+
+func TestDamageTakenBreakdown(t *testing.T) {
+	result := RunSim(tankSimRequest(1_000_000), nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	tank := result.RaidMetrics.Parties[0].Players[0]
+
+	if len(tank.DamageTaken) != 1 {
+		t.Fatalf("Expected damage taken from 1 source, got %d", len(tank.DamageTaken))
+	}
+	melee := tank.DamageTaken[0]
+	if !WithinToleranceFloat64(tank.Dtps.Avg*60, melee.DamageAvg, 0.01) {
+		t.Fatalf("Expected melee damage taken %0.3f to match DTPS, got %0.3f", tank.Dtps.Avg*60, melee.DamageAvg)
+	}
+	if melee.ArmorMitigatedAvg <= 0 {
+		t.Fatalf("Expected damage mitigated by armor")
+	}
+	if melee.DodgesAvg <= 0 || melee.AvoidedAvg <= 0 {
+		t.Fatalf("Expected dodged damage, got %0.3f dodges for %0.3f damage", melee.DodgesAvg, melee.AvoidedAvg)
+	}
+	// One swing every 2s from the start of the fight.
+	if swings := melee.HitsAvg + melee.DodgesAvg + melee.ParriesAvg + melee.MissesAvg; !WithinToleranceFloat64(31, swings, 0.01) {
+		t.Fatalf("Expected 31 swings, got %0.3f", swings)
+	}
+
+	timeline := tank.HealthTimeline
+	if timeline == nil || len(timeline.Buckets) != 12 {
+		t.Fatalf("Expected a health timeline with 12 buckets, got %v", timeline)
+	}
+	// Without healing, health only goes down.
+	for i := 1; i < len(timeline.Buckets); i++ {
+		if timeline.Buckets[i].LowestHealthPercentAvg > timeline.Buckets[i-1].LowestHealthPercentAvg {
+			t.Fatalf("Expected lowest health to decrease, bucket %d has %0.3f%% after %0.3f%%", i, timeline.Buckets[i].LowestHealthPercentAvg, timeline.Buckets[i-1].LowestHealthPercentAvg)
+		}
+	}
+	if len(timeline.DeathTimeHist) != 0 {
+		t.Fatalf("Expected no deaths, got %v", timeline.DeathTimeHist)
+	}
+}
+
+func TestHealthTimelineDeaths(t *testing.T) {
+	result := RunSim(tankSimRequest(50_000), nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	tank := result.RaidMetrics.Parties[0].Players[0]
+
+	deaths := int32(0)
+	for _, count := range tank.HealthTimeline.DeathTimeHist {
+		deaths += count
+	}
+	if tank.ChanceOfDeath != 1 || deaths != 20 {
+		t.Fatalf("Expected a death in all 20 iterations, got %d with chance of death %0.3f", deaths, tank.ChanceOfDeath)
+	}
+
+	last := tank.HealthTimeline.Buckets[len(tank.HealthTimeline.Buckets)-1]
+	if last.LowestHealthPercentAvg != 0 || last.LowestHealthPercentHist[0] != last.Samples {
+		t.Fatalf("Expected no health at the end of the fight, got %0.3f%%", last.LowestHealthPercentAvg)
+	}
+}
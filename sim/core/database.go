@@ -186,6 +186,12 @@ type Item struct {
 	UpgradeStep    proto.ItemLevelState
 	ItemEffect     *proto.ItemEffect
 	ChallengeMode  bool
+
+	// Equip restrictions, only used for validation.
+	Unique             bool
+	LimitCategory      int32
+	ClassAllowlist     []proto.Class
+	RequiredProfession proto.Profession
 }
 
 func ItemFromProto(pData *proto.SimItem) Item {
@@ -204,6 +210,11 @@ func ItemFromProto(pData *proto.SimItem) Item {
 		SetID:            pData.SetId,
 		ScalingOptions:   pData.ScalingOptions,
 		ItemEffect:       pData.ItemEffect,
+
+		Unique:             pData.Unique,
+		LimitCategory:      pData.LimitCategory,
+		ClassAllowlist:     pData.ClassAllowlist,
+		RequiredProfession: pData.RequiredProfession,
 	}
 }
 
@@ -248,6 +259,9 @@ type Enchant struct {
 	EnchantEffect *proto.ItemEffect
 	Name          string         // Only needed for unit tests
 	Type          proto.ItemType // Only needed for unit tests
+
+	ClassAllowlist     []proto.Class
+	RequiredProfession proto.Profession
 }
 
 func EnchantFromProto(pData *proto.SimEnchant) Enchant {
@@ -257,6 +271,9 @@ func EnchantFromProto(pData *proto.SimEnchant) Enchant {
 		EnchantEffect: pData.EnchantEffect,
 		Name:          pData.Name,
 		Type:          pData.Type,
+
+		ClassAllowlist:     pData.ClassAllowlist,
+		RequiredProfession: pData.RequiredProfession,
 	}
 }
 
@@ -266,6 +283,8 @@ type Gem struct {
 	Stats                   stats.Stats
 	Color                   proto.GemColor
 	DisabledInChallengeMode bool
+	Unique                  bool
+	RequiredProfession      proto.Profession
 }
 
 func GemFromProto(pData *proto.SimGem) Gem {
@@ -275,6 +294,8 @@ func GemFromProto(pData *proto.SimGem) Gem {
 		Stats:                   stats.FromProtoArray(pData.Stats),
 		Color:                   pData.Color,
 		DisabledInChallengeMode: pData.DisabledInChallengeMode,
+		Unique:                  pData.Unique,
+		RequiredProfession:      pData.RequiredProfession,
 	}
 }
 
@@ -619,10 +640,7 @@ func (equipment *Equipment) Stats(spec proto.Spec) stats.Stats {
 	secondaries := []stats.Stat{stats.CritRating, stats.HasteRating, stats.MasteryRating, stats.DodgeRating, stats.ParryRating}
 
 	fixedStats := []stats.Stat{stats.HitRating, stats.ExpertiseRating}
-	switch spec {
-	case proto.Spec_SpecElementalShaman,
-		proto.Spec_SpecShadowPriest,
-		proto.Spec_SpecBalanceDruid:
+	if SpiritIsHitRating(spec) {
 		fixedStats = append(fixedStats, stats.Spirit)
 	}
 
@@ -675,6 +693,17 @@ func (equipment *Equipment) Stats(spec proto.Spec) stats.Stats {
 	return equipStats
 }
 
+// Whether Spirit from gear also counts as hit rating for this spec.
+func SpiritIsHitRating(spec proto.Spec) bool {
+	switch spec {
+	case proto.Spec_SpecElementalShaman,
+		proto.Spec_SpecShadowPriest,
+		proto.Spec_SpecBalanceDruid:
+		return true
+	}
+	return false
+}
+
 // Returns the base stats on the equipment. That is all stats without Gems / Enchants
 func ItemEquipmentBaseStats(item Item) stats.Stats {
 	equipStats := stats.Stats{}
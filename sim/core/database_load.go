@@ -40,6 +40,11 @@ func init() {
 			SetId:            item.SetId,
 			ScalingOptions:   item.ScalingOptions,
 			ItemEffect:       item.ItemEffect,
+
+			Unique:             item.Unique,
+			LimitCategory:      item.LimitCategory,
+			ClassAllowlist:     item.ClassAllowlist,
+			RequiredProfession: item.RequiredProfession,
 		}
 	}
 
@@ -58,6 +63,9 @@ func init() {
 			EnchantEffect: enchant.EnchantEffect,
 			Name:          enchant.Name,
 			Type:          enchant.Type,
+
+			ClassAllowlist:     enchant.ClassAllowlist,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 
@@ -68,6 +76,8 @@ func init() {
 			Color:                   gem.Color,
 			Stats:                   gem.Stats,
 			DisabledInChallengeMode: gem.DisabledInChallengeMode,
+			Unique:                  gem.Unique,
+			RequiredProfession:      gem.RequiredProfession,
 		}
 	}
 
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+)
+
+// Adds fake items to the database for the duration of the test.
+func addTestItems(t *testing.T, items ...Item) {
+	for _, item := range items {
+		ItemsByID[item.ID] = item
+	}
+	t.Cleanup(func() {
+		for _, item := range items {
+			delete(ItemsByID, item.ID)
+		}
+	})
+}
+
+// Adds fake reforges to the database for the duration of the test.
+func addTestReforges(t *testing.T, reforges ...ReforgeStat) {
+	for _, reforge := range reforges {
+		ReforgeStatsByID[reforge.ID] = reforge
+	}
+	t.Cleanup(func() {
+		for _, reforge := range reforges {
+			delete(ReforgeStatsByID, reforge.ID)
+		}
+	})
+}
+
+// Adds fake gems to the database for the duration of the test.
+func addTestGems(t *testing.T, gems ...Gem) {
+	for _, gem := range gems {
+		GemsByID[gem.ID] = gem
+	}
+	t.Cleanup(func() {
+		for _, gem := range gems {
+			delete(GemsByID, gem.ID)
+		}
+	})
+}
+
+// Adds a fake meta gem activation requirement for the duration of the test.
+func addTestMetaGemCondition(t *testing.T, gemID int32, condition metaGemCondition) {
+	metaGemConditions[gemID] = condition
+	t.Cleanup(func() {
+		delete(metaGemConditions, gemID)
+	})
+}
@@ -0,0 +1,418 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultDefensivePlanTimeStep = time.Second * 5
+	DefaultDefensivePlanPasses   = 2
+)
+
+type defensivePlanCooldown struct {
+	actionID ActionID
+	cooldown time.Duration
+}
+
+// Usage times of each cooldown, indexed by cooldown and then by usage slot.
+// Unused slots are negative.
+type defensivePlan [][]time.Duration
+
+func (plan defensivePlan) clone() defensivePlan {
+	return MapSlice(plan, func(timings []time.Duration) []time.Duration {
+		return append([]time.Duration(nil), timings...)
+	})
+}
+
+func (plan defensivePlan) key() string {
+	var sb strings.Builder
+	for _, timings := range plan {
+		for _, timing := range timings {
+			sb.WriteString(strconv.FormatInt(int64(timing), 10))
+			sb.WriteByte(',')
+		}
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
+
+func (plan defensivePlan) usedTimings(cooldownIdx int) []time.Duration {
+	return FilterSlice(plan[cooldownIdx], func(timing time.Duration) bool { return timing >= 0 })
+}
+
+type defensivePlanScore struct {
+	tmi           float64
+	chanceOfDeath float64
+}
+
+func defensivePlanScoreFromResult(result *proto.RaidSimResult) defensivePlanScore {
+	playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+	return defensivePlanScore{
+		tmi:           playerMetrics.Tmi.Avg,
+		chanceOfDeath: playerMetrics.ChanceOfDeath,
+	}
+}
+
+// Returns the cooldowns to plan and how often each can be used.
+func defensivePlanCooldowns(request *proto.DefensivePlanRequest, baseRequest *proto.RaidSimRequest) (cooldowns []defensivePlanCooldown, errorOutcome *proto.ErrorOutcome) {
+	// Invalid settings panic while building the environment.
+	defer func() {
+		if err := recover(); err != nil {
+			cooldowns = nil
+			errorOutcome = ErrorOutcomeFromPanic(err)
+		}
+	}()
+	errorResult := func(format string, args ...any) *proto.ErrorOutcome {
+		return &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}
+	}
+
+	// The rotation's own casts would remove the cooldowns from the major
+	// cooldowns, so they're looked up without it.
+	envRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+	envRequest.Raid.Parties[0].Players[0].Rotation = &proto.APLRotation{}
+	env, _, _ := NewEnvironment(envRequest.Raid, envRequest.Encounter, false)
+	character := env.Raid.Parties[0].Players[0].GetCharacter()
+	if !character.Metrics.IsTanking() {
+		return nil, errorResult("Player isn't tanking any target!")
+	}
+
+	var spells []*Spell
+	if len(request.Cooldowns) == 0 {
+		for _, mcd := range character.initialMajorCooldowns {
+			if mcd.Type.Matches(CooldownTypeSurvival) {
+				spells = append(spells, mcd.Spell)
+			}
+		}
+		if len(spells) == 0 {
+			return nil, errorResult("Player has no survival cooldowns to plan!")
+		}
+	} else {
+		for _, cooldownID := range request.Cooldowns {
+			actionID := ProtoToActionID(cooldownID)
+			spell := character.GetSpell(actionID)
+			if spell == nil {
+				return nil, errorResult("Player has no spell %s!", actionID)
+			}
+			spells = append(spells, spell)
+		}
+	}
+
+	return MapSlice(spells, func(spell *Spell) defensivePlanCooldown {
+		return defensivePlanCooldown{
+			actionID: spell.ActionID,
+			cooldown: max(spell.CD.Duration, spell.SharedCD.Duration),
+		}
+	}), nil
+}
+
+// Returns whether the action casts one of the cooldowns anywhere within it,
+// e.g. inside a sequence.
+func aplActionCastsCooldown(msg protoreflect.Message, cooldowns []defensivePlanCooldown) bool {
+	var spellID *proto.ActionID
+	switch action := msg.Interface().(type) {
+	case *proto.APLActionCastSpell:
+		spellID = action.SpellId
+	case *proto.APLActionCastFriendlySpell:
+		spellID = action.SpellId
+	}
+	if spellID != nil {
+		actionID := ProtoToActionID(spellID)
+		return slices.ContainsFunc(cooldowns, func(cooldown defensivePlanCooldown) bool { return cooldown.actionID == actionID })
+	}
+
+	found := false
+	msg.Range(func(field protoreflect.FieldDescriptor, fieldValue protoreflect.Value) bool {
+		switch {
+		case field.Message() == nil || field.IsMap():
+		case field.IsList():
+			list := fieldValue.List()
+			for i := 0; i < list.Len() && !found; i++ {
+				found = aplActionCastsCooldown(list.Get(i).Message(), cooldowns)
+			}
+		default:
+			found = aplActionCastsCooldown(fieldValue.Message(), cooldowns)
+		}
+		return !found
+	})
+	return found
+}
+
+// Returns one schedule action per cooldown with at least one usage, and a
+// cast which never fires for each unused cooldown.
+func (plan defensivePlan) scheduleActions(cooldowns []defensivePlanCooldown) []*proto.APLAction {
+	var actions []*proto.APLAction
+	for i, cooldown := range cooldowns {
+		castAction := &proto.APLAction{
+			Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: cooldown.actionID.ToProto()}},
+		}
+		timings := plan.usedTimings(i)
+		if len(timings) == 0 {
+			// Never cast, but referenced so the cooldown isn't autocast either.
+			castAction.Condition = &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "false"}}}
+			actions = append(actions, castAction)
+			continue
+		}
+		timingStrs := MapSlice(timings, func(timing time.Duration) string {
+			return strconv.FormatFloat(timing.Seconds(), 'f', -1, 64) + "s"
+		})
+		actions = append(actions, &proto.APLAction{
+			Action: &proto.APLAction_Schedule{Schedule: &proto.APLActionSchedule{
+				Schedule:    strings.Join(timingStrs, ", "),
+				InnerAction: castAction,
+			}},
+		})
+	}
+	return actions
+}
+
+// Searches for the usage times of a tank's defensive cooldowns which minimize
+// TMI or chance of death, using coordinate descent: each pass tries every
+// time for one usage at a time while holding the others at their best times
+// so far.
+func runDefensivePlan(request *proto.DefensivePlanRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.DefensivePlanResult {
+	errorResult := func(format string, args ...any) *proto.DefensivePlanResult {
+		return &proto.DefensivePlanResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if request.BaseSettings == nil || request.BaseSettings.Raid == nil || request.BaseSettings.SimOptions == nil || request.BaseSettings.Encounter == nil {
+		return errorResult("Defensive plan request is missing base settings!")
+	}
+	baseRequest := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if len(baseRequest.Raid.Parties) == 0 || len(baseRequest.Raid.Parties[0].Players) == 0 {
+		return errorResult("Defensive plan request has no player!")
+	}
+	if baseRequest.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+	player := baseRequest.Raid.Parties[0].Players[0]
+	if player.HealingModel == nil {
+		return errorResult("Player needs a healing model!")
+	}
+	if request.Objective == proto.DefensivePlanObjective_DefensivePlanObjectiveTmi && player.HealingModel.BurstWindow <= 0 {
+		return errorResult("Healing model needs a burst window to compute TMI!")
+	}
+	if player.Rotation == nil {
+		player.Rotation = &proto.APLRotation{}
+	}
+	rotation := player.Rotation
+
+	cooldowns, errorOutcome := defensivePlanCooldowns(request, baseRequest)
+	if errorOutcome != nil {
+		return &proto.DefensivePlanResult{Error: errorOutcome}
+	}
+
+	timeStep := DefaultDefensivePlanTimeStep
+	if request.TimeStepSeconds > 0 {
+		timeStep = DurationFromSeconds(request.TimeStepSeconds)
+	}
+	maxPasses := request.MaxPasses
+	if maxPasses <= 0 {
+		maxPasses = DefaultDefensivePlanPasses
+	}
+	duration := DurationFromSeconds(baseRequest.Encounter.Duration)
+
+	// Every sim uses the same seed and labeled RNG, so that boss damage spikes
+	// land at the same times for every plan.
+	if baseRequest.SimOptions.RandomSeed == 0 {
+		baseRequest.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseRequest.SimOptions.UseLabeledRands = true
+	baseRequest.SimOptions.TargetStdevOfMean = 0
+	baseRequest.SimOptions.Debug = false
+	baseRequest.SimOptions.DebugFirstIteration = false
+
+	// The planned cooldowns are only cast by the schedule, so the rotation's
+	// own casts of them are hidden.
+	buildRotation := func(plan defensivePlan) *proto.APLRotation {
+		plannedRotation := googleProto.Clone(rotation).(*proto.APLRotation)
+		hideCooldownCasts := func(items []*proto.APLListItem) {
+			for _, item := range items {
+				if item.Action != nil && aplActionCastsCooldown(item.Action.ProtoReflect(), cooldowns) {
+					item.Hide = true
+				}
+			}
+		}
+		hideCooldownCasts(plannedRotation.PriorityList)
+		for _, group := range plannedRotation.Groups {
+			hideCooldownCasts(group.Actions)
+		}
+
+		schedule := MapSlice(plan.scheduleActions(cooldowns), func(action *proto.APLAction) *proto.APLListItem {
+			return &proto.APLListItem{Action: action}
+		})
+		plannedRotation.PriorityList = append(schedule, plannedRotation.PriorityList...)
+		return plannedRotation
+	}
+
+	// Every cooldown starts out being used on cooldown from the pull.
+	initialPlan := make(defensivePlan, len(cooldowns))
+	numCandidates := 0
+	for i, cooldown := range cooldowns {
+		if cooldown.cooldown <= 0 {
+			return errorResult("%s has no cooldown!", cooldown.actionID)
+		}
+		numSlots := int((duration-1)/cooldown.cooldown) + 1
+		for slot := range numSlots {
+			initialPlan[i] = append(initialPlan[i], time.Duration(slot)*cooldown.cooldown)
+		}
+		numCandidates += numSlots * (int(duration/timeStep) + 2)
+	}
+
+	simsTotal := int32(2 + numCandidates*int(maxPasses))
+	var simsRun int32 = 0
+
+	cache := map[string]defensivePlanScore{}
+
+	reportProgress := func() {
+		simsRun++
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     simsTotal * baseRequest.SimOptions.Iterations,
+				CompletedIterations: simsRun * baseRequest.SimOptions.Iterations,
+				CompletedSims:       simsRun,
+				TotalSims:           simsTotal,
+			}
+		}
+	}
+
+	buildRequest := func(rotation *proto.APLRotation) *proto.RaidSimRequest {
+		planRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		planRequest.Raid.Parties[0].Players[0].Rotation = rotation
+		return planRequest
+	}
+
+	// Returns the score of each plan.
+	evaluate := func(plans []defensivePlan) ([]defensivePlanScore, *proto.ErrorOutcome) {
+		var queued []defensivePlan
+		queuedKeys := map[string]bool{}
+		for _, plan := range plans {
+			key := plan.key()
+			if _, ok := cache[key]; !ok && !queuedKeys[key] {
+				queuedKeys[key] = true
+				queued = append(queued, plan)
+			}
+		}
+
+		errorOutcome := runSimBatch(len(queued), func(planIdx int) *proto.RaidSimRequest {
+			return buildRequest(buildRotation(queued[planIdx]))
+		}, func(planIdx int, result *proto.RaidSimResult) {
+			cache[queued[planIdx].key()] = defensivePlanScoreFromResult(result)
+			reportProgress()
+		}, signals)
+		if errorOutcome != nil {
+			return nil, errorOutcome
+		}
+
+		return MapSlice(plans, func(plan defensivePlan) defensivePlanScore { return cache[plan.key()] }), nil
+	}
+
+	isBetter := func(score defensivePlanScore, best defensivePlanScore) bool {
+		if request.Objective == proto.DefensivePlanObjective_DefensivePlanObjectiveChanceOfDeath && score.chanceOfDeath != best.chanceOfDeath {
+			return score.chanceOfDeath < best.chanceOfDeath
+		}
+		return score.tmi < best.tmi
+	}
+
+	// Returns the plans to try for one usage, holding the others fixed. Usages
+	// of the same cooldown stay in order and at least a cooldown apart.
+	slotPlans := func(current defensivePlan, cooldownIdx int, slot int) []defensivePlan {
+		cooldown := cooldowns[cooldownIdx].cooldown
+		earliest, latest := time.Duration(0), duration-1
+		for i, timing := range current[cooldownIdx] {
+			if i < slot && timing >= 0 {
+				earliest = timing + cooldown
+			} else if i > slot && timing >= 0 {
+				latest = timing - cooldown
+				break
+			}
+		}
+
+		// Leaving the slot unused is always an option.
+		candidates := []time.Duration{-1}
+		for timing := (earliest + timeStep - 1) / timeStep * timeStep; timing <= latest; timing += timeStep {
+			candidates = append(candidates, timing)
+		}
+		if current[cooldownIdx][slot] >= 0 && current[cooldownIdx][slot]%timeStep != 0 {
+			candidates = append(candidates, current[cooldownIdx][slot])
+		}
+
+		return MapSlice(candidates, func(timing time.Duration) defensivePlan {
+			plan := current.clone()
+			plan[cooldownIdx][slot] = timing
+			return plan
+		})
+	}
+
+	// The rotation as given, for comparison.
+	initialSimResult := RunSim(buildRequest(rotation), nil, signals)
+	if initialSimResult.Error != nil {
+		return &proto.DefensivePlanResult{Error: initialSimResult.Error}
+	}
+	initialScore := defensivePlanScoreFromResult(initialSimResult)
+	reportProgress()
+
+	initialScores, errOutcome := evaluate([]defensivePlan{initialPlan})
+	if errOutcome != nil {
+		return &proto.DefensivePlanResult{Error: errOutcome}
+	}
+
+	current := initialPlan
+	currentScore := initialScores[0]
+	for range maxPasses {
+		changed := false
+		for cooldownIdx := range cooldowns {
+			for slot := range current[cooldownIdx] {
+				plans := slotPlans(current, cooldownIdx, slot)
+				scores, errOutcome := evaluate(plans)
+				if errOutcome != nil {
+					return &proto.DefensivePlanResult{Error: errOutcome}
+				}
+				for i, score := range scores {
+					// Only move on a strict improvement, so that ties keep the current usage.
+					if isBetter(score, currentScore) {
+						current = plans[i]
+						currentScore = score
+						changed = true
+					}
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Keep the rotation as given if no plan beats it.
+	if !isBetter(currentScore, initialScore) {
+		current = make(defensivePlan, len(cooldowns))
+		currentScore = initialScore
+	}
+
+	result := &proto.DefensivePlanResult{
+		Schedule:             current.scheduleActions(cooldowns),
+		PlannedRotation:      buildRotation(current),
+		InitialTmi:           initialScore.tmi,
+		PlannedTmi:           currentScore.tmi,
+		InitialChanceOfDeath: initialScore.chanceOfDeath,
+		PlannedChanceOfDeath: currentScore.chanceOfDeath,
+		SimsRun:              simsRun,
+	}
+	for i, cooldown := range cooldowns {
+		result.Cooldowns = append(result.Cooldowns, &proto.DefensivePlanCooldown{
+			Id: cooldown.actionID.ToProto(),
+			Timings: MapSlice(current.usedTimings(i), func(timing time.Duration) float64 {
+				return timing.Seconds()
+			}),
+		})
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// A tank with a single 60s cooldown halving damage taken for 10s.
+func init() {
+	registerFakeAgent(proto.Player_ProtectionWarrior{}, proto.Spec_SpecProtectionWarrior, func(fa *FakeAgent) {
+		actionID := ActionID{SpellID: 871}
+		aura := fa.RegisterAura(Aura{
+			Label:    "Fake Wall",
+			ActionID: actionID,
+			Duration: time.Second * 10,
+		}).AttachMultiplicativePseudoStatBuff(&fa.PseudoStats.DamageTakenMultiplier, 0.5)
+
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID: actionID,
+			Flags:    SpellFlagAPL,
+			Cast: CastConfig{
+				CD: Cooldown{
+					Timer:    fa.NewTimer(),
+					Duration: time.Minute,
+				},
+			},
+			ApplyEffects: func(sim *Simulation, _ *Unit, _ *Spell) {
+				aura.Activate(sim)
+			},
+		})
+		fa.AddMajorCooldown(MajorCooldown{
+			Spell: fa.Spell,
+			Type:  CooldownTypeSurvival,
+		})
+	})
+}
+
+func defensivePlanTestRequest() *proto.DefensivePlanRequest {
+	return &proto.DefensivePlanRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			SimOptions: &proto.SimOptions{
+				Iterations: 20,
+				RandomSeed: 100,
+			},
+			Raid: &proto.Raid{
+				Parties: []*proto.Party{
+					{
+						Players: []*proto.Player{
+							{
+								Name:      "Tank",
+								Class:     proto.Class_ClassWarrior,
+								Buffs:     &proto.IndividualBuffs{},
+								Spec:      &proto.Player_ProtectionWarrior{},
+								Equipment: &proto.EquipmentSpec{},
+								BonusStats: &proto.UnitStats{
+									Stats: stats.Stats{stats.Health: 500_000}.ToProtoArray(),
+								},
+								HealingModel: &proto.HealingModel{Hps: 5000, CadenceSeconds: 2, BurstWindow: 6},
+							},
+						},
+						Buffs: &proto.PartyBuffs{},
+					},
+				},
+				Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+			},
+			Encounter: &proto.Encounter{
+				Targets: []*proto.Target{
+					{
+						Name:          "target",
+						Level:         93,
+						MobType:       proto.MobType_MobTypeDemon,
+						MinBaseDamage: 20000,
+						DamageSpread:  0.5,
+						SwingSpeed:    2,
+					},
+				},
+				Duration: 120,
+			},
+		},
+		TimeStepSeconds: 10,
+	}
+}
+
+func TestDefensivePlan(t *testing.T) {
+	result := RunDefensivePlan(defensivePlanTestRequest())
+	if result.Error != nil {
+		t.Fatalf("Defensive plan failed: %s", result.Error.Message)
+	}
+
+	if result.PlannedTmi >= result.InitialTmi {
+		t.Fatalf("Expected planned TMI below %0.3f, got %0.3f", result.InitialTmi, result.PlannedTmi)
+	}
+
+	if len(result.Cooldowns) != 1 {
+		t.Fatalf("Expected 1 planned cooldown, got %d", len(result.Cooldowns))
+	}
+	timings := result.Cooldowns[0].Timings
+	if len(timings) == 0 {
+		t.Fatalf("Expected the cooldown to be used")
+	}
+	for i := 1; i < len(timings); i++ {
+		if timings[i]-timings[i-1] < 60 {
+			t.Fatalf("Expected usages to be at least a cooldown apart, got %v", timings)
+		}
+	}
+
+	if len(result.Schedule) != 1 || result.Schedule[0].GetSchedule() == nil {
+		t.Fatalf("Expected 1 schedule action, got %v", result.Schedule)
+	}
+	if result.PlannedRotation.PriorityList[0].Action.GetSchedule() == nil {
+		t.Fatalf("Expected the schedule at the top of the planned rotation")
+	}
+}
+
+func TestDefensivePlanRequiresBurstWindow(t *testing.T) {
+	request := defensivePlanTestRequest()
+	request.BaseSettings.Raid.Parties[0].Players[0].HealingModel.BurstWindow = 0
+	if result := RunDefensivePlan(request); result.Error == nil {
+		t.Fatalf("Expected an error without a burst window")
+	}
+}
+
+func TestDefensivePlanHidesRotationCasts(t *testing.T) {
+	request := defensivePlanTestRequest()
+	request.BaseSettings.Raid.Parties[0].Players[0].Rotation = &proto.APLRotation{
+		PriorityList: []*proto.APLListItem{
+			{Action: &proto.APLAction{Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+				SpellId: ActionID{SpellID: 871}.ToProto(),
+			}}}},
+		},
+	}
+
+	result := RunDefensivePlan(request)
+	if result.Error != nil {
+		t.Fatalf("Defensive plan failed: %s", result.Error.Message)
+	}
+	if !result.PlannedRotation.PriorityList[1].Hide {
+		t.Fatalf("Expected the rotation's own cast of the planned cooldown to be hidden")
+	}
+}
+
+func TestDefensivePlanInvalidSettings(t *testing.T) {
+	request := defensivePlanTestRequest()
+	request.BaseSettings.Raid.Parties[0].Players[0].Equipment = &proto.EquipmentSpec{Items: []*proto.ItemSpec{{Id: 1}}}
+	if result := RunDefensivePlan(request); result.Error == nil {
+		t.Fatalf("Expected an error with invalid settings")
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -46,6 +48,31 @@ func (fa *FakeAgent) Reset(_ *Simulation)            {}
 func (fa *FakeAgent) OnGCDReady(_ *Simulation)       {}
 func (fa *FakeAgent) OnEncounterStart(_ *Simulation) {}
 
+// Registers a FakeAgent for the given spec, set up by init once its character
+// is initialized. For tests which need several fake classes in one raid.
+func registerFakeAgent(emptyOptions interface{}, spec proto.Spec, init func(fa *FakeAgent)) {
+	specType := reflect.PointerTo(reflect.TypeOf(emptyOptions))
+	RegisterAgentFactory(
+		emptyOptions,
+		spec,
+		func(char *Character, _ *proto.Player) Agent {
+			fa := &FakeAgent{
+				Character: *char,
+			}
+			fa.Init = func() {
+				init(fa)
+			}
+			return fa
+		},
+		func(player *proto.Player, specValue interface{}) {
+			if reflect.TypeOf(specValue) != specType {
+				panic(fmt.Sprintf("Invalid spec value for %s!", spec))
+			}
+			reflect.ValueOf(player).Elem().FieldByName("Spec").Set(reflect.ValueOf(specValue))
+		},
+	)
+}
+
 func NewFakeElementalShaman(char *Character, _ *proto.Player) Agent {
 	fa := &FakeAgent{
 		Character: *char,
@@ -94,6 +121,22 @@ func NewFakeElementalShaman(char *Character, _ *proto.Player) Agent {
 	return fa
 }
 
+// An APL which keeps the fake elemental shaman's dot up.
+func fakeDotRotation() *proto.APLRotation {
+	fakeDot := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 42}}
+	return &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		PriorityList: []*proto.APLListItem{{
+			Action: &proto.APLAction{
+				Condition: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{
+					Val: &proto.APLValue{Value: &proto.APLValue_DotIsActive{DotIsActive: &proto.APLValueDotIsActive{SpellId: fakeDot}}},
+				}}},
+				Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: fakeDot}},
+			},
+		}},
+	}
+}
+
 func SetupFakeSim() *Simulation {
 	sim := NewSim(&proto.RaidSimRequest{
 		SimOptions: &proto.SimOptions{
@@ -171,3 +214,74 @@ func TestDotSnapshotSpellMultiplier(t *testing.T) {
 	fa.Dot.Apply(sim)
 	expectDotTickDamage(t, sim, fa.Dot, 300) // (100) * 1.5 * 2
 }
+
+func TestDotCombatLogEvents(t *testing.T) {
+	sim := SetupFakeSim()
+	fa := sim.Raid.Parties[0].Players[0].(*FakeAgent)
+
+	var events []*proto.CombatLogEvent
+	sim.CombatLog = func(event *proto.CombatLogEvent) {
+		events = append(events, event)
+	}
+
+	fa.Dot.Apply(sim)
+	fa.Dot.TickOnce(sim)
+
+	var tick *proto.CombatLogEvent
+	for _, event := range events {
+		if event.Type == proto.CombatLogEventType_CombatLogEventDamageTick {
+			tick = event
+		}
+	}
+	if tick == nil {
+		t.Fatalf("Expected a damage tick event, got %v", events)
+	}
+	if tick.UnitIndex != fa.UnitIndex || tick.TargetUnitIndex != fa.CurrentTarget.UnitIndex {
+		t.Fatalf("Incorrect units for tick event: %d -> %d", tick.UnitIndex, tick.TargetUnitIndex)
+	}
+	if tick.ActionId.GetSpellId() != 42 || tick.Outcome != proto.CombatLogOutcome_CombatLogOutcomeHit || !WithinToleranceFloat64(150, tick.Amount, 0.01) {
+		t.Fatalf("Incorrect tick event: %v", tick)
+	}
+}
+
+func TestCombatLogIterationsAcrossSplits(t *testing.T) {
+	result := runSimConcurrent(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			Iterations:    6,
+			RandomSeed:    100,
+			Debug:         true,
+			IsTest:        true,
+			CombatLogMode: proto.CombatLogMode_CombatLogEvents,
+		},
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Caster",
+			Class:     proto.Class_ClassShaman,
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Rotation:  fakeDotRotation(),
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+			Duration: 20,
+		},
+	}, nil, simsignals.CreateSignals())
+
+	// Splits are combined in order, so iterations should only ever increase.
+	seen := map[int32]bool{}
+	lastIteration := int32(0)
+	for _, event := range result.CombatLog {
+		if event.Type == proto.CombatLogEventType_CombatLogEventPendingAction {
+			t.Fatalf("Expected no pending action events unless requested")
+		}
+		if event.Iteration < lastIteration {
+			t.Fatalf("Iteration went from %d back to %d", lastIteration, event.Iteration)
+		}
+		lastIteration = event.Iteration
+		seen[event.Iteration] = true
+	}
+	for iteration := range int32(6) {
+		if !seen[iteration] {
+			t.Errorf("Expected events for iteration %d, got %v", iteration, seen)
+		}
+	}
+}
@@ -120,6 +120,9 @@ func (eb *energyBar) AddEnergy(sim *Simulation, amount float64, metrics *Resourc
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
+	if sim.CombatLog != nil {
+		eb.unit.logResourceEvent(sim, metrics, amount, newEnergy, false)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -135,6 +138,9 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, metrics *Resou
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
+	if sim.CombatLog != nil {
+		eb.unit.logResourceEvent(sim, metrics, amount, newEnergy, true)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -218,6 +224,9 @@ func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, metrics
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %d %s from %s (%d --> %d) of %0.0f total.", pointsToAdd, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
 	}
+	if sim.CombatLog != nil {
+		eb.unit.logResourceEvent(sim, metrics, float64(pointsToAdd), float64(newComboPoints), false)
+	}
 
 	eb.comboPoints = newComboPoints
 }
@@ -236,6 +245,9 @@ func (eb *energyBar) spendComboPointsInternal(sim *Simulation, pointsToSpend int
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %d %s from %s (%d --> %d) of %0.0f total.", pointsToSpend, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
 	}
+	if sim.CombatLog != nil {
+		eb.unit.logResourceEvent(sim, metrics, float64(pointsToSpend), float64(newComboPoints), true)
+	}
 	metrics.AddEvent(float64(-pointsToSpend), float64(-pointsToSpend))
 	eb.comboPoints = newComboPoints
 }
@@ -58,6 +58,7 @@ func NewEnvironment(raidProto *proto.Raid, encounterProto *proto.Encounter, runF
 		State: Created,
 	}
 
+	validateRaidProto(raidProto)
 	env.construct(raidProto, encounterProto)
 	raidStats := env.initialize(raidProto, encounterProto)
 	env.finalize(raidProto, encounterProto, raidStats, runFakePrepull)
@@ -92,8 +93,9 @@ func (env *Environment) construct(raidProto *proto.Raid, encounterProto *proto.E
 		unit.CurrentTarget = env.Encounter.ActiveTargetUnits[0]
 	}
 
-	// Apply extra debuffs from raid.
-	if raidProto.Debuffs != nil && len(env.Encounter.AllTargetUnits) > 0 {
+	// Apply extra debuffs from raid. When buffs come from the raid composition,
+	// debuffs are instead applied by the players' own spells.
+	if raidProto.Debuffs != nil && !raidProto.BuffsFromComposition && len(env.Encounter.AllTargetUnits) > 0 {
 		for targetIdx, targetUnit := range env.Encounter.AllTargetUnits {
 			applyDebuffEffects(targetUnit, targetIdx, raidProto.Debuffs, raidProto)
 		}
@@ -112,6 +114,10 @@ func (env *Environment) construct(raidProto *proto.Raid, encounterProto *proto.E
 		}
 	}
 
+	if raidProto.DamageProfile.GetTankMeleeOnDummies() {
+		env.setupDummyTankTargets(tankTargetSet)
+	}
+
 	// Check for Challenge Mode
 	for _, party := range raidProto.Parties {
 		for _, playerOrPet := range party.Players {
@@ -142,6 +148,7 @@ func (env *Environment) initialize(raidProto *proto.Raid, encounterProto *proto.
 	}
 
 	raidStats := env.Raid.applyCharacterEffects(raidProto)
+	env.registerRaidDamageProfile(raidProto.DamageProfile)
 
 	for _, party := range env.Raid.Parties {
 		for _, playerOrPet := range party.PlayersAndPets {
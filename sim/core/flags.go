@@ -148,6 +148,34 @@ func (ho HitOutcome) String() string {
 	}
 }
 
+func (ho HitOutcome) ToProto() proto.CombatLogOutcome {
+	if ho.Matches(OutcomeMiss) {
+		return proto.CombatLogOutcome_CombatLogOutcomeMiss
+	} else if ho.Matches(OutcomeDodge) {
+		return proto.CombatLogOutcome_CombatLogOutcomeDodge
+	} else if ho.Matches(OutcomeParry) {
+		return proto.CombatLogOutcome_CombatLogOutcomeParry
+	} else if ho.Matches(OutcomeBlock) {
+		if ho.Matches(OutcomeCrit) {
+			return proto.CombatLogOutcome_CombatLogOutcomeCriticalBlock
+		} else if ho.Matches(OutcomeGlance) {
+			return proto.CombatLogOutcome_CombatLogOutcomeGlanceBlock
+		} else {
+			return proto.CombatLogOutcome_CombatLogOutcomeBlock
+		}
+	} else if ho.Matches(OutcomeGlance) {
+		return proto.CombatLogOutcome_CombatLogOutcomeGlance
+	} else if ho.Matches(OutcomeCrit) {
+		return proto.CombatLogOutcome_CombatLogOutcomeCrit
+	} else if ho.Matches(OutcomeHit) {
+		return proto.CombatLogOutcome_CombatLogOutcomeHit
+	} else if ho.Matches(OutcomeCrush) {
+		return proto.CombatLogOutcome_CombatLogOutcomeCrush
+	} else {
+		return proto.CombatLogOutcome_CombatLogOutcomeEmpty
+	}
+}
+
 // Other flags
 type SpellFlag uint64
 
@@ -190,6 +218,8 @@ const (
 	SpellFlagAoE                                           // Indicates that this spell is an AoE spell. Spells flagged with this will use the AoE Cap multiplier when calculating damage.
 	SpellFlagRanged                                        // Indicates that this spell is a ranged spell. Spells flagged with this will have increased damage when Hunters Mark is active.
 	SpellFlagReadinessTrinket                              // Indicates that this spell part of Readiness. Used by Siege of Orgrimmar CDR trinkets.
+	SpellFlagInterrupt                                     // Indicates that this spell interrupts the target's cast, e.g. Pummel or Kick. Used by the interrupt APL action.
+	SpellFlagInterruptible                                 // Indicates that casts of this spell can be interrupted. Used for target spells.
 
 	// Used to let agents categorize their spells.
 	SpellFlagAgentReserved1
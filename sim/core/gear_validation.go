@@ -0,0 +1,582 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// Which armor and weapons a class can equip. See player_classes/*.ts.
+type classGearRestrictions struct {
+	// Heaviest armor type the class can wear, which is also the type needed for armor specialization.
+	armorType proto.ArmorType
+	// Usable weapon types, mapped to whether two-handed weapons of that type are usable.
+	weaponTypes       map[proto.WeaponType]bool
+	rangedWeaponTypes []proto.RangedWeaponType
+}
+
+var classGearRestrictionsByClass = map[proto.Class]classGearRestrictions{
+	proto.Class_ClassDeathKnight: {
+		armorType: proto.ArmorType_ArmorTypePlate,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     true,
+			proto.WeaponType_WeaponTypeMace:    true,
+			proto.WeaponType_WeaponTypePolearm: true,
+			proto.WeaponType_WeaponTypeSword:   true,
+		},
+	},
+	proto.Class_ClassDruid: {
+		armorType: proto.ArmorType_ArmorTypeLeather,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeFist:    false,
+			proto.WeaponType_WeaponTypeMace:    true,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+			proto.WeaponType_WeaponTypePolearm: true,
+		},
+	},
+	proto.Class_ClassHunter: {
+		armorType: proto.ArmorType_ArmorTypeMail,
+		rangedWeaponTypes: []proto.RangedWeaponType{
+			proto.RangedWeaponType_RangedWeaponTypeBow,
+			proto.RangedWeaponType_RangedWeaponTypeCrossbow,
+			proto.RangedWeaponType_RangedWeaponTypeGun,
+		},
+	},
+	proto.Class_ClassMage: {
+		armorType: proto.ArmorType_ArmorTypeCloth,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+			proto.WeaponType_WeaponTypeSword:   false,
+		},
+		rangedWeaponTypes: []proto.RangedWeaponType{proto.RangedWeaponType_RangedWeaponTypeWand},
+	},
+	proto.Class_ClassMonk: {
+		armorType: proto.ArmorType_ArmorTypeLeather,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     false,
+			proto.WeaponType_WeaponTypeFist:    false,
+			proto.WeaponType_WeaponTypeMace:    false,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypePolearm: true,
+			proto.WeaponType_WeaponTypeStaff:   true,
+			proto.WeaponType_WeaponTypeSword:   false,
+		},
+	},
+	proto.Class_ClassPaladin: {
+		armorType: proto.ArmorType_ArmorTypePlate,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     true,
+			proto.WeaponType_WeaponTypeMace:    true,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypePolearm: true,
+			proto.WeaponType_WeaponTypeShield:  false,
+			proto.WeaponType_WeaponTypeSword:   true,
+		},
+	},
+	proto.Class_ClassPriest: {
+		armorType: proto.ArmorType_ArmorTypeCloth,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeMace:    false,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+		},
+		rangedWeaponTypes: []proto.RangedWeaponType{proto.RangedWeaponType_RangedWeaponTypeWand},
+	},
+	proto.Class_ClassRogue: {
+		armorType: proto.ArmorType_ArmorTypeLeather,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     false,
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeFist:    false,
+			proto.WeaponType_WeaponTypeMace:    false,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeSword:   false,
+		},
+	},
+	proto.Class_ClassShaman: {
+		armorType: proto.ArmorType_ArmorTypeMail,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     true,
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeFist:    false,
+			proto.WeaponType_WeaponTypeMace:    true,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeShield:  false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+		},
+	},
+	proto.Class_ClassWarlock: {
+		armorType: proto.ArmorType_ArmorTypeCloth,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+			proto.WeaponType_WeaponTypeSword:   false,
+		},
+		rangedWeaponTypes: []proto.RangedWeaponType{proto.RangedWeaponType_RangedWeaponTypeWand},
+	},
+	proto.Class_ClassWarrior: {
+		armorType: proto.ArmorType_ArmorTypePlate,
+		weaponTypes: map[proto.WeaponType]bool{
+			proto.WeaponType_WeaponTypeAxe:     true,
+			proto.WeaponType_WeaponTypeDagger:  false,
+			proto.WeaponType_WeaponTypeFist:    false,
+			proto.WeaponType_WeaponTypeMace:    true,
+			proto.WeaponType_WeaponTypeOffHand: false,
+			proto.WeaponType_WeaponTypePolearm: true,
+			proto.WeaponType_WeaponTypeShield:  false,
+			proto.WeaponType_WeaponTypeStaff:   true,
+			proto.WeaponType_WeaponTypeSword:   true,
+		},
+		rangedWeaponTypes: []proto.RangedWeaponType{
+			proto.RangedWeaponType_RangedWeaponTypeBow,
+			proto.RangedWeaponType_RangedWeaponTypeCrossbow,
+			proto.RangedWeaponType_RangedWeaponTypeGun,
+			proto.RangedWeaponType_RangedWeaponTypeThrown,
+		},
+	},
+}
+
+// Specs that can use a one-handed weapon in the off hand. See canDualWield in player_specs/*.ts.
+var dualWieldSpecs = map[proto.Spec]bool{
+	proto.Spec_SpecBloodDeathKnight:   true,
+	proto.Spec_SpecFrostDeathKnight:   true,
+	proto.Spec_SpecUnholyDeathKnight:  true,
+	proto.Spec_SpecBeastMasteryHunter: true,
+	proto.Spec_SpecMarksmanshipHunter: true,
+	proto.Spec_SpecSurvivalHunter:     true,
+	proto.Spec_SpecBrewmasterMonk:     true,
+	proto.Spec_SpecWindwalkerMonk:     true,
+	proto.Spec_SpecAssassinationRogue: true,
+	proto.Spec_SpecCombatRogue:        true,
+	proto.Spec_SpecSubtletyRogue:      true,
+	proto.Spec_SpecEnhancementShaman:  true,
+	proto.Spec_SpecArmsWarrior:        true,
+	proto.Spec_SpecFuryWarrior:        true,
+	proto.Spec_SpecProtectionWarrior:  true,
+}
+
+// Titan's Grip only allows two-handed axes, maces and swords to be wielded in one hand.
+var titansGripWeaponTypes = []proto.WeaponType{
+	proto.WeaponType_WeaponTypeAxe,
+	proto.WeaponType_WeaponTypeMace,
+	proto.WeaponType_WeaponTypeSword,
+}
+
+type gearValidator struct {
+	result      *proto.ValidateGearResult
+	class       proto.Class
+	spec        proto.Spec
+	professions [2]proto.Profession
+}
+
+func (gv *gearValidator) addError(issueType proto.GearIssueType, slot proto.ItemSlot, itemID int32, relatedID int32, format string, args ...any) {
+	gv.result.Errors = append(gv.result.Errors, &proto.GearIssue{
+		Type:      issueType,
+		Slot:      slot,
+		ItemId:    itemID,
+		RelatedId: relatedID,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (gv *gearValidator) addWarning(issueType proto.GearIssueType, slot proto.ItemSlot, itemID int32, relatedID int32, format string, args ...any) {
+	gv.result.Warnings = append(gv.result.Warnings, &proto.GearIssue{
+		Type:      issueType,
+		Slot:      slot,
+		ItemId:    itemID,
+		RelatedId: relatedID,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (gv *gearValidator) hasProfession(profession proto.Profession) bool {
+	return profession == proto.Profession_ProfessionUnknown || slices.Contains(gv.professions[:], profession)
+}
+
+// Checks whether the player's gear could actually be equipped in game. Unlike
+// NewEquipmentSet this never panics, so it can be used on imported characters
+// before simming them.
+func ValidateEquipment(player *proto.Player) *proto.ValidateGearResult {
+	if player == nil {
+		return &proto.ValidateGearResult{}
+	}
+
+	gv := &gearValidator{
+		result:      &proto.ValidateGearResult{},
+		class:       player.Class,
+		spec:        PlayerProtoToSpec(player),
+		professions: [2]proto.Profession{player.Profession1, player.Profession2},
+	}
+
+	itemCounts := map[int32]int{}
+	limitCategoryCounts := map[int32]int{}
+	gemCounts := map[int32]int{}
+	var metaGem *Gem
+	var metaGemSlot proto.ItemSlot
+	var mainHand, offHand *Item
+	var numRed, numYellow, numBlue int
+	numItems, numChallengeMode := 0, 0
+
+	for i, itemSpec := range player.GetEquipment().GetItems() {
+		slot := proto.ItemSlot(i)
+		if itemSpec == nil || itemSpec.Id == 0 {
+			continue
+		}
+		if slot >= NumItemSlots {
+			gv.addError(proto.GearIssueType_GearIssueUnknown, slot, itemSpec.Id, 0, "Item %d is in an invalid slot index %d.", itemSpec.Id, i)
+			continue
+		}
+
+		item, ok := ItemsByID[itemSpec.Id]
+		if !ok {
+			gv.addError(proto.GearIssueType_GearIssueMissingFromDatabase, slot, itemSpec.Id, 0, "No item with id %d.", itemSpec.Id)
+			continue
+		}
+
+		numItems++
+		if itemSpec.ChallengeMode {
+			numChallengeMode++
+		}
+
+		itemCounts[item.ID]++
+		if item.Unique && itemCounts[item.ID] > 1 {
+			gv.addError(proto.GearIssueType_GearIssueUniqueEquipped, slot, item.ID, 0, "%s is unique-equipped.", item.Name)
+		}
+		if item.LimitCategory != 0 {
+			limitCategoryCounts[item.LimitCategory]++
+			if limitCategoryCounts[item.LimitCategory] == 2 {
+				gv.addWarning(proto.GearIssueType_GearIssueUniqueEquipped, slot, item.ID, 0, "%s shares a unique-equipped category with another equipped item.", item.Name)
+			}
+		}
+
+		gv.validateItemType(slot, &item)
+		switch slot {
+		case proto.ItemSlot_ItemSlotMainHand:
+			mainHand = &item
+		case proto.ItemSlot_ItemSlotOffHand:
+			offHand = &item
+		}
+		gv.validateItemLevel(slot, &item, itemSpec)
+
+		if !gv.hasProfession(item.RequiredProfession) {
+			gv.addError(proto.GearIssueType_GearIssueProfession, slot, item.ID, 0, "%s requires %s.", item.Name, item.RequiredProfession)
+		}
+
+		for _, enchantID := range []int32{itemSpec.Enchant, itemSpec.Tinker} {
+			if enchantID != 0 {
+				gv.validateEnchant(slot, &item, enchantID)
+			}
+		}
+
+		for socketIdx, gemID := range itemSpec.Gems {
+			if gemID == 0 {
+				continue
+			}
+
+			gem, ok := GemsByID[gemID]
+			if !ok {
+				gv.addError(proto.GearIssueType_GearIssueMissingFromDatabase, slot, item.ID, gemID, "No gem with id %d in socket %d of %s.", gemID, socketIdx, item.Name)
+				continue
+			}
+
+			// Blacksmiths can add an extra socket to bracers and gloves. The belt buckle is available to everyone.
+			if socketIdx >= len(item.GemSockets) && (slot == proto.ItemSlot_ItemSlotWrist || slot == proto.ItemSlot_ItemSlotHands) && !gv.hasProfession(proto.Profession_Blacksmithing) {
+				gv.addError(proto.GearIssueType_GearIssueProfession, slot, item.ID, gemID, "The extra socket on %s requires Blacksmithing.", item.Name)
+			}
+			if !gv.hasProfession(gem.RequiredProfession) {
+				gv.addError(proto.GearIssueType_GearIssueProfession, slot, item.ID, gemID, "%s requires %s.", gem.Name, gem.RequiredProfession)
+			}
+
+			gemCounts[gem.ID]++
+			if gem.Unique && gemCounts[gem.ID] > 1 {
+				gv.addError(proto.GearIssueType_GearIssueUniqueEquipped, slot, item.ID, gemID, "%s is unique-equipped.", gem.Name)
+			}
+			if gem.DisabledInChallengeMode && itemSpec.ChallengeMode {
+				gv.addWarning(proto.GearIssueType_GearIssueChallengeMode, slot, item.ID, gemID, "%s is disabled in challenge mode.", gem.Name)
+			}
+
+			if gem.Color == proto.GemColor_GemColorMeta {
+				metaGem = &gem
+				metaGemSlot = slot
+				continue
+			}
+			if ColorIntersects(proto.GemColor_GemColorRed, gem.Color) {
+				numRed++
+			}
+			if ColorIntersects(proto.GemColor_GemColorYellow, gem.Color) {
+				numYellow++
+			}
+			if ColorIntersects(proto.GemColor_GemColorBlue, gem.Color) {
+				numBlue++
+			}
+		}
+
+		if itemSpec.Reforging != 0 {
+			gv.validateReforge(slot, itemSpec)
+		}
+	}
+
+	gv.validateWeaponCombo(mainHand, offHand)
+
+	if metaGem != nil {
+		if condition, ok := metaGemConditions[metaGem.ID]; ok && !condition.isMet(numRed, numYellow, numBlue) {
+			gv.addWarning(proto.GearIssueType_GearIssueInactiveMetaGem, metaGemSlot, 0, metaGem.ID, "%s is inactive. %s", metaGem.Name, condition.description)
+		}
+	}
+
+	if numChallengeMode > 0 && numChallengeMode < numItems {
+		gv.addWarning(proto.GearIssueType_GearIssueChallengeMode, 0, 0, 0, "Only %d of %d items are scaled for challenge mode.", numChallengeMode, numItems)
+	}
+
+	return gv.result
+}
+
+// Checks the slot, armor type and weapon type of the item against the player's class and spec.
+func (gv *gearValidator) validateItemType(slot proto.ItemSlot, item *Item) {
+	if !slices.Contains(eligibleSlotsForItem(item, gv.spec == proto.Spec_SpecFuryWarrior), slot) {
+		gv.addError(proto.GearIssueType_GearIssueItemSlot, slot, item.ID, 0, "%s can't be equipped in %s.", item.Name, slot)
+		return
+	}
+
+	if len(item.ClassAllowlist) > 0 && !slices.Contains(item.ClassAllowlist, gv.class) {
+		gv.addError(proto.GearIssueType_GearIssueClassRestriction, slot, item.ID, 0, "%s can't be used by %s.", item.Name, gv.class)
+	}
+
+	restrictions, ok := classGearRestrictionsByClass[gv.class]
+	if !ok {
+		return
+	}
+
+	// Cloaks are cloth for everyone.
+	if item.ArmorType != proto.ArmorType_ArmorTypeUnknown && item.Type != proto.ItemType_ItemTypeBack {
+		if item.ArmorType > restrictions.armorType {
+			gv.addError(proto.GearIssueType_GearIssueArmorType, slot, item.ID, 0, "%s can't wear %s.", gv.class, item.ArmorType)
+		} else if item.ArmorType < restrictions.armorType && slices.Contains(ArmorSpecializationSlots(), slot) {
+			gv.addWarning(proto.GearIssueType_GearIssueArmorType, slot, item.ID, 0, "%s is %s, which disables armor specialization.", item.Name, item.ArmorType)
+		}
+	}
+
+	switch item.Type {
+	case proto.ItemType_ItemTypeWeapon:
+		canUseTwoHand, ok := restrictions.weaponTypes[item.WeaponType]
+		if !ok {
+			gv.addError(proto.GearIssueType_GearIssueWeaponType, slot, item.ID, 0, "%s can't use %s.", gv.class, item.WeaponType)
+		} else if item.HandType == proto.HandType_HandTypeTwoHand && !canUseTwoHand {
+			gv.addError(proto.GearIssueType_GearIssueWeaponType, slot, item.ID, 0, "%s can't use two-handed %s.", gv.class, item.WeaponType)
+		} else if slot == proto.ItemSlot_ItemSlotOffHand && !dualWieldSpecs[gv.spec] && item.WeaponType != proto.WeaponType_WeaponTypeShield && item.WeaponType != proto.WeaponType_WeaponTypeOffHand {
+			gv.addError(proto.GearIssueType_GearIssueWeaponType, slot, item.ID, 0, "%s can't dual wield.", gv.spec)
+		}
+	case proto.ItemType_ItemTypeRanged:
+		if !slices.Contains(restrictions.rangedWeaponTypes, item.RangedWeaponType) {
+			gv.addError(proto.GearIssueType_GearIssueWeaponType, slot, item.ID, 0, "%s can't use %s.", gv.class, item.RangedWeaponType)
+		}
+	}
+}
+
+// Same rules as validWeaponCombo in proto_utils/utils.ts, plus the weapon types allowed by Titan's Grip.
+func (gv *gearValidator) validateWeaponCombo(mainHand *Item, offHand *Item) {
+	if mainHand == nil || offHand == nil {
+		return
+	}
+
+	for _, weapon := range []struct {
+		slot proto.ItemSlot
+		item *Item
+	}{{proto.ItemSlot_ItemSlotMainHand, mainHand}, {proto.ItemSlot_ItemSlotOffHand, offHand}} {
+		if weapon.item.Type != proto.ItemType_ItemTypeWeapon || weapon.item.HandType != proto.HandType_HandTypeTwoHand {
+			continue
+		}
+
+		if gv.spec != proto.Spec_SpecFuryWarrior {
+			// A two-handed weapon in the off hand is already reported as being in the wrong slot.
+			if weapon.slot == proto.ItemSlot_ItemSlotMainHand {
+				gv.addError(proto.GearIssueType_GearIssueWeaponType, proto.ItemSlot_ItemSlotOffHand, offHand.ID, 0, "%s can't be used with the two-handed %s.", offHand.Name, mainHand.Name)
+			}
+		} else if !slices.Contains(titansGripWeaponTypes, weapon.item.WeaponType) {
+			gv.addError(proto.GearIssueType_GearIssueWeaponType, weapon.slot, weapon.item.ID, 0, "Titan's Grip doesn't allow dual wielding two-handed %s.", weapon.item.WeaponType)
+		}
+	}
+}
+
+// Checks the upgrade step and challenge mode scaling of the item.
+func (gv *gearValidator) validateItemLevel(slot proto.ItemSlot, item *Item, itemSpec *proto.ItemSpec) {
+	if itemSpec.UpgradeStep != proto.ItemLevelState_Base {
+		if _, ok := item.ScalingOptions[int32(itemSpec.UpgradeStep)]; !ok || itemSpec.UpgradeStep == proto.ItemLevelState_ChallengeMode {
+			gv.addError(proto.GearIssueType_GearIssueUpgradeStep, slot, item.ID, 0, "%s can't be upgraded to %s.", item.Name, itemSpec.UpgradeStep)
+			return
+		}
+	}
+
+	if !itemSpec.ChallengeMode {
+		return
+	}
+
+	// Items above the cap are scaled down in challenge modes, which needs the scaled stats in the database.
+	upgraded, ok := item.ScalingOptions[int32(itemSpec.UpgradeStep)]
+	if !ok || upgraded.Ilvl <= MaxChallengeModeIlvl {
+		return
+	}
+	if _, ok := item.ScalingOptions[int32(proto.ItemLevelState_ChallengeMode)]; !ok {
+		gv.addError(proto.GearIssueType_GearIssueChallengeMode, slot, item.ID, 0, "%s (ilvl %d) has no stats scaled to the challenge mode item level cap of %d.", item.Name, upgraded.Ilvl, MaxChallengeModeIlvl)
+	}
+}
+
+func (gv *gearValidator) validateEnchant(slot proto.ItemSlot, item *Item, enchantID int32) {
+	enchant, ok := EnchantsByEffectID[enchantID]
+	if !ok {
+		// The sim ignores unknown enchants rather than failing.
+		gv.addWarning(proto.GearIssueType_GearIssueMissingFromDatabase, slot, item.ID, enchantID, "No enchant with id %d on %s, it will be ignored.", enchantID, item.Name)
+		return
+	}
+
+	if !gv.hasProfession(enchant.RequiredProfession) {
+		gv.addError(proto.GearIssueType_GearIssueProfession, slot, item.ID, enchantID, "%s requires %s.", enchant.Name, enchant.RequiredProfession)
+	}
+	if len(enchant.ClassAllowlist) > 0 && !slices.Contains(enchant.ClassAllowlist, gv.class) {
+		gv.addError(proto.GearIssueType_GearIssueClassRestriction, slot, item.ID, enchantID, "%s can't be used by %s.", enchant.Name, gv.class)
+	}
+}
+
+// Same check as NewItem, which panics on an invalid reforge.
+func (gv *gearValidator) validateReforge(slot proto.ItemSlot, itemSpec *proto.ItemSpec) {
+	reforge, ok := ReforgeStatsByID[itemSpec.Reforging]
+	if !ok {
+		gv.addError(proto.GearIssueType_GearIssueMissingFromDatabase, slot, itemSpec.Id, itemSpec.Reforging, "No reforge with id %d.", itemSpec.Reforging)
+		return
+	}
+
+	item := ItemsByID[itemSpec.Id]
+	item.UpgradeStep = itemSpec.UpgradeStep
+	item.ChallengeMode = itemSpec.ChallengeMode
+	scalingOptions := item.GetEffectiveScalingOptions()
+	if scalingOptions == nil {
+		// Already reported as an invalid upgrade step.
+		return
+	}
+	item.Stats = stats.FromProtoMap(scalingOptions.Stats)
+	item.RandPropPoints = scalingOptions.RandPropPoints
+	if itemSpec.RandomSuffix != 0 {
+		randomSuffix, ok := RandomSuffixesByID[itemSpec.RandomSuffix]
+		if !ok {
+			gv.addError(proto.GearIssueType_GearIssueMissingFromDatabase, slot, item.ID, itemSpec.RandomSuffix, "No random suffix with id %d.", itemSpec.RandomSuffix)
+			return
+		}
+		item.RandomSuffix = randomSuffix
+	}
+
+	if !validateReforging(&item, reforge) {
+		gv.addError(proto.GearIssueType_GearIssueReforge, slot, item.ID, reforge.ID, "%s can't be reforged from %s to %s.", item.Name, reforge.FromStat, reforge.ToStat)
+	}
+}
+
+// Activation requirements of meta gems. This duplicates MetaGemCondition in
+// proto_utils/gems.ts, so changes to either list need to be made in both.
+type metaGemCondition struct {
+	description string
+
+	minRed    int
+	minYellow int
+	minBlue   int
+
+	compareColorGreater proto.GemColor
+	compareColorLesser  proto.GemColor
+}
+
+func numGemsOfColor(color proto.GemColor, numRed int, numYellow int, numBlue int) int {
+	switch color {
+	case proto.GemColor_GemColorRed:
+		return numRed
+	case proto.GemColor_GemColorYellow:
+		return numYellow
+	default:
+		return numBlue
+	}
+}
+
+func (condition metaGemCondition) isMet(numRed int, numYellow int, numBlue int) bool {
+	if numRed < condition.minRed || numYellow < condition.minYellow || numBlue < condition.minBlue {
+		return false
+	}
+	if condition.compareColorGreater == proto.GemColor_GemColorUnknown {
+		return true
+	}
+	return numGemsOfColor(condition.compareColorGreater, numRed, numYellow, numBlue) >
+		numGemsOfColor(condition.compareColorLesser, numRed, numYellow, numBlue)
+}
+
+// MoP meta gems have no activation requirements, so only older meta gems are listed here.
+var metaGemConditions = map[int32]metaGemCondition{
+	52289: {description: "Requires at least 2 Yellow Gems.", minYellow: 2},
+	52291: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	52292: {description: "Requires at least 1 Blue Gem and 1 Yellow Gem.", minYellow: 1, minBlue: 1},
+	52293: {description: "Requires at least 3 Blue Gems.", minBlue: 3},
+	52294: {description: "Requires at least 2 Yellow Gems.", minYellow: 2},
+	52295: {description: "Requires at least 1 Red Gem and 1 Yellow Gem.", minRed: 1, minYellow: 1},
+	52296: {description: "Requires at least 2 Yellow Gems.", minYellow: 2},
+	52297: {description: "Requires at least 1 Blue Gem and 1 Yellow Gem.", minYellow: 1, minBlue: 1},
+	52298: {description: "Requires at least 2 Red Gems.", minRed: 2},
+	52299: {description: "Requires at least 2 Blue Gems.", minBlue: 2},
+	52300: {description: "Requires at least 1 Blue Gem and 1 Yellow Gem.", minYellow: 1, minBlue: 1},
+	52301: {description: "Requires at least 1 Blue Gem and 1 Yellow Gem.", minYellow: 1, minBlue: 1},
+	52302: {description: "Requires at least 1 Blue Gem and 1 Yellow Gem.", minYellow: 1, minBlue: 1},
+	68778: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	68779: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	68780: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	// WOTLK GEMS
+	41285: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	41307: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	41333: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	41335: {description: "Requires at least 2 Red Gems and at least 1 Yellow Gem.", minRed: 2, minYellow: 1},
+	41377: {description: "Requires at least 2 Blue Gems and at least 1 Red Gem.", minRed: 1, minBlue: 2},
+	41339: {description: "Requires at least 2 Yellow Gems and at least 1 Red Gem.", minRed: 1, minYellow: 2},
+	41375: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	41376: {description: "Requires at least 2 Red Gems.", minRed: 2},
+	41378: {description: "Requires at least 2 Yellow Gems and at least 1 Blue Gem.", minYellow: 2, minBlue: 1},
+	41379: {description: "Requires at least 2 Red Gems and at least 1 Blue Gem.", minRed: 2, minBlue: 1},
+	41380: {description: "Requires at least 2 Blue Gems and at least 1 Red Gem.", minRed: 1, minBlue: 2},
+	41381: {description: "Requires at least 2 Yellow Gems and at least 1 Blue Gem.", minYellow: 2, minBlue: 1},
+	41382: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	41385: {description: "Requires at least 2 Blue Gems and at least 1 Red Gem.", minRed: 1, minBlue: 2},
+	41389: {description: "Requires at least 2 Red Gems and at least 1 Yellow Gem.", minRed: 2, minYellow: 1},
+	41395: {description: "Requires at least 2 Red Gems and at least 1 Blue Gem.", minRed: 2, minBlue: 1},
+	41396: {description: "Requires at least 2 Red Gems and at least 1 Blue Gem.", minRed: 2, minBlue: 1},
+	41397: {description: "Requires at least 3 Blue Gems.", minBlue: 3},
+	41398: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	41400: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	41401: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	44076: {description: "Requires at least 2 Yellow Gems and at least 1 Red Gem.", minRed: 1, minYellow: 2},
+	44078: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	44081: {description: "Requires at least 2 Red Gems and at least 1 Blue Gem.", minRed: 2, minBlue: 1},
+	44082: {description: "Requires at least 2 Blue Gems and at least 1 Red Gem.", minRed: 1, minBlue: 2},
+	44084: {description: "Requires at least 2 Yellow Gems and at least 1 Blue Gem.", minYellow: 2, minBlue: 1},
+	44087: {description: "Requires at least 3 Blue Gems.", minBlue: 3},
+	44088: {description: "Requires at least 2 Blue Gems and at least 1 Yellow Gem.", minYellow: 1, minBlue: 2},
+	44089: {description: "Requires at least 1 Red Gem, at least 1 Yellow Gem, and at least 1 Blue Gem.", minRed: 1, minYellow: 1, minBlue: 1},
+	// TBC GEMS
+	25899: {description: "Requires at least 2 Red Gems, at least 2 Yellow Gems, and at least 2 Blue Gems.", minRed: 2, minYellow: 2, minBlue: 2},
+	34220: {description: "Requires at least 2 Blue Gems.", minBlue: 2},
+	25890: {description: "Requires at least 2 Red Gems, at least 2 Yellow Gems, and at least 2 Blue Gems.", minRed: 2, minYellow: 2, minBlue: 2},
+	35503: {description: "Requires at least 3 Red Gems.", minRed: 3},
+	35501: {description: "Requires at least 2 Blue Gems and at least 1 Yellow Gem.", minYellow: 1, minBlue: 2},
+	32641: {description: "Requires at least 3 Yellow Gems.", minYellow: 3},
+	25901: {description: "Requires at least 2 Red Gems, at least 2 Yellow Gems, and at least 2 Blue Gems.", minRed: 2, minYellow: 2, minBlue: 2},
+	25896: {description: "Requires at least 3 Blue Gems.", minBlue: 3},
+	32409: {description: "Requires at least 2 Red Gems, at least 2 Yellow Gems, and at least 2 Blue Gems.", minRed: 2, minYellow: 2, minBlue: 2},
+	25894: {description: "Requires at least 2 Yellow Gems and at least 1 Red Gem.", minRed: 1, minYellow: 2},
+	28557: {description: "Requires at least 2 Yellow Gems and at least 1 Red Gem.", minRed: 1, minYellow: 2},
+	28556: {description: "Requires at least 2 Yellow Gems and at least 1 Red Gem.", minRed: 1, minYellow: 2},
+	25898: {description: "Requires at least 5 Blue Gems.", minBlue: 5},
+	32410: {description: "Requires at least 2 Red Gems, at least 2 Yellow Gems, and at least 2 Blue Gems.", minRed: 2, minYellow: 2, minBlue: 2},
+	25897: {description: "Requires more Red Gems than Blue Gems.", compareColorGreater: proto.GemColor_GemColorRed, compareColorLesser: proto.GemColor_GemColorBlue},
+	25895: {description: "Requires more Red Gems than Yellow Gems.", compareColorGreater: proto.GemColor_GemColorRed, compareColorLesser: proto.GemColor_GemColorYellow},
+	25893: {description: "Requires more Blue Gems than Yellow Gems.", compareColorGreater: proto.GemColor_GemColorBlue, compareColorLesser: proto.GemColor_GemColorYellow},
+	32640: {description: "Requires more Blue Gems than Yellow Gems.", compareColorGreater: proto.GemColor_GemColorBlue, compareColorLesser: proto.GemColor_GemColorYellow},
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// The validator looks up the spec of the player, which needs a registered agent.
+func init() {
+	registerFakeAgent(proto.Player_ArcaneMage{}, proto.Spec_SpecArcaneMage, func(fa *FakeAgent) {})
+	registerFakeAgent(proto.Player_ArmsWarrior{}, proto.Spec_SpecArmsWarrior, func(fa *FakeAgent) {})
+	registerFakeAgent(proto.Player_FuryWarrior{}, proto.Spec_SpecFuryWarrior, func(fa *FakeAgent) {})
+}
+
+func addGearValidationTestItems(t *testing.T) {
+	addTestItems(t,
+		Item{ID: 9100001, Name: "Plate Helm", Type: proto.ItemType_ItemTypeHead, ArmorType: proto.ArmorType_ArmorTypePlate, GemSockets: []proto.GemColor{proto.GemColor_GemColorMeta}},
+		Item{ID: 9100002, Name: "Unique Ring", Type: proto.ItemType_ItemTypeFinger, Unique: true},
+		Item{ID: 9100003, Name: "Staff", Type: proto.ItemType_ItemTypeWeapon, WeaponType: proto.WeaponType_WeaponTypeStaff, HandType: proto.HandType_HandTypeTwoHand},
+		Item{ID: 9100004, Name: "Bracers", Type: proto.ItemType_ItemTypeWrist, ArmorType: proto.ArmorType_ArmorTypeCloth,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{
+				int32(proto.ItemLevelState_Base):           {Ilvl: 496},
+				int32(proto.ItemLevelState_UpgradeStepOne): {Ilvl: 500},
+			}},
+		Item{ID: 9100005, Name: "Sword", Type: proto.ItemType_ItemTypeWeapon, WeaponType: proto.WeaponType_WeaponTypeSword, HandType: proto.HandType_HandTypeOneHand},
+		Item{ID: 9100006, Name: "Tome", Type: proto.ItemType_ItemTypeWeapon, WeaponType: proto.WeaponType_WeaponTypeOffHand, HandType: proto.HandType_HandTypeOffHand},
+		Item{ID: 9100007, Name: "Greatsword", Type: proto.ItemType_ItemTypeWeapon, WeaponType: proto.WeaponType_WeaponTypeSword, HandType: proto.HandType_HandTypeTwoHand},
+		Item{ID: 9100008, Name: "Polearm", Type: proto.ItemType_ItemTypeWeapon, WeaponType: proto.WeaponType_WeaponTypePolearm, HandType: proto.HandType_HandTypeTwoHand},
+	)
+	addTestGems(t,
+		Gem{ID: 9100010, Name: "Chaotic Diamond", Color: proto.GemColor_GemColorMeta},
+		Gem{ID: 9100011, Name: "Serpent's Eye", Color: proto.GemColor_GemColorRed, RequiredProfession: proto.Profession_Jewelcrafting},
+	)
+	addTestMetaGemCondition(t, 9100010, metaGemCondition{description: "Requires at least 1 Red Gem.", minRed: 1})
+}
+
+func gearValidationTestPlayer(items map[proto.ItemSlot]*proto.ItemSpec) *proto.Player {
+	equipment := make([]*proto.ItemSpec, NumItemSlots)
+	for i := range equipment {
+		equipment[i] = &proto.ItemSpec{}
+	}
+	for slot, item := range items {
+		equipment[slot] = item
+	}
+	return &proto.Player{
+		Class:       proto.Class_ClassMage,
+		Spec:        &proto.Player_ArcaneMage{},
+		Profession1: proto.Profession_Engineering,
+		Equipment:   &proto.EquipmentSpec{Items: equipment},
+	}
+}
+
+func TestValidateEquipment(t *testing.T) {
+	addGearValidationTestItems(t)
+
+	player := gearValidationTestPlayer(map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotHead:     {Id: 9100001, Gems: []int32{9100010}},
+		proto.ItemSlot_ItemSlotFinger1:  {Id: 9100002},
+		proto.ItemSlot_ItemSlotFinger2:  {Id: 9100002},
+		proto.ItemSlot_ItemSlotWrist:    {Id: 9100004, UpgradeStep: proto.ItemLevelState_UpgradeStepTwo, Gems: []int32{9100011}},
+		proto.ItemSlot_ItemSlotMainHand: {Id: 9100003},
+		proto.ItemSlot_ItemSlotOffHand:  {Id: 9199999},
+	})
+	result := ValidateEquipment(player)
+
+	expectedErrors := []proto.GearIssueType{
+		proto.GearIssueType_GearIssueArmorType,
+		proto.GearIssueType_GearIssueUpgradeStep,
+		proto.GearIssueType_GearIssueProfession, // Blacksmithing socket.
+		proto.GearIssueType_GearIssueProfession, // Jewelcrafting gem.
+		proto.GearIssueType_GearIssueUniqueEquipped,
+		proto.GearIssueType_GearIssueMissingFromDatabase,
+	}
+	if len(result.Errors) != len(expectedErrors) {
+		t.Fatalf("Expected %d errors, got %v", len(expectedErrors), result.Errors)
+	}
+	for i, issue := range result.Errors {
+		if issue.Type != expectedErrors[i] {
+			t.Errorf("Expected error %d to be %s, got %s", i, expectedErrors[i], issue)
+		}
+	}
+
+	// The Jewelcrafting gem counts as red, so the meta gem is active.
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", result.Warnings)
+	}
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotWrist].Gems = nil
+	result = ValidateEquipment(player)
+	if len(result.Warnings) != 1 || result.Warnings[0].Type != proto.GearIssueType_GearIssueInactiveMetaGem {
+		t.Errorf("Expected an inactive meta gem warning, got %v", result.Warnings)
+	}
+}
+
+func TestValidateEquipmentWeapons(t *testing.T) {
+	addGearValidationTestItems(t)
+
+	player := gearValidationTestPlayer(map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotMainHand: {Id: 9100003},
+	})
+	if result := ValidateEquipment(player); len(result.Errors) != 0 {
+		t.Errorf("Expected a staff to be valid for mages, got %v", result.Errors)
+	}
+
+	player.Class = proto.Class_ClassRogue
+	player.Spec = &proto.Player_CombatRogue{}
+	if result := ValidateEquipment(player); len(result.Errors) != 1 || result.Errors[0].Type != proto.GearIssueType_GearIssueWeaponType {
+		t.Errorf("Expected a weapon type error for a rogue with a staff, got %v", result.Errors)
+	}
+}
+
+func expectGearErrors(t *testing.T, description string, player *proto.Player, expected ...proto.GearIssueType) {
+	t.Helper()
+	result := ValidateEquipment(player)
+	if len(result.Errors) != len(expected) {
+		t.Errorf("%s: expected errors %v, got %v", description, expected, result.Errors)
+		return
+	}
+	for i, issue := range result.Errors {
+		if issue.Type != expected[i] {
+			t.Errorf("%s: expected error %d to be %s, got %s", description, i, expected[i], issue)
+		}
+	}
+}
+
+func TestValidateEquipmentWeaponCombos(t *testing.T) {
+	addGearValidationTestItems(t)
+
+	player := gearValidationTestPlayer(map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotMainHand: {Id: 9100005},
+		proto.ItemSlot_ItemSlotOffHand:  {Id: 9100006},
+	})
+	expectGearErrors(t, "Mage with a sword and an off hand", player)
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{Id: 9100005}
+	expectGearErrors(t, "Mage with a sword in the off hand", player, proto.GearIssueType_GearIssueWeaponType)
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 9100003}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{Id: 9100006}
+	expectGearErrors(t, "Mage with a staff and an off hand", player, proto.GearIssueType_GearIssueWeaponType)
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 9100006}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{}
+	expectGearErrors(t, "Mage with an off hand in the main hand", player, proto.GearIssueType_GearIssueItemSlot)
+
+	player.Class = proto.Class_ClassWarrior
+	player.Spec = &proto.Player_ArmsWarrior{}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 9100007}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{Id: 9100007}
+	expectGearErrors(t, "Arms Warrior with two greatswords", player, proto.GearIssueType_GearIssueItemSlot, proto.GearIssueType_GearIssueWeaponType)
+
+	player.Spec = &proto.Player_FuryWarrior{}
+	expectGearErrors(t, "Fury Warrior with two greatswords", player)
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{Id: 9100008}
+	expectGearErrors(t, "Fury Warrior with a polearm in the off hand", player, proto.GearIssueType_GearIssueWeaponType)
+
+	player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand] = &proto.ItemSpec{}
+	player.Equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: 9100008}
+	expectGearErrors(t, "Fury Warrior with only a polearm", player)
+}
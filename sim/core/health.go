@@ -57,6 +57,12 @@ func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *Resour
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Gained %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
 	}
+	if sim.CombatLog != nil {
+		hb.unit.logResourceEvent(sim, metrics, amount, newHealth, false)
+	}
+	if hb.unit.Metrics.healthTimeline != nil {
+		hb.unit.Metrics.healthTimeline.recordHealth(sim, newHealth, hb.MaxHealth())
+	}
 
 	hb.currentHealth = newHealth
 }
@@ -79,10 +85,16 @@ func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
 		}
 		hb.unit.Metrics.tmiList = append(hb.unit.Metrics.tmiList, entry)
 	}
+	if hb.unit.Metrics.healthTimeline != nil {
+		hb.unit.Metrics.healthTimeline.recordHealth(sim, newHealth, hb.MaxHealth())
+	}
 
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Spent %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
 	}
+	if sim.CombatLog != nil {
+		hb.unit.logResourceEvent(sim, metrics, amount, newHealth, true)
+	}
 
 	hb.currentHealth = newHealth
 }
@@ -101,6 +113,12 @@ func (hb *healthBar) UpdateMaxHealth(sim *Simulation, bonusHealth float64, metri
 var ChanceOfDeathAuraLabel = "Chance of Death"
 
 func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel) {
+	character.trackHealth(healingModel, true)
+}
+
+// Units without a rotation, such as target dummies, pass reactToDamage=false
+// since they have nothing to react with.
+func (character *Character) trackHealth(healingModel *proto.HealingModel, reactToDamage bool) {
 	character.Unit.Metrics.isTanking = false
 	for _, target := range character.Env.Encounter.AllTargetUnits {
 		if (target.CurrentTarget == &character.Unit) || (target.SecondaryTarget == &character.Unit) {
@@ -117,7 +135,9 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 		OnSpellHitTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
 			if result.Damage > 0 {
 				aura.Unit.RemoveHealth(sim, result.Damage)
-				aura.Unit.ReactToEvent(sim, false)
+				if reactToDamage {
+					aura.Unit.ReactToEvent(sim, false)
+				}
 
 				if (aura.Unit.CurrentHealth() <= 0) && !aura.Unit.Metrics.Died {
 					// Queue a pending action to let shield effects give health
@@ -159,6 +179,8 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 		return
 	}
 
+	character.Unit.Metrics.healthTimeline = newHealthTimeline()
+
 	if healingModel == nil {
 		return
 	}
@@ -173,6 +195,9 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 func (character *Character) Died(sim *Simulation) {
 	aura := character.GetAura(ChanceOfDeathAuraLabel)
 	aura.Unit.Metrics.Died = true
+	if aura.Unit.Metrics.healthTimeline != nil {
+		aura.Unit.Metrics.healthTimeline.died(sim)
+	}
 	if sim.Log != nil {
 		character.Log(sim, "Dead")
 	}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"time"
+)
+
+// Returns whether the unit is in the middle of a cast which can be interrupted.
+func (unit *Unit) IsCastingInterruptible(sim *Simulation) bool {
+	return unit.Hardcast.Interruptible && unit.Hardcast.Expires > sim.CurrentTime
+}
+
+// Interrupts the unit's current cast, if it can be interrupted, and locks the
+// unit out of casting for the given duration. The interrupted spell has no
+// effect and doesn't trigger its cooldown. Returns whether a cast was
+// interrupted.
+func (unit *Unit) InterruptCast(sim *Simulation, interrupter *Spell, lockout time.Duration) bool {
+	if !unit.IsCastingInterruptible(sim) {
+		return false
+	}
+
+	if sim.Log != nil {
+		unit.Log(sim, "Cast of %s interrupted by %s, locked out for %s", unit.Hardcast.ActionID, interrupter.ActionID, lockout)
+	}
+
+	unit.Metrics.castsInterrupted++
+
+	// The pending hardcast action is a no-op once Expires is reset.
+	unit.Hardcast.Expires = startingCDTime
+	unit.Hardcast.Interruptible = false
+	unit.SetGCDTimer(sim, sim.CurrentTime+lockout)
+	return true
+}
@@ -98,6 +98,9 @@ func (unit *Unit) AddMana(sim *Simulation, amount float64, metrics *ResourceMetr
 	if sim.Log != nil {
 		unit.Log(sim, "Gained %0.3f mana from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldMana, newMana, unit.MaxMana())
 	}
+	if sim.CombatLog != nil {
+		unit.logResourceEvent(sim, metrics, amount, newMana, false)
+	}
 
 	unit.currentMana = newMana
 	unit.Metrics.ManaGained += newMana - oldMana
@@ -114,6 +117,9 @@ func (unit *Unit) SpendMana(sim *Simulation, amount float64, metrics *ResourceMe
 	if sim.Log != nil {
 		unit.Log(sim, "Spent %0.3f mana from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, unit.CurrentMana(), newMana, unit.MaxMana())
 	}
+	if sim.CombatLog != nil {
+		unit.logResourceEvent(sim, metrics, amount, newMana, true)
+	}
 
 	unit.currentMana = newMana
 	unit.Metrics.ManaSpent += amount
@@ -56,6 +56,12 @@ func (distMetrics *DistributionMetrics) doneIteration(sim *Simulation) {
 	distMetrics.hist[dpsRounded]++
 }
 
+// Returns the standard error of the mean over the iterations aggregated so far.
+func (distMetrics *DistributionMetrics) stdevOfMean() float64 {
+	_, stdev := distMetrics.meanAndStdDev()
+	return stdevOfMean(stdev, distMetrics.n)
+}
+
 func (distMetrics *DistributionMetrics) ToProto() *proto.DistributionMetrics {
 	mean, stdev := distMetrics.meanAndStdDev()
 
@@ -89,12 +95,16 @@ type UnitMetrics struct {
 	dtps   DistributionMetrics
 	tmi    DistributionMetrics
 	hps    DistributionMetrics
+	ehps   DistributionMetrics
 	tto    DistributionMetrics
 
 	tmiList   []tmiListItem
 	isTanking bool
 	tmiBin    int32
 
+	// Only set for tanks.
+	healthTimeline *healthTimeline
+
 	CharacterIterationMetrics
 
 	// Aggregate values. These are updated after each iteration.
@@ -103,6 +113,12 @@ type UnitMetrics struct {
 	oomTimeSum   float64
 	actions      map[ActionID]*ActionMetrics
 	resources    []*ResourceMetrics
+
+	// Casts of spells with SpellFlagInterruptible, and how many of them were interrupted.
+	interruptibleCasts int64
+	castsInterrupted   int64
+
+	damageTaken map[ActionID]*damageTakenMetrics
 }
 
 // Metrics for the current iteration, for 1 agent. Keep this as a separate
@@ -175,6 +191,7 @@ type SpellMetrics struct {
 	TotalThreat            float64 // Threat generated by all casts of this spell.
 	TotalHealing           float64 // Healing done by all casts of this spell.
 	TotalCritHealing       float64 // Healing done by all critical casts of this spell.
+	TotalOverhealing       float64 // Healing done by all casts of this spell that exceeded the target's missing health.
 	TotalShielding         float64 // Shielding done by all casts of this spell.
 	TotalCastTime          time.Duration
 }
@@ -206,6 +223,7 @@ type TargetedActionMetrics struct {
 	Threat            float64
 	Healing           float64
 	CritHealing       float64
+	Overhealing       float64
 	Shielding         float64
 	CastTime          time.Duration
 }
@@ -237,6 +255,7 @@ func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 		Threat:            tam.Threat,
 		Healing:           tam.Healing,
 		CritHealing:       tam.CritHealing,
+		Overhealing:       tam.Overhealing,
 		Shielding:         tam.Shielding,
 		CastTimeMs:        float64(tam.CastTime.Milliseconds()),
 	}
@@ -249,6 +268,7 @@ func NewUnitMetrics() UnitMetrics {
 		dtps:    NewDistributionMetrics(),
 		tmi:     NewDistributionMetrics(),
 		hps:     NewDistributionMetrics(),
+		ehps:    NewDistributionMetrics(),
 		tto:     NewDistributionMetrics(),
 		actions: make(map[ActionID]*ActionMetrics),
 	}
@@ -398,6 +418,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Threat += spellTargetMetrics.TotalThreat
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.CritHealing += spellTargetMetrics.TotalCritHealing
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
 		if !spell.Flags.Matches(SpellFlagPassiveSpell) {
 			tam.CastTime += spellTargetMetrics.TotalCastTime
@@ -411,10 +432,28 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 			unitMetrics.threat.Total += spellTargetMetrics.TotalThreat
 		} else {
 			unitMetrics.hps.Total += spellTargetMetrics.TotalHealing + spellTargetMetrics.TotalShielding
+			unitMetrics.ehps.Total += spellTargetMetrics.TotalHealing - spellTargetMetrics.TotalOverhealing + spellTargetMetrics.TotalShielding
 		}
 	}
 }
 
+// Returns the damage and effective healing done by the unit's spells so far in
+// the current iteration, which unlike the unit metrics is available before the
+// iteration is done.
+func (unit *Unit) IterationDamageAndHealing() (float64, float64) {
+	var damage, healing float64
+	for _, spell := range unit.Spellbook {
+		for i, spellTargetMetrics := range spell.SpellMetrics {
+			if unit.IsOpponent(unit.AttackTables[i].Defender) {
+				damage += spellTargetMetrics.TotalDamage
+			} else {
+				healing += spellTargetMetrics.TotalHealing - spellTargetMetrics.TotalOverhealing + spellTargetMetrics.TotalShielding
+			}
+		}
+	}
+	return damage, healing
+}
+
 // This should be called at the end of each iteration, to include metrics from Pets in
 // those of their owner.
 // Assumes that doneIteration() has already been called on the pet metrics.
@@ -445,8 +484,12 @@ func (unitMetrics *UnitMetrics) reset() {
 	unitMetrics.tmi.reset()
 	unitMetrics.tmiList = nil
 	unitMetrics.hps.reset()
+	unitMetrics.ehps.reset()
 	unitMetrics.tto.reset()
 	unitMetrics.CharacterIterationMetrics = CharacterIterationMetrics{}
+	if unitMetrics.healthTimeline != nil {
+		unitMetrics.healthTimeline.reset()
+	}
 
 	for _, resourceMetrics := range unitMetrics.resources {
 		resourceMetrics.reset()
@@ -487,12 +530,16 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 		// Hack because of the way DistributionMetrics does its calculations.
 		unitMetrics.tmi.Total *= sim.Duration.Seconds()
 	}
+	if unitMetrics.healthTimeline != nil {
+		unitMetrics.healthTimeline.doneIteration(sim)
+	}
 
 	unitMetrics.dps.doneIteration(sim)
 	unitMetrics.threat.doneIteration(sim)
 	unitMetrics.dtps.doneIteration(sim)
 	unitMetrics.tmi.doneIteration(sim)
 	unitMetrics.hps.doneIteration(sim)
+	unitMetrics.ehps.doneIteration(sim)
 	unitMetrics.tto.doneIteration(sim)
 
 	unitMetrics.oomTimeSum += unitMetrics.OOMTime.Seconds()
@@ -587,9 +634,19 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		Dtps:          unitMetrics.dtps.ToProto(),
 		Tmi:           unitMetrics.tmi.ToProto(),
 		Hps:           unitMetrics.hps.ToProto(),
+		Ehps:          unitMetrics.ehps.ToProto(),
 		Tto:           unitMetrics.tto.ToProto(),
 		SecondsOomAvg: unitMetrics.oomTimeSum / n,
 		ChanceOfDeath: float64(unitMetrics.numItersDead) / n,
+
+		InterruptibleCastsAvg: float64(unitMetrics.interruptibleCasts) / n,
+		CastsInterruptedAvg:   float64(unitMetrics.castsInterrupted) / n,
+
+		DamageTaken: unitMetrics.damageTakenToProto(n),
+	}
+
+	if unitMetrics.healthTimeline != nil {
+		protoMetrics.HealthTimeline = unitMetrics.healthTimeline.toProto()
 	}
 
 	if len(unitMetrics.deathSeeds) > 0 {
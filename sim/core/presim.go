@@ -70,6 +70,7 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 	presimRequest.SimOptions.Debug = false
 	presimRequest.SimOptions.DebugFirstIteration = false
 	presimRequest.SimOptions.Iterations = numPresimIterations
+	presimRequest.SimOptions.TargetStdevOfMean = 0
 	duration := DurationFromSeconds(presimRequest.Encounter.Duration)
 
 	var lastResult *proto.RaidSimResult
@@ -94,7 +95,9 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 		}
 
 		// Run the presim.
-		presimResult := runSim(presimRequest, nil, true, sim.Signals)
+		presimResult := globalPresimCache.getOrRun(presimRequest, func() *proto.RaidSimResult {
+			return runSim(presimRequest, nil, true, sim.Signals)
+		})
 		lastResult = presimResult
 
 		if presimResult.Error != nil {
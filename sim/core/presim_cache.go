@@ -0,0 +1,232 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Maximum number of presim results kept in memory.
+const PresimCacheSize = 256
+
+// Presims always use the same seed and iteration count, so their results only
+// depend on the presim request. This caches them across requests, e.g. for
+// the many near-identical requests of stat weights and bulk sims, as well as
+// the concurrent splits of a single request.
+type presimCache struct {
+	mu      sync.Mutex
+	entries map[string]*presimCacheEntry
+	order   []string // Keys in insertion order, for eviction.
+
+	// On-disk cache folder, disabled when empty.
+	dir       string
+	buildHash string
+
+	// Used for testing.
+	hits   int
+	misses int
+}
+
+type presimCacheEntry struct {
+	// Closed once result is set.
+	ready  chan struct{}
+	result *proto.RaidSimResult
+}
+
+var globalPresimCache = &presimCache{
+	entries: make(map[string]*presimCacheEntry),
+}
+
+// Additionally stores presim results in dir, so they are kept across restarts.
+// A hash of the running build is part of each key, so results from other code
+// or database versions are not used.
+func EnablePresimDiskCache(dir string) error {
+	buildHash, err := executableHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash the executable: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create presim cache folder: %w", err)
+	}
+
+	globalPresimCache.mu.Lock()
+	defer globalPresimCache.mu.Unlock()
+	globalPresimCache.dir = dir
+	globalPresimCache.buildHash = buildHash
+	return nil
+}
+
+// The database is embedded in the executable, so this changes with both the
+// code and the database. Unlike the version, it also changes between
+// development builds.
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns a hash of everything in the request which can affect the presim
+// metrics. Logging and other output options are left out.
+func presimCacheKey(request *proto.RaidSimRequest, buildHash string) (string, error) {
+	options := request.SimOptions
+	keyRequest := &proto.RaidSimRequest{
+		Raid:      request.Raid,
+		Encounter: request.Encounter,
+		SimOptions: &proto.SimOptions{
+			Iterations:        options.GetIterations(),
+			RandomSeed:        options.GetRandomSeed(),
+			IsTest:            options.GetIsTest(),
+			UseLabeledRands:   options.GetUseLabeledRands(),
+			TargetStdevOfMean: options.GetTargetStdevOfMean(),
+			MaxIterations:     options.GetMaxIterations(),
+		},
+	}
+
+	data, err := googleProto.MarshalOptions{Deterministic: true}.Marshal(keyRequest)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(buildHash))
+	hash.Write([]byte{0})
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns the cached result for the presim request, calling run to compute
+// it if needed. Concurrent calls for the same request wait for the first
+// one instead of repeating the presim. Results with errors are not cached.
+func (cache *presimCache) getOrRun(request *proto.RaidSimRequest, run func() *proto.RaidSimResult) *proto.RaidSimResult {
+	cache.mu.Lock()
+	key, err := presimCacheKey(request, cache.buildHash)
+	if err != nil {
+		cache.mu.Unlock()
+		return run()
+	}
+
+	if entry, ok := cache.entries[key]; ok {
+		cache.hits++
+		cache.mu.Unlock()
+
+		<-entry.ready
+		if entry.result == nil {
+			// The presim which was in progress failed, e.g. because it was
+			// aborted, so try again with this request's signals.
+			return run()
+		}
+		return googleProto.Clone(entry.result).(*proto.RaidSimResult)
+	}
+
+	entry := &presimCacheEntry{ready: make(chan struct{})}
+	cache.entries[key] = entry
+	cache.order = append(cache.order, key)
+	if len(cache.order) > PresimCacheSize {
+		delete(cache.entries, cache.order[0])
+		cache.order = cache.order[1:]
+	}
+	dir := cache.dir
+	cache.mu.Unlock()
+
+	result := readPresimCacheFile(dir, key)
+	if result == nil {
+		cache.mu.Lock()
+		cache.misses++
+		cache.mu.Unlock()
+
+		result = run()
+		if result.Error == nil {
+			writePresimCacheFile(dir, key, result)
+		}
+	}
+
+	if result.Error == nil {
+		entry.result = googleProto.Clone(result).(*proto.RaidSimResult)
+	} else {
+		cache.mu.Lock()
+		if cache.entries[key] == entry {
+			delete(cache.entries, key)
+			// Otherwise a retry adds the key again, and evicting the stale copy would drop the new entry.
+			if idx := slices.Index(cache.order, key); idx != -1 {
+				cache.order = slices.Delete(cache.order, idx, idx+1)
+			}
+		}
+		cache.mu.Unlock()
+	}
+	close(entry.ready)
+
+	return result
+}
+
+func (cache *presimCache) clear() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = make(map[string]*presimCacheEntry)
+	cache.order = nil
+	cache.hits = 0
+	cache.misses = 0
+}
+
+func readPresimCacheFile(dir string, key string) *proto.RaidSimResult {
+	if dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".binpb"))
+	if err != nil {
+		return nil
+	}
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(data, result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// Failing to write is not an error, the result just won't be cached on disk.
+func writePresimCacheFile(dir string, key string, result *proto.RaidSimResult) {
+	if dir == "" {
+		return
+	}
+	data, err := googleProto.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first, so other processes never read a
+	// partially written result.
+	file, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return
+	}
+	if err := os.Rename(file.Name(), filepath.Join(dir, key+".binpb")); err != nil {
+		os.Remove(file.Name())
+	}
+}
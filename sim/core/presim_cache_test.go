@@ -0,0 +1,144 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func presimCacheTestRequest() *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		RequestId: "first",
+		SimOptions: &proto.SimOptions{
+			Iterations: 10,
+			RandomSeed: 100,
+		},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Healer",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_RestorationShaman{},
+							Equipment: &proto.EquipmentSpec{},
+							// A cadence without HPS requires a presim.
+							HealingModel: &proto.HealingModel{CadenceSeconds: 2},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 60,
+		},
+	}
+}
+
+func TestPresimCacheKey(t *testing.T) {
+	request := presimCacheTestRequest()
+	key, _ := presimCacheKey(request, "v1")
+
+	sameRequest := presimCacheTestRequest()
+	sameRequest.RequestId = "second"
+	if sameKey, _ := presimCacheKey(sameRequest, "v1"); sameKey != key {
+		t.Fatalf("Expected the request ID to be ignored")
+	}
+
+	if otherKey, _ := presimCacheKey(request, "v2"); otherKey == key {
+		t.Fatalf("Expected the build hash to change the key")
+	}
+
+	loggedRequest := presimCacheTestRequest()
+	loggedRequest.SimOptions.CombatLogMode = proto.CombatLogMode_CombatLogEvents
+	loggedRequest.SimOptions.ProfileApl = true
+	if loggedKey, _ := presimCacheKey(loggedRequest, "v1"); loggedKey != key {
+		t.Fatalf("Expected output options to be ignored")
+	}
+
+	otherRequest := presimCacheTestRequest()
+	otherRequest.Encounter.Duration = 120
+	if otherKey, _ := presimCacheKey(otherRequest, "v1"); otherKey == key {
+		t.Fatalf("Expected the settings to change the key")
+	}
+}
+
+func TestPresimCacheReusesResults(t *testing.T) {
+	globalPresimCache.clear()
+	defer globalPresimCache.clear()
+
+	first := RunSim(presimCacheTestRequest(), nil, simsignals.CreateSignals())
+	if first.Error != nil {
+		t.Fatalf("Sim failed: %s", first.Error.Message)
+	}
+	if globalPresimCache.misses != 1 || globalPresimCache.hits != 0 {
+		t.Fatalf("Expected 1 miss and 0 hits after the first sim, got %d and %d", globalPresimCache.misses, globalPresimCache.hits)
+	}
+
+	secondRequest := presimCacheTestRequest()
+	secondRequest.RequestId = "second"
+	second := RunSim(secondRequest, nil, simsignals.CreateSignals())
+	if globalPresimCache.misses != 1 || globalPresimCache.hits != 1 {
+		t.Fatalf("Expected 1 miss and 1 hit after the second sim, got %d and %d", globalPresimCache.misses, globalPresimCache.hits)
+	}
+
+	// Actions are listed in map order, so only compare ordered metrics.
+	firstPlayer := first.RaidMetrics.Parties[0].Players[0]
+	secondPlayer := second.RaidMetrics.Parties[0].Players[0]
+	if firstPlayer.Dtps.Avg != secondPlayer.Dtps.Avg || !googleProto.Equal(&proto.UnitMetrics{Resources: firstPlayer.Resources}, &proto.UnitMetrics{Resources: secondPlayer.Resources}) {
+		t.Fatalf("Expected cached presims to give the same results")
+	}
+}
+
+func TestPresimDiskCache(t *testing.T) {
+	globalPresimCache.clear()
+	defer func() {
+		globalPresimCache.clear()
+		globalPresimCache.dir = ""
+		globalPresimCache.buildHash = ""
+	}()
+
+	if err := EnablePresimDiskCache(t.TempDir()); err != nil {
+		t.Fatalf("Failed to enable disk cache: %s", err)
+	}
+	RunSim(presimCacheTestRequest(), nil, simsignals.CreateSignals())
+
+	// Only the files remain, as after a restart.
+	globalPresimCache.clear()
+	RunSim(presimCacheTestRequest(), nil, simsignals.CreateSignals())
+	if globalPresimCache.misses != 0 {
+		t.Fatalf("Expected the presim to be read from disk, got %d misses", globalPresimCache.misses)
+	}
+}
+
+func TestPresimCacheRetriesFailedPresims(t *testing.T) {
+	globalPresimCache.clear()
+	defer globalPresimCache.clear()
+
+	request := presimCacheTestRequest()
+	failed := func() *proto.RaidSimResult {
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: "aborted"}}
+	}
+	succeeded := func() *proto.RaidSimResult {
+		return &proto.RaidSimResult{}
+	}
+
+	for range 3 {
+		globalPresimCache.getOrRun(request, failed)
+	}
+	if len(globalPresimCache.entries) != 0 || len(globalPresimCache.order) != 0 {
+		t.Fatalf("Expected failed presims to leave nothing in the cache, got %d entries and %d keys", len(globalPresimCache.entries), len(globalPresimCache.order))
+	}
+
+	globalPresimCache.getOrRun(request, succeeded)
+	if len(globalPresimCache.entries) != 1 || len(globalPresimCache.order) != 1 {
+		t.Fatalf("Expected the retried presim to be cached once, got %d entries and %d keys", len(globalPresimCache.entries), len(globalPresimCache.order))
+	}
+}
@@ -266,6 +266,30 @@ func (raid *Raid) GetRaidBuffs(baseRaidBuffs *proto.RaidBuffs) *proto.RaidBuffs
 	return raidBuffs
 }
 
+// Adds the raid buffs provided by the players in the raid which are otherwise
+// taken from the raid config.
+func (raid *Raid) addCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			player.AddCompositionRaidBuffs(raidBuffs)
+		}
+	}
+}
+
+// Computes the debuffs the raid applies to enemies, on top of baseDebuffs.
+func (raid *Raid) GetRaidDebuffs(baseDebuffs *proto.Debuffs) *proto.Debuffs {
+	debuffs := &proto.Debuffs{}
+	if baseDebuffs != nil {
+		debuffs = googleProto.Clone(baseDebuffs).(*proto.Debuffs)
+	}
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			player.AddRaidDebuffs(debuffs)
+		}
+	}
+	return debuffs
+}
+
 // Precompute the playersAndPets array for each party.
 func (raid *Raid) updatePlayersAndPets() {
 	var raidPlayers []*Unit
@@ -301,12 +325,27 @@ func (raid *Raid) updatePlayersAndPets() {
 }
 
 func (raid *Raid) applyCharacterEffects(raidConfig *proto.Raid) *proto.RaidStats {
-	raidBuffs := raid.GetRaidBuffs(raidConfig.Buffs)
-	raidStats := &proto.RaidStats{}
+	baseRaidBuffs, baseDebuffs := raidConfig.Buffs, raidConfig.Debuffs
+	if raidConfig.BuffsFromComposition {
+		baseRaidBuffs, baseDebuffs = nil, nil
+	}
+
+	raidBuffs := raid.GetRaidBuffs(baseRaidBuffs)
+	if raidConfig.BuffsFromComposition {
+		raid.addCompositionRaidBuffs(raidBuffs)
+	}
+	raidStats := &proto.RaidStats{
+		Buffs:   raidBuffs,
+		Debuffs: raid.GetRaidDebuffs(baseDebuffs),
+	}
 
 	for partyIdx, party := range raid.Parties {
 		partyConfig := raidConfig.Parties[partyIdx]
-		partyBuffs := party.GetPartyBuffs(partyConfig.Buffs)
+		basePartyBuffs := partyConfig.Buffs
+		if raidConfig.BuffsFromComposition {
+			basePartyBuffs = nil
+		}
+		partyBuffs := party.GetPartyBuffs(basePartyBuffs)
 		partyStats := &proto.PartyStats{
 			Players: make([]*proto.PlayerStats, 5),
 		}
@@ -315,6 +354,9 @@ func (raid *Raid) applyCharacterEffects(raidConfig *proto.Raid) *proto.RaidStats
 		for playerIdx, player := range party.Players {
 			if playerIdx >= len(partyConfig.Players) {
 				// This happens for target dummies.
+				if raidConfig.DamageProfile != nil {
+					player.(*TargetDummy).enableDamageTaken(raidConfig.DamageProfile)
+				}
 				continue
 			}
 			playerConfig := partyConfig.Players[playerIdx]
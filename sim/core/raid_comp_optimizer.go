@@ -0,0 +1,489 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultRaidCompMaxCompositions = 500
+	DefaultRaidCompNumResults      = 10
+
+	// Compositions are only enumerated up to this many, to filter them by the
+	// constraints. Larger pools go straight to the swap search.
+	maxRaidCompEnumeration = 100000
+)
+
+func isHealingSpec(spec proto.Spec) bool {
+	switch spec {
+	case proto.Spec_SpecRestorationDruid, proto.Spec_SpecHolyPaladin, proto.Spec_SpecDisciplinePriest,
+		proto.Spec_SpecHolyPriest, proto.Spec_SpecRestorationShaman, proto.Spec_SpecMistweaverMonk:
+		return true
+	}
+	return false
+}
+
+type raidCompCandidate struct {
+	player *proto.Player
+	// Index of the player in the base raid.
+	raidIndex int32
+
+	isTank   bool
+	isHealer bool
+
+	buffs   *proto.RaidBuffs
+	debuffs *proto.Debuffs
+	// Bit i is set if this player can provide the i-th required buff or
+	// debuff, in at least some compositions.
+	provides uint64
+}
+
+// A composition is a sorted list of candidate indices.
+type raidComp []int
+
+func (comp raidComp) key() string {
+	return strings.Join(MapSlice(comp, strconv.Itoa), ",")
+}
+
+// Returns the populated fields of the required buffs and debuffs, as a
+// function which turns a player's buffs and debuffs into a bitmask.
+func raidCompRequirements(requiredBuffs *proto.RaidBuffs, requiredDebuffs *proto.Debuffs) (func(*proto.RaidBuffs, *proto.Debuffs) uint64, int) {
+	var buffFields, debuffFields []protoreflect.FieldDescriptor
+	if requiredBuffs != nil {
+		requiredBuffs.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			buffFields = append(buffFields, fd)
+			return true
+		})
+	}
+	if requiredDebuffs != nil {
+		requiredDebuffs.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			debuffFields = append(debuffFields, fd)
+			return true
+		})
+	}
+
+	provides := func(buffs *proto.RaidBuffs, debuffs *proto.Debuffs) uint64 {
+		var mask uint64
+		for i, fd := range buffFields {
+			if buffs.ProtoReflect().Has(fd) {
+				mask |= 1 << i
+			}
+		}
+		for i, fd := range debuffFields {
+			if debuffs.ProtoReflect().Has(fd) {
+				mask |= 1 << (len(buffFields) + i)
+			}
+		}
+		return mask
+	}
+	return provides, len(buffFields) + len(debuffFields)
+}
+
+// Returns every player in the base raid, with the buffs and debuffs they can
+// provide. Some depend on the rest of the raid, e.g. a Paladin's blessing, so
+// this includes those provided both on their own and alongside the whole pool.
+func raidCompCandidates(baseRequest *proto.RaidSimRequest) []*raidCompCandidate {
+	tankIndices := map[int32]bool{}
+	for _, tank := range baseRequest.Raid.Tanks {
+		if tank.Type == proto.UnitReference_Player {
+			tankIndices[tank.Index] = true
+		}
+	}
+
+	poolRaid := googleProto.Clone(baseRequest.Raid).(*proto.Raid)
+	poolRaid.NumActiveParties = 0
+	poolRaid.BuffsFromComposition = true
+	poolEnv, _, _ := NewEnvironment(poolRaid, googleProto.Clone(baseRequest.Encounter).(*proto.Encounter), false)
+
+	var candidates []*raidCompCandidate
+	for partyIdx, party := range baseRequest.Raid.Parties {
+		if party == nil {
+			continue
+		}
+		for playerIdx, player := range party.Players {
+			if player == nil || player.Class == proto.Class_ClassUnknown {
+				continue
+			}
+			raidIndex := int32(partyIdx*5 + playerIdx)
+
+			soloRaid := SinglePlayerRaidProto(googleProto.Clone(player).(*proto.Player), nil, nil, nil)
+			soloRaid.BuffsFromComposition = true
+			_, raidStats, _ := NewEnvironment(soloRaid, googleProto.Clone(baseRequest.Encounter).(*proto.Encounter), false)
+
+			poolParty := poolEnv.Raid.Parties[slices.IndexFunc(poolEnv.Raid.Parties, func(poolParty *Party) bool { return poolParty.Index == partyIdx })]
+			poolPlayer := poolParty.Players[slices.IndexFunc(poolParty.Players, func(agent Agent) bool { return agent.GetCharacter().PartyIndex == playerIdx })]
+			poolPlayer.AddRaidBuffs(raidStats.Buffs)
+			poolPlayer.GetCharacter().AddRaidBuffs(raidStats.Buffs)
+			poolPlayer.AddCompositionRaidBuffs(raidStats.Buffs)
+			poolPlayer.AddRaidDebuffs(raidStats.Debuffs)
+
+			candidates = append(candidates, &raidCompCandidate{
+				player:    player,
+				raidIndex: raidIndex,
+				isTank:    tankIndices[raidIndex],
+				isHealer:  isHealingSpec(PlayerProtoToSpec(player)),
+				buffs:     raidStats.Buffs,
+				debuffs:   raidStats.Debuffs,
+			})
+		}
+	}
+	return candidates
+}
+
+// Returns n choose k, or limit+1 if it is larger than limit.
+func binomialUpTo(n int, k int, limit int) int {
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+		if result > limit {
+			return limit + 1
+		}
+	}
+	return result
+}
+
+// Searches for the raid composition with the highest raid DPS from a pool of
+// candidate players, subject to role and buff coverage constraints. If there
+// are few enough compositions every one is simmed, otherwise the search
+// starts from a composition which meets the constraints and keeps swapping a
+// raid member for a benched player, taking the best swap while that improves
+// DPS.
+func runRaidCompOptimize(request *proto.RaidCompOptimizeRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) (optimizeResult *proto.RaidCompOptimizeResult) {
+	// Invalid settings panic while building the environments.
+	defer func() {
+		if err := recover(); err != nil {
+			optimizeResult = &proto.RaidCompOptimizeResult{Error: ErrorOutcomeFromPanic(err)}
+		}
+	}()
+
+	errorResult := func(format string, args ...any) *proto.RaidCompOptimizeResult {
+		return &proto.RaidCompOptimizeResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if request.BaseSettings == nil || request.BaseSettings.Raid == nil || request.BaseSettings.SimOptions == nil || request.BaseSettings.Encounter == nil {
+		return errorResult("Raid comp request is missing base settings!")
+	}
+	baseRequest := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if baseRequest.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+
+	candidates := raidCompCandidates(baseRequest)
+	raidSize := int(request.RaidSize)
+	if raidSize <= 0 {
+		return errorResult("Raid size must be positive!")
+	}
+	if raidSize > len(candidates) {
+		return errorResult("Raid size is %d, but there are only %d candidate players!", raidSize, len(candidates))
+	}
+
+	providesMask, numRequired := raidCompRequirements(request.RequiredBuffs, request.RequiredDebuffs)
+	if numRequired > 64 {
+		return errorResult("Too many required buffs and debuffs!")
+	}
+	requiredMask := uint64(1)<<numRequired - 1
+	for _, candidate := range candidates {
+		candidate.provides = providesMask(candidate.buffs, candidate.debuffs)
+	}
+
+	maxCompositions := int(request.MaxCompositions)
+	if maxCompositions <= 0 {
+		maxCompositions = DefaultRaidCompMaxCompositions
+	}
+	numResults := int(request.NumResults)
+	if numResults <= 0 {
+		numResults = DefaultRaidCompNumResults
+	}
+
+	// Every composition uses the same seed and labeled RNG, so that they are
+	// compared on the same fight.
+	if baseRequest.SimOptions.RandomSeed == 0 {
+		baseRequest.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseRequest.SimOptions.UseLabeledRands = true
+	baseRequest.SimOptions.Debug = false
+	baseRequest.SimOptions.DebugFirstIteration = false
+
+	raidTemplate := googleProto.Clone(baseRequest.Raid).(*proto.Raid)
+	raidTemplate.Parties = nil
+	raidTemplate.Tanks = nil
+	raidTemplate.NumActiveParties = 0
+	raidTemplate.BuffsFromComposition = true
+
+	// Fills parties of 5 in candidate order, keeping the tank order of the base raid.
+	buildRaid := func(comp raidComp) *proto.Raid {
+		raid := googleProto.Clone(raidTemplate).(*proto.Raid)
+		for i, idx := range comp {
+			if i%5 == 0 {
+				raid.Parties = append(raid.Parties, &proto.Party{Buffs: &proto.PartyBuffs{}})
+			}
+			party := raid.Parties[len(raid.Parties)-1]
+			party.Players = append(party.Players, googleProto.Clone(candidates[idx].player).(*proto.Player))
+		}
+		for _, tank := range baseRequest.Raid.Tanks {
+			if tank.Type != proto.UnitReference_Player {
+				continue
+			}
+			for i, idx := range comp {
+				if candidates[idx].raidIndex == tank.Index {
+					raid.Tanks = append(raid.Tanks, &proto.UnitReference{Type: proto.UnitReference_Player, Index: int32(i)})
+				}
+			}
+		}
+		return raid
+	}
+
+	// Checks the roles and what each player can provide. Some buffs depend on
+	// the rest of the raid, e.g. a Paladin's blessing, so simComps checks the
+	// exact buffs and debuffs of the compositions it sims.
+	isValid := func(comp raidComp) bool {
+		numTanks, numHealers := 0, 0
+		var provides uint64
+		for _, idx := range comp {
+			candidate := candidates[idx]
+			if candidate.isTank {
+				numTanks++
+			}
+			if candidate.isHealer {
+				numHealers++
+			}
+			provides |= candidate.provides
+		}
+		return numTanks >= int(request.MinTanks) && numHealers >= int(request.MinHealers) && provides&requiredMask == requiredMask
+	}
+
+	// Picks the required roles and buffs first, then fills up with the
+	// remaining players in order, preferring damage dealers.
+	initialComp := func() raidComp {
+		selected := make([]bool, len(candidates))
+		var comp raidComp
+		var provides uint64
+		pick := func(count int, pred func(*raidCompCandidate) bool) {
+			for idx, candidate := range candidates {
+				if count <= 0 || len(comp) >= raidSize {
+					return
+				}
+				if !selected[idx] && pred(candidate) {
+					selected[idx] = true
+					comp = append(comp, idx)
+					provides |= candidate.provides
+					count--
+				}
+			}
+		}
+
+		pick(int(request.MinTanks), func(candidate *raidCompCandidate) bool { return candidate.isTank })
+		pick(int(request.MinHealers), func(candidate *raidCompCandidate) bool { return candidate.isHealer })
+		for bit := range numRequired {
+			if provides&(1<<bit) == 0 {
+				pick(1, func(candidate *raidCompCandidate) bool { return candidate.provides&(1<<bit) != 0 })
+			}
+		}
+		pick(raidSize, func(candidate *raidCompCandidate) bool { return !candidate.isTank && !candidate.isHealer })
+		pick(raidSize, func(candidate *raidCompCandidate) bool { return true })
+
+		slices.Sort(comp)
+		if !isValid(comp) {
+			return nil
+		}
+		return comp
+	}
+
+	// Lists every valid composition, or returns nil if there are too many.
+	allComps := func() []raidComp {
+		if binomialUpTo(len(candidates), raidSize, maxRaidCompEnumeration) > maxRaidCompEnumeration {
+			return nil
+		}
+
+		comps := []raidComp{}
+		comp := make(raidComp, raidSize)
+		for i := range comp {
+			comp[i] = i
+		}
+		for {
+			if isValid(comp) {
+				comps = append(comps, slices.Clone(comp))
+				if len(comps) > maxCompositions {
+					return nil
+				}
+			}
+
+			// Advance to the next combination in lexicographic order.
+			i := raidSize - 1
+			for i >= 0 && comp[i] == len(candidates)-raidSize+i {
+				i--
+			}
+			if i < 0 {
+				return comps
+			}
+			comp[i]++
+			for j := i + 1; j < raidSize; j++ {
+				comp[j] = comp[j-1] + 1
+			}
+		}
+	}
+
+	comps := allComps()
+	exhaustive := comps != nil
+	simsTotal := int32(maxCompositions)
+	if exhaustive {
+		if len(comps) == 0 {
+			return errorResult("No composition meets the constraints!")
+		}
+		simsTotal = int32(len(comps))
+	}
+	var simsRun int32 = 0
+
+	results := map[string]*proto.RaidComposition{}
+	// Compositions which turned out to miss a required buff or debuff. They
+	// count towards the budget, as finding out needs an environment.
+	rejected := map[string]bool{}
+	var simmed []*proto.RaidComposition
+	simComps := func(candidateComps []raidComp) *proto.ErrorOutcome {
+		var comps []raidComp
+		var raids []*proto.Raid
+		var compRaidStats []*proto.RaidStats
+		for _, comp := range candidateComps {
+			raid := buildRaid(comp)
+			_, raidStats, _ := NewEnvironment(raid, googleProto.Clone(baseRequest.Encounter).(*proto.Encounter), false)
+			if providesMask(raidStats.Buffs, raidStats.Debuffs)&requiredMask != requiredMask {
+				rejected[comp.key()] = true
+				if exhaustive {
+					simsTotal--
+				}
+				continue
+			}
+			comps = append(comps, comp)
+			raids = append(raids, raid)
+			compRaidStats = append(compRaidStats, raidStats)
+		}
+
+		compResults := make([]*proto.RaidComposition, len(comps))
+		errorOutcome := runSimBatch(len(comps), func(compIdx int) *proto.RaidSimRequest {
+			compRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+			compRequest.Raid = googleProto.Clone(raids[compIdx]).(*proto.Raid)
+			return compRequest
+		}, func(compIdx int, simResult *proto.RaidSimResult) {
+			comp := comps[compIdx]
+			raidStats := compRaidStats[compIdx]
+			compResults[compIdx] = &proto.RaidComposition{
+				Players: MapSlice(comp, func(idx int) *proto.UnitReference {
+					return &proto.UnitReference{Type: proto.UnitReference_Player, Index: candidates[idx].raidIndex}
+				}),
+				Raid:     raids[compIdx],
+				Dps:      simResult.RaidMetrics.Dps.Avg,
+				DpsStdev: simResult.RaidMetrics.Dps.Stdev,
+				Buffs:    raidStats.Buffs,
+				Debuffs:  raidStats.Debuffs,
+			}
+
+			simsRun++
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     simsTotal * baseRequest.SimOptions.Iterations,
+					CompletedIterations: simsRun * baseRequest.SimOptions.Iterations,
+					CompletedSims:       simsRun,
+					TotalSims:           simsTotal,
+				}
+			}
+		}, signals)
+		if errorOutcome != nil {
+			return errorOutcome
+		}
+
+		// In the order of comps rather than completion, so the ranking is deterministic.
+		for compIdx, comp := range comps {
+			results[comp.key()] = compResults[compIdx]
+			simmed = append(simmed, compResults[compIdx])
+		}
+		return nil
+	}
+
+	if exhaustive {
+		if errorOutcome := simComps(comps); errorOutcome != nil {
+			return &proto.RaidCompOptimizeResult{Error: errorOutcome}
+		}
+		if len(simmed) == 0 {
+			return errorResult("No composition meets the constraints!")
+		}
+	} else {
+		current := initialComp()
+		if current != nil {
+			if errorOutcome := simComps([]raidComp{current}); errorOutcome != nil {
+				return &proto.RaidCompOptimizeResult{Error: errorOutcome}
+			}
+		}
+		best, ok := results[current.key()]
+		if !ok {
+			return errorResult("Couldn't find a composition which meets the constraints!")
+		}
+
+		// Sims every swap of one raid member for a benched player, and moves to
+		// the best one, until no swap improves DPS or the budget runs out.
+		for {
+			budget := maxCompositions - int(simsRun) - len(rejected)
+			if budget <= 0 {
+				break
+			}
+
+			var swaps []raidComp
+			queued := map[string]bool{}
+			for i := range current {
+				for benchIdx := range candidates {
+					if slices.Contains(current, benchIdx) {
+						continue
+					}
+					swapped := slices.Clone(current)
+					swapped[i] = benchIdx
+					slices.Sort(swapped)
+					if _, ok := results[swapped.key()]; ok || rejected[swapped.key()] || queued[swapped.key()] || !isValid(swapped) {
+						continue
+					}
+					queued[swapped.key()] = true
+					swaps = append(swaps, swapped)
+				}
+			}
+			swaps = swaps[:min(len(swaps), budget)]
+			if len(swaps) == 0 {
+				break
+			}
+
+			if errorOutcome := simComps(swaps); errorOutcome != nil {
+				return &proto.RaidCompOptimizeResult{Error: errorOutcome}
+			}
+			improved, simmedAny := false, false
+			for _, swapped := range swaps {
+				result, ok := results[swapped.key()]
+				simmedAny = simmedAny || ok
+				if ok && result.Dps > best.Dps {
+					current, best = swapped, result
+					improved = true
+				}
+			}
+			// If every swap was rejected, the next round tries the others.
+			if simmedAny && !improved {
+				break
+			}
+		}
+	}
+
+	// Stable, so that ties keep the order in which they were simmed.
+	slices.SortStableFunc(simmed, func(a, b *proto.RaidComposition) int {
+		return cmp.Compare(b.Dps, a.Dps)
+	})
+
+	return &proto.RaidCompOptimizeResult{
+		Compositions: simmed[:min(numResults, len(simmed))],
+		SimsRun:      simsRun,
+		Exhaustive:   exhaustive,
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func raidCompTestRequest(t *testing.T) *proto.RaidCompOptimizeRequest {
+	rotation, err := APLRotationFromText(`
+		type TypeAPL
+		action cast_spell(42) if !dot_is_active(42)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse rotation: %s", err)
+	}
+
+	caster := func(name string, spellPower float64) *proto.Player {
+		return &proto.Player{
+			Name:      name,
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Rotation:  rotation,
+			BonusStats: &proto.UnitStats{
+				Stats: stats.Stats{stats.SpellPower: spellPower}.ToProtoArray(),
+			},
+		}
+	}
+
+	return &proto.RaidCompOptimizeRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			SimOptions: &proto.SimOptions{
+				Iterations: 5,
+				RandomSeed: 100,
+				IsTest:     true,
+			},
+			Raid: &proto.Raid{
+				Parties: []*proto.Party{
+					{
+						Players: []*proto.Player{
+							{
+								Name:      "Tank",
+								Class:     proto.Class_ClassWarrior,
+								Buffs:     &proto.IndividualBuffs{},
+								Spec:      &proto.Player_ProtectionWarrior{},
+								Equipment: &proto.EquipmentSpec{},
+							},
+							{
+								Name:      "Healer",
+								Class:     proto.Class_ClassShaman,
+								Buffs:     &proto.IndividualBuffs{},
+								Spec:      &proto.Player_RestorationShaman{},
+								Equipment: &proto.EquipmentSpec{},
+							},
+							caster("Weak", 0),
+						},
+						Buffs: &proto.PartyBuffs{},
+					},
+					{
+						Players: []*proto.Player{
+							caster("Strong", 5000),
+							caster("Medium", 2000),
+						},
+						Buffs: &proto.PartyBuffs{},
+					},
+				},
+				NumActiveParties: 1,
+				Tanks:            []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+			},
+			Encounter: &proto.Encounter{
+				Targets: []*proto.Target{
+					{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+				},
+				Duration: 60,
+			},
+		},
+	}
+}
+
+func TestRaidCompOptimize(t *testing.T) {
+	request := raidCompTestRequest(t)
+	request.RaidSize = 3
+	request.MinTanks = 1
+	request.MinHealers = 1
+
+	result := RunRaidCompOptimize(request)
+	if result.Error != nil {
+		t.Fatalf("Raid comp optimize failed: %s", result.Error.Message)
+	}
+
+	// Only the tank and the healer with one of the casters meet the constraints.
+	if !result.Exhaustive || result.SimsRun != 3 || len(result.Compositions) != 3 {
+		t.Fatalf("Expected 3 compositions to be simmed exhaustively, got %d (exhaustive: %t)", result.SimsRun, result.Exhaustive)
+	}
+	for i, comp := range result.Compositions {
+		if comp.Players[0].Index != 0 || comp.Players[1].Index != 1 {
+			t.Errorf("Expected composition %d to include the tank and the healer, got %v", i, comp.Players)
+		}
+		if len(comp.Raid.Tanks) != 1 || comp.Raid.Tanks[0].Index != 0 {
+			t.Errorf("Expected composition %d to keep the tank, got %v", i, comp.Raid.Tanks)
+		}
+		if i > 0 && comp.Dps > result.Compositions[i-1].Dps {
+			t.Errorf("Compositions are not ranked by DPS")
+		}
+	}
+
+	// Indices into the base raid, where the strong caster is the first player in the second party.
+	if best := result.Compositions[0]; best.Players[2].Index != 5 {
+		t.Errorf("Expected the strong caster in the best composition, got %v", best.Players)
+	}
+}
+
+func TestRaidCompOptimizeSwapSearch(t *testing.T) {
+	request := raidCompTestRequest(t)
+	request.RaidSize = 2
+	request.MaxCompositions = 4
+
+	result := RunRaidCompOptimize(request)
+	if result.Error != nil {
+		t.Fatalf("Raid comp optimize failed: %s", result.Error.Message)
+	}
+
+	if result.Exhaustive || result.SimsRun > 4 {
+		t.Fatalf("Expected at most 4 compositions from the swap search, got %d (exhaustive: %t)", result.SimsRun, result.Exhaustive)
+	}
+	if best := result.Compositions[0]; best.Players[0].Index != 5 && best.Players[1].Index != 5 {
+		t.Errorf("Expected the strong caster in the best composition, got %v", best.Players)
+	}
+}
+
+func TestRaidCompOptimizeUnmetConstraints(t *testing.T) {
+	request := raidCompTestRequest(t)
+	request.RaidSize = 3
+	request.RequiredDebuffs = &proto.Debuffs{WeakenedArmor: true}
+
+	result := RunRaidCompOptimize(request)
+	if result.Error == nil || result.Error.Message != "No composition meets the constraints!" {
+		t.Fatalf("Expected an error for unmet constraints, got %v", result.Error)
+	}
+}
+
+func TestRaidCompOptimizeInvalidSettings(t *testing.T) {
+	request := raidCompTestRequest(t)
+	request.RaidSize = 3
+	request.BaseSettings.Raid.Parties[1].Players[0].Equipment = &proto.EquipmentSpec{Items: []*proto.ItemSpec{{Id: 1}}}
+
+	if result := RunRaidCompOptimize(request); result.Error == nil {
+		t.Fatalf("Expected an error with invalid settings")
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// Max health given to target dummies when a damage profile doesn't specify one.
+const DefaultTargetDummyHealth = 500_000.0
+
+// Gives a target dummy a real health pool, so damage from the raid damage
+// profile creates deficits for healers to fill and overhealing can be measured.
+func (td *TargetDummy) enableDamageTaken(profile *proto.RaidDamageProfile) {
+	maxHealth := profile.TargetDummyHealth
+	if maxHealth <= 0 {
+		maxHealth = DefaultTargetDummyHealth
+	}
+
+	td.baseStats[stats.Health] = maxHealth
+	td.AddStats(td.baseStats)
+	td.EnableHealthBar()
+	td.trackHealth(nil, false)
+}
+
+// Points every encounter target which wasn't assigned a tank at the first
+// target dummy, so that its auto attacks act as a tank damage stream.
+func (env *Environment) setupDummyTankTargets(tankTargetSet map[*Unit]bool) {
+	dummies := env.Raid.GetTargetDummies()
+	if len(dummies) == 0 {
+		return
+	}
+
+	dummyTank := &dummies[0].Unit
+	for _, target := range env.Encounter.AllTargets {
+		if target.CurrentTarget != nil {
+			continue
+		}
+
+		target.CurrentTarget = dummyTank
+		if !tankTargetSet[dummyTank] {
+			dummyTank.CurrentTarget = &target.Unit
+			tankTargetSet[dummyTank] = true
+		}
+	}
+}
+
+// Registers the raid-wide pulses and single-target spikes described by the
+// damage profile. These are cast by the first encounter target, so they show
+// up in its metrics and in each raid member's damage taken.
+func (env *Environment) registerRaidDamageProfile(profile *proto.RaidDamageProfile) {
+	if profile == nil || len(env.Encounter.AllTargets) == 0 || len(env.Raid.AllPlayerUnits) == 0 {
+		return
+	}
+
+	caster := &env.Encounter.AllTargets[0].Unit
+	raidUnits := env.Raid.AllPlayerUnits
+	variance := max(0, min(1, profile.DamageVariance))
+
+	rollDamage := func(sim *Simulation, baseDamage float64, label string) float64 {
+		if variance == 0 {
+			return baseDamage
+		}
+		return baseDamage * sim.RollWithLabel(1-variance, 1+variance, label)
+	}
+
+	var pulse *Spell
+	if profile.PulseDamage > 0 && profile.PulseInterval > 0 {
+		pulse = caster.RegisterSpell(SpellConfig{
+			ActionID:         ActionID{OtherID: proto.OtherAction_OtherActionDamageTaken, Tag: 1},
+			SpellSchool:      SpellSchoolFromProto(profile.PulseSchool),
+			ProcMask:         ProcMaskSpellDamage,
+			Flags:            SpellFlagIgnoreAttackerModifiers,
+			DamageMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, _ *Unit, spell *Spell) {
+				for _, unit := range raidUnits {
+					spell.CalcAndDealDamage(sim, unit, rollDamage(sim, profile.PulseDamage, "Raid Pulse Damage"), spell.OutcomeAlwaysHit)
+				}
+			},
+		})
+	}
+
+	var spike *Spell
+	if profile.SpikeDamage > 0 && profile.SpikeInterval > 0 {
+		spike = caster.RegisterSpell(SpellConfig{
+			ActionID:         ActionID{OtherID: proto.OtherAction_OtherActionDamageTaken, Tag: 2},
+			SpellSchool:      SpellSchoolFromProto(profile.SpikeSchool),
+			ProcMask:         ProcMaskSpellDamage,
+			Flags:            SpellFlagIgnoreAttackerModifiers,
+			DamageMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.CalcAndDealDamage(sim, target, rollDamage(sim, profile.SpikeDamage, "Raid Spike Damage"), spell.OutcomeAlwaysHit)
+			},
+		})
+	}
+
+	if pulse == nil && spike == nil {
+		return
+	}
+
+	MakePermanent(caster.RegisterAura(Aura{
+		Label: "Raid Damage Profile",
+	})).ApplyOnEncounterStart(func(aura *Aura, sim *Simulation) {
+		if pulse != nil {
+			StartPeriodicAction(sim, PeriodicActionOptions{
+				Period: DurationFromSeconds(profile.PulseInterval),
+				OnAction: func(sim *Simulation) {
+					pulse.Cast(sim, raidUnits[0])
+				},
+			})
+		}
+
+		if spike != nil {
+			StartPeriodicAction(sim, PeriodicActionOptions{
+				Period: DurationFromSeconds(profile.SpikeInterval),
+				OnAction: func(sim *Simulation) {
+					idx := int(sim.RandomFloat("Raid Spike Target") * float64(len(raidUnits)))
+					spike.Cast(sim, raidUnits[min(idx, len(raidUnits)-1)])
+				},
+			})
+		}
+	})
+}
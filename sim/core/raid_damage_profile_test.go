@@ -0,0 +1,118 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func init() {
+	registerFakeAgent(proto.Player_RestorationShaman{}, proto.Spec_SpecRestorationShaman, func(fa *FakeAgent) {
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID:         ActionID{SpellID: 43},
+			SpellSchool:      SpellSchoolNature,
+			ProcMask:         ProcMaskSpellHealing,
+			Flags:            SpellFlagHelpful,
+			DamageMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.CalcAndDealHealing(sim, target, 1500, spell.OutcomeHealing)
+			},
+		})
+	})
+}
+
+func setupDamageProfileSim(profile *proto.RaidDamageProfile) *Simulation {
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			RandomSeed: 100,
+		},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Healer",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_RestorationShaman{},
+							Equipment: &proto.EquipmentSpec{},
+							Rotation:  &proto.APLRotation{},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+			TargetDummies: 2,
+			DamageProfile: profile,
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 180,
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	return sim
+}
+
+func TestRaidDamageProfileDummyHealth(t *testing.T) {
+	sim := setupDamageProfileSim(&proto.RaidDamageProfile{
+		TargetDummyHealth: 20000,
+	})
+
+	for _, dummy := range sim.Raid.GetTargetDummies() {
+		if !dummy.HasHealthBar() {
+			t.Fatalf("Expected %s to have a health bar", dummy.Label)
+		}
+		if dummy.CurrentHealth() != 20000 {
+			t.Fatalf("Expected %s to start at 20000 health, got %0.0f", dummy.Label, dummy.CurrentHealth())
+		}
+	}
+}
+
+func TestRaidDamageProfileTankMelee(t *testing.T) {
+	sim := setupDamageProfileSim(&proto.RaidDamageProfile{
+		TankMeleeOnDummies: true,
+	})
+
+	dummyTank := &sim.Raid.GetTargetDummies()[0].Unit
+	if sim.Encounter.AllTargets[0].CurrentTarget != dummyTank {
+		t.Fatalf("Expected untanked target to attack %s", dummyTank.Label)
+	}
+	if !dummyTank.Metrics.IsTanking() {
+		t.Fatalf("Expected %s to be tanking", dummyTank.Label)
+	}
+}
+
+func TestRaidDamageProfileOverhealing(t *testing.T) {
+	sim := setupDamageProfileSim(&proto.RaidDamageProfile{
+		PulseDamage:   1000,
+		PulseInterval: 2,
+	})
+	healer := sim.Raid.Parties[0].Players[0].(*FakeAgent)
+	dummy := &sim.Raid.GetTargetDummies()[0].Unit
+
+	pulse := sim.Encounter.AllTargets[0].GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionDamageTaken, Tag: 1})
+	if pulse == nil {
+		t.Fatalf("Expected raid pulse spell to be registered")
+	}
+	pulse.Cast(sim, dummy)
+
+	if missing := dummy.MaxHealth() - dummy.CurrentHealth(); !WithinToleranceFloat64(1000, missing, 0.01) {
+		t.Fatalf("Expected raid pulse to remove 1000 health, got %0.3f", missing)
+	}
+
+	healer.Spell.Cast(sim, dummy)
+
+	metrics := healer.Spell.SpellMetrics[dummy.UnitIndex]
+	if !WithinToleranceFloat64(1500, metrics.TotalHealing, 0.01) {
+		t.Fatalf("Expected 1500 healing, got %0.3f", metrics.TotalHealing)
+	}
+	if !WithinToleranceFloat64(500, metrics.TotalOverhealing, 0.01) {
+		t.Fatalf("Expected 500 overhealing, got %0.3f", metrics.TotalOverhealing)
+	}
+}
@@ -0,0 +1,441 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+type reforgeCap struct {
+	stats         []stats.Stat
+	cap           float64
+	postCapWeight float64
+
+	// Value of the capped stats from sources other than gear.
+	offset float64
+}
+
+func (rc *reforgeCap) ToProto() *proto.ReforgeStatCap {
+	return &proto.ReforgeStatCap{
+		Stats:         MapSlice(rc.stats, func(stat stats.Stat) proto.Stat { return proto.Stat(stat) }),
+		Cap:           rc.cap,
+		PostCapWeight: rc.postCapWeight,
+	}
+}
+
+type reforgeSlot struct {
+	slot proto.ItemSlot
+	item Item
+
+	// Index 0 is always 'no reforge'.
+	reforges      []*ReforgeStat
+	reforgeChoice int
+
+	// Candidate gems for each socket. Empty when gems are not being optimized.
+	gemOptions [][]Gem
+	gemChoices []int
+}
+
+func (rs *reforgeSlot) numVariables() int {
+	return 1 + len(rs.gemOptions)
+}
+
+func (rs *reforgeSlot) numOptions(variable int) int {
+	if variable == 0 {
+		return len(rs.reforges)
+	}
+	return len(rs.gemOptions[variable-1])
+}
+
+func (rs *reforgeSlot) getChoice(variable int) int {
+	if variable == 0 {
+		return rs.reforgeChoice
+	}
+	return rs.gemChoices[variable-1]
+}
+
+func (rs *reforgeSlot) setChoice(variable int, choice int) {
+	if variable == 0 {
+		rs.reforgeChoice = choice
+	} else {
+		rs.gemChoices[variable-1] = choice
+	}
+}
+
+func (rs *reforgeSlot) currentItem() Item {
+	item := rs.item
+	item.Reforging = rs.reforges[rs.reforgeChoice]
+	if len(rs.gemOptions) > 0 {
+		item.Gems = make([]Gem, len(rs.gemOptions))
+		for socketIdx, options := range rs.gemOptions {
+			item.Gems[socketIdx] = options[rs.gemChoices[socketIdx]]
+		}
+	}
+	return item
+}
+
+func (rs *reforgeSlot) currentStats() stats.Stats {
+	item := rs.currentItem()
+	return ItemEquipmentBaseStats(item).Add(ItemEquipmentGemAndEnchantStats(item))
+}
+
+// Finds the reforges (and optionally gems) which maximize the weighted value
+// of a gear set, subject to stat caps.
+type reforgeOptimizer struct {
+	weights stats.Stats
+	caps    []*reforgeCap
+	capped  [stats.SimStatsLen]bool
+	slots   []*reforgeSlot
+
+	slotStats []stats.Stats
+}
+
+func newReforgeOptimizer(weights stats.Stats, caps []*reforgeCap, slots []*reforgeSlot) *reforgeOptimizer {
+	ro := &reforgeOptimizer{
+		weights:   weights,
+		caps:      caps,
+		slots:     slots,
+		slotStats: make([]stats.Stats, len(slots)),
+	}
+	for _, rc := range caps {
+		for _, stat := range rc.stats {
+			ro.capped[stat] = true
+		}
+	}
+	for i, rs := range slots {
+		ro.slotStats[i] = rs.currentStats()
+	}
+	return ro
+}
+
+func (ro *reforgeOptimizer) gearStats() stats.Stats {
+	var gearStats stats.Stats
+	for i := range ro.slotStats {
+		gearStats.AddInplace(&ro.slotStats[i])
+	}
+	return gearStats
+}
+
+func (ro *reforgeOptimizer) score(gearStats stats.Stats) float64 {
+	score := 0.0
+	for stat, value := range gearStats {
+		if !ro.capped[stat] {
+			score += ro.weights[stat] * value
+		}
+	}
+
+	for _, rc := range ro.caps {
+		total := rc.offset
+		for _, stat := range rc.stats {
+			total += gearStats[stat]
+		}
+		score += ro.weights[rc.stats[0]]*min(total, rc.cap) + rc.postCapWeight*max(0, total-rc.cap)
+	}
+	return score
+}
+
+// Sets the value of a variable and returns the new score.
+func (ro *reforgeOptimizer) try(gearStats *stats.Stats, slotIdx int, variable int, choice int) float64 {
+	rs := ro.slots[slotIdx]
+	rs.setChoice(variable, choice)
+	newSlotStats := rs.currentStats()
+
+	gearStats.AddInplace(&newSlotStats)
+	*gearStats = gearStats.Subtract(ro.slotStats[slotIdx])
+	ro.slotStats[slotIdx] = newSlotStats
+	return ro.score(*gearStats)
+}
+
+type reforgeVariable struct {
+	slotIdx  int
+	variable int
+}
+
+// Hill climbs using single and pairwise variable changes, starting from the
+// current configuration. The result is never worse than the starting point.
+func (ro *reforgeOptimizer) optimize() float64 {
+	var variables []reforgeVariable
+	for slotIdx, rs := range ro.slots {
+		for variable := range rs.numVariables() {
+			if rs.numOptions(variable) > 1 {
+				variables = append(variables, reforgeVariable{slotIdx, variable})
+			}
+		}
+	}
+
+	const epsilon = 1e-9
+	gearStats := ro.gearStats()
+	bestScore := ro.score(gearStats)
+
+	for improved := true; improved; {
+		improved = false
+
+		for _, v := range variables {
+			rs := ro.slots[v.slotIdx]
+			bestChoice := rs.getChoice(v.variable)
+			for choice := range rs.numOptions(v.variable) {
+				if score := ro.try(&gearStats, v.slotIdx, v.variable, choice); score > bestScore+epsilon {
+					bestScore = score
+					bestChoice = choice
+					improved = true
+				}
+			}
+			ro.try(&gearStats, v.slotIdx, v.variable, bestChoice)
+		}
+
+		if improved {
+			continue
+		}
+
+		// Moves which trade a capped stat between two items are only found by changing both at once.
+		for i, v1 := range variables {
+			rs1 := ro.slots[v1.slotIdx]
+			for _, v2 := range variables[i+1:] {
+				if v1.slotIdx == v2.slotIdx {
+					continue
+				}
+				rs2 := ro.slots[v2.slotIdx]
+				best1, best2 := rs1.getChoice(v1.variable), rs2.getChoice(v2.variable)
+				for choice1 := range rs1.numOptions(v1.variable) {
+					ro.try(&gearStats, v1.slotIdx, v1.variable, choice1)
+					for choice2 := range rs2.numOptions(v2.variable) {
+						if score := ro.try(&gearStats, v2.slotIdx, v2.variable, choice2); score > bestScore+epsilon {
+							bestScore = score
+							best1, best2 = choice1, choice2
+							improved = true
+						}
+					}
+				}
+				ro.try(&gearStats, v1.slotIdx, v1.variable, best1)
+				ro.try(&gearStats, v2.slotIdx, v2.variable, best2)
+			}
+		}
+	}
+
+	return bestScore
+}
+
+func reforgeOptionsForItem(item *Item) []*ReforgeStat {
+	ids := make([]int32, 0, len(ReforgeStatsByID))
+	for id := range ReforgeStatsByID {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	options := []*ReforgeStat{nil}
+	for _, id := range ids {
+		reforge := ReforgeStatsByID[id]
+		if validateReforging(item, reforge) {
+			options = append(options, &reforge)
+		}
+	}
+	return options
+}
+
+func gemFitsSocket(gem Gem, socketColor proto.GemColor) bool {
+	switch socketColor {
+	case proto.GemColor_GemColorMeta, proto.GemColor_GemColorCogwheel, proto.GemColor_GemColorShaTouched:
+		return gem.Color == socketColor
+	}
+	return gem.Color != proto.GemColor_GemColorMeta && gem.Color != proto.GemColor_GemColorCogwheel && gem.Color != proto.GemColor_GemColorShaTouched
+}
+
+func gemOptionsForItem(item *Item, candidateGems []Gem) [][]Gem {
+	gemOptions := make([][]Gem, len(item.Gems))
+	for socketIdx, currentGem := range item.Gems {
+		// Extra sockets from belt buckles and professions are prismatic.
+		socketColor := proto.GemColor_GemColorPrismatic
+		if socketIdx < len(item.GemSockets) {
+			socketColor = item.GemSockets[socketIdx]
+		}
+
+		gemOptions[socketIdx] = []Gem{currentGem}
+		for _, gem := range candidateGems {
+			if gem.ID != currentGem.ID && gemFitsSocket(gem, socketColor) {
+				gemOptions[socketIdx] = append(gemOptions[socketIdx], gem)
+			}
+		}
+	}
+	return gemOptions
+}
+
+// Derives hit and expertise caps against the given target, using the same
+// attack table values and rating conversions as the sim itself.
+func defaultReforgeStatCaps(character *Character, target *Unit, weights stats.Stats, finalStats stats.Stats) []*reforgeCap {
+	if weights[stats.HitRating] <= 0 && weights[stats.ExpertiseRating] <= 0 {
+		return nil
+	}
+
+	attackTable := NewAttackTable(&character.Unit, target)
+
+	if weights[stats.SpellPower] > weights[stats.AttackPower] {
+		// Expertise also counts as spell hit.
+		casterCap := &reforgeCap{
+			stats:  []stats.Stat{stats.HitRating, stats.ExpertiseRating},
+			cap:    attackTable.BaseSpellMissChance * 100 * SpellHitRatingPerHitPercent,
+			offset: finalStats[stats.SpellHitPercent] * SpellHitRatingPerHitPercent,
+		}
+		if SpiritIsHitRating(character.Spec) {
+			casterCap.stats = append(casterCap.stats, stats.Spirit)
+		}
+		return []*reforgeCap{casterCap}
+	}
+
+	expertiseChance := attackTable.BaseDodgeChance
+	if character.PseudoStats.InFrontOfTarget {
+		expertiseChance += attackTable.BaseParryChance
+	}
+
+	return []*reforgeCap{
+		{
+			stats:  []stats.Stat{stats.HitRating},
+			cap:    attackTable.BaseMissChance * 100 * PhysicalHitRatingPerHitPercent,
+			offset: finalStats[stats.PhysicalHitPercent] * PhysicalHitRatingPerHitPercent,
+		},
+		{
+			stats:  []stats.Stat{stats.ExpertiseRating},
+			cap:    expertiseChance * 400 * ExpertisePerQuarterPercentReduction,
+			offset: finalStats[stats.ExpertiseRating],
+		},
+	}
+}
+
+func optimizeReforges(request *proto.ReforgeOptimizeRequest) (result *proto.ReforgeOptimizeResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.ReforgeOptimizeResult{Error: ErrorOutcomeFromPanic(err)}
+		}
+	}()
+
+	if request.Player == nil || request.Player.Equipment == nil {
+		return &proto.ReforgeOptimizeResult{Error: &proto.ErrorOutcome{Message: "Reforge request is missing a player!"}}
+	}
+	if request.StatWeights == nil {
+		return &proto.ReforgeOptimizeResult{Error: &proto.ErrorOutcome{Message: "Reforge request is missing stat weights!"}}
+	}
+
+	encounter := request.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+
+	// Stats are measured without any reforges, so that gear stats can be swapped in and out linearly.
+	player := googleProto.Clone(request.Player).(*proto.Player)
+	initialReforges := make([]int32, len(player.Equipment.Items))
+	for i, itemSpec := range player.Equipment.Items {
+		initialReforges[i] = itemSpec.Reforging
+		itemSpec.Reforging = 0
+	}
+
+	env, raidStats, _ := NewEnvironment(SinglePlayerRaidProto(player, request.PartyBuffs, request.RaidBuffs, request.Debuffs), encounter, true)
+	character := env.Raid.Parties[0].Players[0].GetCharacter()
+	finalStats := stats.FromUnitStatsProto(raidStats.Parties[0].Players[0].FinalStats)
+
+	weights := stats.FromUnitStatsProto(request.StatWeights)
+
+	var candidateGems []Gem
+	if request.OptimizeGems {
+		for _, gemID := range request.GemOptions {
+			gem, ok := GemsByID[gemID]
+			if !ok {
+				return &proto.ReforgeOptimizeResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf("No gem with id: %d", gemID)}}
+			}
+			candidateGems = append(candidateGems, gem)
+		}
+	}
+
+	var slots []*reforgeSlot
+	for slot := range NumItemSlots {
+		item := character.Equipment[slot]
+		if item.ID == 0 {
+			continue
+		}
+
+		rs := &reforgeSlot{
+			slot:     slot,
+			item:     item,
+			reforges: reforgeOptionsForItem(&item),
+		}
+		for choice, reforge := range rs.reforges {
+			if reforge != nil && int(slot) < len(initialReforges) && reforge.ID == initialReforges[slot] {
+				rs.reforgeChoice = choice
+			}
+		}
+		if len(candidateGems) > 0 {
+			rs.gemOptions = gemOptionsForItem(&item, candidateGems)
+			rs.gemChoices = make([]int, len(rs.gemOptions))
+		}
+		slots = append(slots, rs)
+	}
+
+	var caps []*reforgeCap
+	if len(request.StatCaps) > 0 {
+		for _, capProto := range request.StatCaps {
+			if len(capProto.Stats) == 0 {
+				return &proto.ReforgeOptimizeResult{Error: &proto.ErrorOutcome{Message: "Stat cap has no stats!"}}
+			}
+			rc := &reforgeCap{
+				stats:         stats.ProtoArrayToStatsList(capProto.Stats),
+				cap:           capProto.Cap,
+				postCapWeight: capProto.PostCapWeight,
+			}
+			for _, stat := range rc.stats {
+				rc.offset += finalStats[stat]
+			}
+			caps = append(caps, rc)
+		}
+	} else {
+		caps = defaultReforgeStatCaps(character, env.Encounter.ActiveTargetUnits[0], weights, finalStats)
+	}
+
+	// Cap offsets were measured with the unreforged gear, so remove that gear's contribution.
+	unreforgedGearStats := stats.Stats{}
+	for _, rs := range slots {
+		item := rs.item
+		unreforgedGearStats = unreforgedGearStats.Add(ItemEquipmentBaseStats(item).Add(ItemEquipmentGemAndEnchantStats(item)))
+	}
+	for _, rc := range caps {
+		for _, stat := range rc.stats {
+			rc.offset -= unreforgedGearStats[stat]
+		}
+	}
+
+	optimizer := newReforgeOptimizer(weights, caps, slots)
+	initialScore := optimizer.score(optimizer.gearStats())
+	score := optimizer.optimize()
+
+	result = &proto.ReforgeOptimizeResult{
+		Equipment:    googleProto.Clone(request.Player.Equipment).(*proto.EquipmentSpec),
+		StatCaps:     MapSlice(caps, (*reforgeCap).ToProto),
+		InitialScore: initialScore,
+		Score:        score,
+	}
+
+	for _, rs := range slots {
+		item := rs.currentItem()
+		itemSpec := result.Equipment.Items[rs.slot]
+		if len(rs.gemOptions) > 0 {
+			itemSpec.Gems = MapSlice(item.Gems, func(gem Gem) int32 { return gem.ID })
+		}
+
+		assignment := &proto.ReforgeAssignment{
+			Slot:   rs.slot,
+			ItemId: item.ID,
+		}
+		itemSpec.Reforging = 0
+		if item.Reforging != nil {
+			itemSpec.Reforging = item.Reforging.ID
+			assignment.Reforging = item.Reforging.ID
+			assignment.FromStat = item.Reforging.FromStat
+			assignment.ToStat = item.Reforging.ToStat
+			assignment.Amount = math.Abs(ItemEquipmentBaseStats(item)[item.Reforging.ToStat] - ItemEquipmentBaseStats(rs.item)[item.Reforging.ToStat])
+		}
+		result.Reforges = append(result.Reforges, assignment)
+	}
+
+	return result
+}
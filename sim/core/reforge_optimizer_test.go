@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestReforgeOptimizerRespectsCaps(t *testing.T) {
+	addTestReforges(t,
+		ReforgeStat{ID: 9000101, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHitRating, Multiplier: 0.4},
+		ReforgeStat{ID: 9000102, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHasteRating, Multiplier: 0.4},
+	)
+
+	newSlot := func(slot proto.ItemSlot, crit float64) *reforgeSlot {
+		item := Item{ID: 9000100 + int32(slot), Stats: stats.Stats{stats.CritRating: crit}}
+		return &reforgeSlot{slot: slot, item: item, reforges: reforgeOptionsForItem(&item)}
+	}
+	slots := []*reforgeSlot{
+		newSlot(proto.ItemSlot_ItemSlotChest, 400),
+		newSlot(proto.ItemSlot_ItemSlotLegs, 200),
+	}
+
+	weights := stats.Stats{stats.HitRating: 2, stats.CritRating: 1, stats.HasteRating: 1.5}
+	caps := []*reforgeCap{{stats: []stats.Stat{stats.HitRating}, cap: 100}}
+
+	optimizer := newReforgeOptimizer(weights, caps, slots)
+	if score := optimizer.optimize(); score != 760 {
+		t.Fatalf("Expected score of 760, got %0.1f", score)
+	}
+
+	// The smaller item should take hit, so that the larger one isn't wasted over the cap.
+	if reforge := slots[0].currentItem().Reforging; reforge == nil || reforge.ToStat != proto.Stat_StatHasteRating {
+		t.Fatalf("Expected chest to be reforged to haste, got %v", reforge)
+	}
+	if reforge := slots[1].currentItem().Reforging; reforge == nil || reforge.ToStat != proto.Stat_StatHitRating {
+		t.Fatalf("Expected legs to be reforged to hit, got %v", reforge)
+	}
+}
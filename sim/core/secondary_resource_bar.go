@@ -66,6 +66,9 @@ func (bar *DefaultSecondaryResourceBarImpl) Gain(sim *Simulation, amount int32,
 			bar.config.Max,
 		)
 	}
+	if sim.CombatLog != nil {
+		bar.unit.logResourceEvent(sim, metrics, float64(amountGained), float64(bar.value), false)
+	}
 
 	bar.invokeOnGain(sim, amount, amountGained, action)
 }
@@ -111,6 +114,9 @@ func (bar *DefaultSecondaryResourceBarImpl) Spend(sim *Simulation, amount int32,
 			bar.config.Max,
 		)
 	}
+	if sim.CombatLog != nil {
+		bar.unit.logResourceEvent(sim, metrics, float64(amount), float64(bar.value-amount), true)
+	}
 
 	metrics.AddEvent(float64(-amount), float64(-amount))
 	bar.invokeOnSpend(sim, amount, action)
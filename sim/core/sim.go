@@ -6,7 +6,6 @@ import (
 	"math"
 	"math/rand"
 	"runtime"
-	"runtime/debug"
 	"slices"
 	"strconv"
 	"strings"
@@ -40,12 +39,17 @@ type Simulation struct {
 	CurrentTime       time.Duration // duration that has elapsed in the sim since starting
 	Duration          time.Duration // Duration of current iteration
 	NeedsInput        bool          // Sim is in interactive mode and needs input
+	InteractiveUnit   *Unit         // Unit whose actions are chosen externally in interactive mode, or nil for all players
 
 	ProgressReport func(*proto.ProgressMetrics)
 	Signals        simsignals.Signals
 
 	Log func(string, ...interface{})
 
+	// Receives structured combat log events, when enabled via SimOptions.CombatLogMode.
+	// Use Unit.LogEvent rather than calling this directly.
+	CombatLog func(*proto.CombatLogEvent)
+
 	executePhase int32 // 20, 25, 35, 45 or 90 for the respective execute range, 100 otherwise
 
 	executePhaseCallbacks []func(*Simulation, int32) // 2nd parameter is 90 for 90%, 45 for 45%, 35 for 35%, 25 for 25% and 20 for 20%
@@ -119,17 +123,8 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 	if !rsr.SimOptions.IsTest {
 		defer func() {
 			if err := recover(); err != nil {
-				errStr := ""
-				switch errt := err.(type) {
-				case string:
-					errStr = errt
-				case error:
-					errStr = errt.Error()
-				}
-
-				errStr += "\nStack Trace:\n" + string(debug.Stack())
 				result = &proto.RaidSimResult{
-					Error: &proto.ErrorOutcome{Message: errStr},
+					Error: ErrorOutcomeFromPanic(err),
 				}
 				if progress != nil {
 					progress <- &proto.ProgressMetrics{
@@ -198,6 +193,10 @@ func newSimWithEnv(env *Environment, simOptions *proto.SimOptions, signals simsi
 		rseed = time.Now().UnixNano()
 	}
 
+	if simOptions.ProfileApl {
+		env.Raid.enableAPLProfiling()
+	}
+
 	return &Simulation{
 		Environment: env,
 		Options:     simOptions,
@@ -293,15 +292,42 @@ func (sim *Simulation) Reseed(seed int64) {
 	sim.reseedRands(seed)
 }
 
+// Upper bound on iterations for adaptive sims which don't set max_iterations.
+const DefaultMaxIterations = 100000
+
+// Returns the most iterations a sim with these options will run. For adaptive
+// sims (target_stdev_of_mean set) the iterations option is only a minimum.
+func MaxIterations(options *proto.SimOptions) int32 {
+	if options.TargetStdevOfMean <= 0 {
+		return options.Iterations
+	}
+	if options.MaxIterations <= 0 {
+		return max(options.Iterations, DefaultMaxIterations)
+	}
+	return max(options.Iterations, options.MaxIterations)
+}
+
 // Run runs the simulation for the configured number of iterations, and
-// collects all the metrics together.
+// collects all the metrics together. Adaptive sims stop early once the raid
+// DPS has converged to the requested precision.
 func (sim *Simulation) run() *proto.RaidSimResult {
 	t0 := time.Now()
 
 	logsBuffer := &strings.Builder{}
+	var combatLog []*proto.CombatLogEvent
+	iteration := int32(0)
 	if sim.Options.Debug || sim.Options.DebugFirstIteration {
-		sim.Log = func(message string, vals ...interface{}) {
-			logsBuffer.WriteString(fmt.Sprintf("[%0.2f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
+		if sim.Options.CombatLogMode != proto.CombatLogMode_CombatLogEvents {
+			sim.Log = func(message string, vals ...interface{}) {
+				logsBuffer.WriteString(fmt.Sprintf("[%0.2f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
+			}
+		}
+		if sim.Options.CombatLogMode != proto.CombatLogMode_CombatLogText {
+			sim.CombatLog = func(event *proto.CombatLogEvent) {
+				event.Iteration = iteration
+				event.Timestamp = sim.CurrentTime.Seconds()
+				combatLog = append(combatLog, event)
+			}
 		}
 	}
 
@@ -320,10 +346,14 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 	if !sim.Options.Debug {
 		sim.Log = nil
+		sim.CombatLog = nil
 	}
 
+	maxIterations := MaxIterations(sim.Options)
+	iterationsDone := int32(1)
+
 	var st time.Time
-	for i := int32(1); i < sim.Options.Iterations; i++ {
+	for i := int32(1); i < maxIterations; i++ {
 		if sim.Signals.Abort.IsTriggered() {
 			quitResult := &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}
 			if sim.ProgressReport != nil {
@@ -335,7 +365,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		// fmt.Printf("Iteration: %d\n", i)
 		if sim.ProgressReport != nil && time.Since(st) > time.Millisecond*100 {
 			metrics := sim.Raid.GetMetrics()
-			sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: i, Dps: metrics.Dps.Avg, Hps: metrics.Hps.Avg})
+			sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: maxIterations, CompletedIterations: i, Dps: metrics.Dps.Avg, Hps: metrics.Hps.Avg})
 			if IsRunningInWasm() {
 				time.Sleep(time.Microsecond) // Need to sleep to escape the go scheduler in wasm to give the JS event loop a chance to process requests to the worker.
 			} else {
@@ -346,6 +376,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 		// Before each iteration, reset state to seed+iterations
 		sim.reseedRands(int64(i))
+		iteration = i
 
 		sim.runOnce()
 		iterDuration := sim.Duration
@@ -353,6 +384,11 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 			iterDuration = sim.CurrentTime
 		}
 		totalDuration += iterDuration
+		iterationsDone = i + 1
+
+		if sim.Options.TargetStdevOfMean > 0 && iterationsDone >= sim.Options.Iterations && sim.Raid.dpsMetrics.stdevOfMean() <= sim.Options.TargetStdevOfMean {
+			break
+		}
 	}
 	result := &proto.RaidSimResult{
 		RaidMetrics:      sim.Raid.GetMetrics(),
@@ -360,16 +396,24 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 		Logs:                   logsBuffer.String(),
 		FirstIterationDuration: firstIterationDuration.Seconds(),
-		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
-		IterationsDone:         sim.Options.Iterations,
+		AvgIterationDuration:   totalDuration.Seconds() / float64(iterationsDone),
+		IterationsDone:         iterationsDone,
+		RaidDpsStdevOfMean:     sim.Raid.dpsMetrics.stdevOfMean(),
+	}
+
+	if combatLog != nil {
+		result.CombatLog = combatLog
+		for _, unit := range sim.Environment.AllUnits {
+			result.CombatLogUnits = append(result.CombatLogUnits, &proto.CombatLogUnit{UnitIndex: unit.UnitIndex, Label: unit.Label})
+		}
 	}
 
 	// Final progress report
 	if sim.ProgressReport != nil {
-		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: sim.Options.Iterations, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
+		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: iterationsDone, CompletedIterations: iterationsDone, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
 	}
 
-	if d := sim.Options.Iterations; d > 3000 {
+	if d := iterationsDone; d > 3000 {
 		log.Printf("running %d iterations took %s", d, time.Since(t0))
 	}
 
@@ -551,6 +595,15 @@ func (sim *Simulation) Step() bool {
 		return false
 	}
 
+	if sim.CombatLog != nil && sim.Options.CombatLogPendingActions {
+		sim.CombatLog(&proto.CombatLogEvent{
+			Type:            proto.CombatLogEventType_CombatLogEventPendingAction,
+			UnitIndex:       -1,
+			TargetUnitIndex: -1,
+			Priority:        int32(pa.Priority),
+		})
+	}
+
 	pa.OnAction(sim)
 	pa.dispose(sim)
 	return false
@@ -695,6 +748,11 @@ func (sim *Simulation) IsExecutePhase90() bool {
 	return sim.executePhase > 90
 }
 
+// Returns whether the unit's actions are chosen externally instead of by its rotation.
+func (sim *Simulation) IsInteractive(unit *Unit) bool {
+	return sim.Options.Interactive && (sim.InteractiveUnit == nil || sim.InteractiveUnit == unit)
+}
+
 func (sim *Simulation) GetRemainingDuration() time.Duration {
 	if sim.Encounter.EndFightAtHealth > 0 {
 		if !sim.Encounter.DurationIsEstimate || sim.CurrentTime < time.Second*5 {
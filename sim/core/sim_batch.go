@@ -0,0 +1,61 @@
+package core
+
+import (
+	"runtime"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+type batchSimResult struct {
+	simIdx int
+	result *proto.RaidSimResult
+}
+
+// Runs numSims independent sims on a pool of worker goroutines, for APIs which
+// sim many variations of one request. buildRequest is called from the workers,
+// and onResult from the calling goroutine as each sim completes. The first
+// failed sim aborts the rest, and its error is returned.
+func runSimBatch(numSims int, buildRequest func(simIdx int) *proto.RaidSimRequest, onResult func(simIdx int, result *proto.RaidSimResult), signals simsignals.Signals) *proto.ErrorOutcome {
+	numWorkers := runtime.NumCPU()
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() {
+		numWorkers = 1
+	}
+
+	jobs := make(chan int, numSims)
+	results := make(chan batchSimResult, numSims)
+	for simIdx := range numSims {
+		jobs <- simIdx
+	}
+	close(jobs)
+
+	for range min(numWorkers, numSims) {
+		go func() {
+			for simIdx := range jobs {
+				if signals.Abort.IsTriggered() {
+					results <- batchSimResult{simIdx: simIdx, result: &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}}
+					continue
+				}
+
+				results <- batchSimResult{simIdx: simIdx, result: RunSim(buildRequest(simIdx), nil, signals)}
+			}
+		}()
+	}
+
+	// Drain every result, so no worker is still simming once this returns.
+	var firstErr *proto.ErrorOutcome
+	for range numSims {
+		simResult := <-results
+		if firstErr != nil {
+			continue
+		}
+		if simResult.result.Error != nil {
+			signals.Abort.Trigger()
+			firstErr = simResult.result.Error
+			continue
+		}
+		onResult(simResult.simIdx, simResult.result)
+	}
+	return firstErr
+}
@@ -6,7 +6,6 @@ import (
 	"math"
 	"reflect"
 	"runtime"
-	"runtime/debug"
 	"slices"
 
 	"github.com/wowsims/mop/sim/core/proto"
@@ -33,18 +32,33 @@ func SplitSimRequestForConcurrency(request *proto.RaidSimRequest, splitCount int
 	split := make([]*proto.RaidSimRequest, splitCount)
 	iterPerSplit := request.SimOptions.Iterations / splitCount
 
+	// Adaptive sims split both bounds, and each split targets a looser
+	// precision so that the combined mean reaches the requested one.
+	adaptive := request.SimOptions.TargetStdevOfMean > 0
+	maxIterations := MaxIterations(request.SimOptions)
+	maxIterPerSplit := maxIterations / splitCount
+	splitTarget := request.SimOptions.TargetStdevOfMean * math.Sqrt(float64(splitCount))
+
 	split[0] = googleProto.Clone(request).(*proto.RaidSimRequest)
 	split[0].SimOptions.Iterations = iterPerSplit + request.SimOptions.Iterations%splitCount
+	if adaptive {
+		split[0].SimOptions.MaxIterations = maxIterPerSplit + maxIterations%splitCount
+		split[0].SimOptions.TargetStdevOfMean = splitTarget
+	}
 
 	// Sims increment their seed each iteration. Offset starting seed of each split to emulate that.
-	nextStartSeed := split[0].SimOptions.RandomSeed + int64(split[0].SimOptions.Iterations)
+	nextStartSeed := split[0].SimOptions.RandomSeed + int64(MaxIterations(split[0].SimOptions))
 
 	for i := 1; i < int(splitCount); i++ {
 		split[i] = googleProto.Clone(request).(*proto.RaidSimRequest)
 		split[i].SimOptions.Iterations = iterPerSplit
+		if adaptive {
+			split[i].SimOptions.MaxIterations = maxIterPerSplit
+			split[i].SimOptions.TargetStdevOfMean = splitTarget
+		}
 		split[i].SimOptions.DebugFirstIteration = false // No logs
 		split[i].SimOptions.RandomSeed = nextStartSeed
-		nextStartSeed += int64(split[i].SimOptions.Iterations)
+		nextStartSeed += int64(MaxIterations(split[i].SimOptions))
 	}
 
 	res.SplitsDone = splitCount
@@ -94,6 +108,22 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		newUm.Pets[i] = rsrc.newUnitMetrics(pet)
 	}
 
+	if baseUnit.HealthTimeline != nil {
+		newUm.HealthTimeline = &proto.HealthTimeline{BucketSeconds: baseUnit.HealthTimeline.BucketSeconds}
+	}
+
+	if baseUnit.AplProfile != nil {
+		newActionProfile := func(action *proto.APLActionProfile) *proto.APLActionProfile {
+			return &proto.APLActionProfile{Uuid: action.Uuid}
+		}
+		newUm.AplProfile = &proto.APLProfile{
+			PriorityList: MapSlice(baseUnit.AplProfile.PriorityList, newActionProfile),
+			Groups: MapSlice(baseUnit.AplProfile.Groups, func(group *proto.APLGroupProfile) *proto.APLGroupProfile {
+				return &proto.APLGroupProfile{Actions: MapSlice(group.Actions, newActionProfile)}
+			}),
+		}
+	}
+
 	return newUm
 }
 
@@ -237,6 +267,85 @@ func (rsrc *raidSimResultCombiner) addResourceMetrics(unit *proto.UnitMetrics, a
 	rm.ActualGain += add.ActualGain
 }
 
+func (rsrc *raidSimResultCombiner) combineAPLActionProfiles(base *proto.APLActionProfile, add *proto.APLActionProfile, weight float64) {
+	// Per execution averages are weighted by the number of executions.
+	addExecutions := add.ExecutionsAvg * weight
+	if totalExecutions := base.ExecutionsAvg + addExecutions; totalExecutions > 0 {
+		base.TimeBetweenExecutionsAvg = (base.TimeBetweenExecutionsAvg*base.ExecutionsAvg + add.TimeBetweenExecutionsAvg*addExecutions) / totalExecutions
+		base.PoolingTimeAvg = (base.PoolingTimeAvg*base.ExecutionsAvg + add.PoolingTimeAvg*addExecutions) / totalExecutions
+	}
+
+	base.EvaluationsAvg += add.EvaluationsAvg * weight
+	base.ConditionTrueAvg += add.ConditionTrueAvg * weight
+	base.ExecutionsAvg += addExecutions
+	base.BlockedByConditionAvg += add.BlockedByConditionAvg * weight
+	base.BlockedByCooldownAvg += add.BlockedByCooldownAvg * weight
+	base.BlockedByResourcesAvg += add.BlockedByResourcesAvg * weight
+	base.BlockedByGcdAvg += add.BlockedByGcdAvg * weight
+	base.BlockedByCastingAvg += add.BlockedByCastingAvg * weight
+	base.BlockedByOtherAvg += add.BlockedByOtherAvg * weight
+}
+
+func (rsrc *raidSimResultCombiner) addDamageTakenMetrics(unit *proto.UnitMetrics, add *proto.DamageTakenMetrics, weight float64) {
+	var base *proto.DamageTakenMetrics
+
+	addKey := add.Id.String()
+	for _, baseDamageTaken := range unit.DamageTaken {
+		if baseDamageTaken.Id.String() == addKey {
+			base = baseDamageTaken
+			break
+		}
+	}
+
+	if base == nil {
+		base = &proto.DamageTakenMetrics{
+			Id:          add.Id,
+			SpellSchool: add.SpellSchool,
+		}
+		unit.DamageTaken = append(unit.DamageTaken, base)
+	}
+
+	base.HitsAvg += add.HitsAvg * weight
+	base.CritsAvg += add.CritsAvg * weight
+	base.BlocksAvg += add.BlocksAvg * weight
+	base.DodgesAvg += add.DodgesAvg * weight
+	base.ParriesAvg += add.ParriesAvg * weight
+	base.MissesAvg += add.MissesAvg * weight
+	base.DamageAvg += add.DamageAvg * weight
+	base.ArmorMitigatedAvg += add.ArmorMitigatedAvg * weight
+	base.BlockedAvg += add.BlockedAvg * weight
+	base.AbsorbedAvg += add.AbsorbedAvg * weight
+	base.AvoidedAvg += add.AvoidedAvg * weight
+}
+
+func (rsrc *raidSimResultCombiner) combineHealthTimelines(base *proto.HealthTimeline, add *proto.HealthTimeline) {
+	for i, addBucket := range add.Buckets {
+		if i == len(base.Buckets) {
+			base.Buckets = append(base.Buckets, &proto.HealthTimelineBucket{
+				LowestHealthPercentHist: make([]int32, len(addBucket.LowestHealthPercentHist)),
+			})
+		}
+		baseBucket := base.Buckets[i]
+
+		// Buckets late in the fight may only be reached by some iterations,
+		// so these are weighted by samples rather than by iterations.
+		if samples := baseBucket.Samples + addBucket.Samples; samples > 0 {
+			baseBucket.LowestHealthPercentAvg = (baseBucket.LowestHealthPercentAvg*float64(baseBucket.Samples) + addBucket.LowestHealthPercentAvg*float64(addBucket.Samples)) / float64(samples)
+		}
+		baseBucket.Samples += addBucket.Samples
+		for j, count := range addBucket.LowestHealthPercentHist {
+			baseBucket.LowestHealthPercentHist[j] += count
+		}
+	}
+
+	for i, count := range add.DeathTimeHist {
+		if i == len(base.DeathTimeHist) {
+			base.DeathTimeHist = append(base.DeathTimeHist, 0)
+		}
+		base.DeathTimeHist[i] += count
+	}
+}
+
 func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, add *proto.UnitMetrics, isLast bool, weight float64) {
 	rsrc.combineDistMetrics(base.Dps, add.Dps, isLast, weight)
 	rsrc.combineDistMetrics(base.Threat, add.Threat, isLast, weight)
@@ -247,6 +356,8 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 
 	base.SecondsOomAvg += add.SecondsOomAvg * weight
 	base.ChanceOfDeath += add.ChanceOfDeath * weight
+	base.InterruptibleCastsAvg += add.InterruptibleCastsAvg * weight
+	base.CastsInterruptedAvg += add.CastsInterruptedAvg * weight
 
 	if add.DeathSeeds != nil {
 		base.DeathSeeds = append(base.DeathSeeds, add.DeathSeeds...)
@@ -265,9 +376,28 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 		rsrc.addResourceMetrics(base, addResource)
 	}
 
+	for _, addDamageTaken := range add.DamageTaken {
+		rsrc.addDamageTakenMetrics(base, addDamageTaken, weight)
+	}
+
+	if base.HealthTimeline != nil && add.HealthTimeline != nil {
+		rsrc.combineHealthTimelines(base.HealthTimeline, add.HealthTimeline)
+	}
+
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
+
+	if base.AplProfile != nil && add.AplProfile != nil {
+		for i, addAction := range add.AplProfile.PriorityList {
+			rsrc.combineAPLActionProfiles(base.AplProfile.PriorityList[i], addAction, weight)
+		}
+		for i, addGroup := range add.AplProfile.Groups {
+			for j, addAction := range addGroup.Actions {
+				rsrc.combineAPLActionProfiles(base.AplProfile.Groups[i].Actions[j], addAction, weight)
+			}
+		}
+	}
 }
 
 func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast bool, weight float64) {
@@ -288,11 +418,18 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 	}
 
 	rsrc.Combined.AvgIterationDuration += result.AvgIterationDuration * weight
-	rsrc.Combined.IterationsDone += result.IterationsDone
 
 	if rsrc.Debug {
 		rsrc.Combined.Logs += "-SIMSTART-\n" + result.Logs
+
+		// Every split numbers its iterations from 0, so continue from the previous splits.
+		for _, event := range result.CombatLog {
+			event.Iteration += rsrc.Combined.IterationsDone
+		}
+		rsrc.Combined.CombatLog = append(rsrc.Combined.CombatLog, result.CombatLog...)
 	}
+
+	rsrc.Combined.IterationsDone += result.IterationsDone
 }
 
 func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
@@ -306,10 +443,12 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 			Targets: make([]*proto.UnitMetrics, len(baseRsr.EncounterMetrics.Targets)),
 		},
 		FirstIterationDuration: baseRsr.FirstIterationDuration,
+		CombatLogUnits:         baseRsr.CombatLogUnits,
 	}
 
 	if !rsrc.Debug {
 		newRsr.Logs = baseRsr.Logs
+		newRsr.CombatLog = baseRsr.CombatLog
 	}
 
 	for i, party := range baseRsr.RaidMetrics.Parties {
@@ -346,6 +485,9 @@ func CombineConcurrentSimResults(results []*proto.RaidSimResult, isDebug bool) *
 		rsrc.AddResult(result, i == numResults-1, resultWeight)
 	}
 
+	raidDps := rsrc.Combined.RaidMetrics.Dps
+	rsrc.Combined.RaidDpsStdevOfMean = stdevOfMean(raidDps.Stdev, int(raidDps.AggregatorData.N))
+
 	return rsrc.Combined
 }
 
@@ -411,16 +553,7 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 	defer func() {
 		if !request.SimOptions.IsTest {
 			if err := recover(); err != nil {
-				errStr := ""
-				switch errt := err.(type) {
-				case string:
-					errStr = errt
-				case error:
-					errStr = errt.Error()
-				}
-
-				errStr += "\nStack Trace:\n" + string(debug.Stack())
-				result = &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: errStr}}
+				result = &proto.RaidSimResult{Error: ErrorOutcomeFromPanic(err)}
 
 				if progress != nil {
 					progress <- &proto.ProgressMetrics{FinalRaidResult: result}
@@ -435,6 +568,10 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 		}
 	}()
 
+	if request.SimOptions.Iterations <= 0 {
+		panic(NewUserError(proto.ErrorCode_ErrorCodeInvalidSimOptions, nil, "sim_options.iterations", "Iterations can't be 0 or negative!"))
+	}
+
 	splitRes := SplitSimRequestForConcurrency(request, TernaryInt32(request.SimOptions.IsTest, 3, int32(runtime.NumCPU())))
 
 	if splitRes.ErrorResult != "" {
@@ -448,7 +585,7 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 
 	csd := concurrentSimData{
 		Concurrency:     threads,
-		IterationsTotal: request.SimOptions.Iterations,
+		IterationsTotal: MaxIterations(request.SimOptions),
 		IterationsDone:  make([]int32, threads),
 		DpsValues:       make([]float64, threads),
 		HpsValues:       make([]float64, threads),
@@ -461,7 +598,11 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 	}
 
 	if !request.SimOptions.IsTest {
-		log.Printf("Running %d iterations on %d concurrent sims.", csd.IterationsTotal, csd.Concurrency)
+		if request.SimOptions.TargetStdevOfMean > 0 {
+			log.Printf("Running up to %d iterations on %d concurrent sims.", csd.IterationsTotal, csd.Concurrency)
+		} else {
+			log.Printf("Running %d iterations on %d concurrent sims.", csd.IterationsTotal, csd.Concurrency)
+		}
 	}
 
 	for i, req := range splitRes.Requests {
@@ -524,6 +665,7 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 
 	if progress != nil {
 		pm := csd.MakeProgressMetrics()
+		pm.TotalIterations = result.IterationsDone
 		pm.FinalRaidResult = result
 		progress <- pm
 	}
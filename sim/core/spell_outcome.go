@@ -671,6 +671,7 @@ func (result *SpellResult) applyAttackTableBlock(sim *Simulation, spell *Spell,
 			spell.SpellMetrics[result.Target.UnitIndex].Blocks++
 		}
 		damageReduced := result.Damage * (1 - result.Target.BlockDamageReduction())
+		result.BlockedDamage = result.Damage - max(0, damageReduced)
 		result.Damage = max(0, damageReduced)
 
 		return true
@@ -808,12 +809,15 @@ func (result *SpellResult) applyEnemyAttackTableBlock(sim *Simulation, spell *Sp
 			spell.SpellMetrics[result.Target.UnitIndex].Blocks++
 		}
 
+		preBlockDamage := result.Damage
 		if result.Target.Blockhandler != nil {
 			result.Target.Blockhandler(sim, spell, result)
+			result.BlockedDamage = max(0, preBlockDamage-result.Damage)
 			return true
 		}
 
 		result.Damage = max(0, result.Damage*(1-result.Target.BlockDamageReduction()))
+		result.BlockedDamage = preBlockDamage - result.Damage
 
 		return true
 	}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 )
 
@@ -20,6 +21,8 @@ type SpellResult struct {
 	ArmorMultiplier   float64 // Armor multiplier
 	PostArmorDamage   float64 // Damage done by this cast after Armor is applied
 	PostOutcomeDamage float64 // Damage done by this cast after Outcome is applied
+	PreOutcomeDamage  float64 // Damage done by this cast before Outcome is applied
+	BlockedDamage     float64 // Damage prevented by a block
 
 	inUse bool
 }
@@ -75,6 +78,8 @@ func (spell *Spell) NewResult(target *Unit) *SpellResult {
 	result.inUse = true
 	result.PostArmorDamage = 0
 	result.PostOutcomeDamage = 0
+	result.PreOutcomeDamage = 0
+	result.BlockedDamage = 0
 
 	return result
 }
@@ -109,6 +114,18 @@ func (result *SpellResult) HealingString() string {
 	return fmt.Sprintf("%s for %0.3f healing", result.Outcome.String(), result.Damage)
 }
 
+func (spell *Spell) logResultEvent(sim *Simulation, result *SpellResult, isPeriodic bool, eventType proto.CombatLogEventType, tickEventType proto.CombatLogEventType) {
+	if isPeriodic {
+		eventType = tickEventType
+	}
+	spell.Unit.LogEvent(sim, result.Target, &proto.CombatLogEvent{
+		Type:     eventType,
+		ActionId: spell.ActionID.ToProto(),
+		Outcome:  result.Outcome.ToProto(),
+		Amount:   result.Damage,
+	})
+}
+
 func (spell *Spell) ThreatFromDamage(sim *Simulation, outcome HitOutcome, damage float64, attackTable *AttackTable) float64 {
 	if outcome.Matches(OutcomeLanded) {
 		threat := (damage*spell.ThreatMultiplier + spell.FlatThreatBonus) * spell.Unit.PseudoStats.ThreatMultiplier
@@ -253,6 +270,7 @@ func (spell *Spell) calcDamageInternal(sim *Simulation, target *Unit, baseDamage
 		result.Damage *= attackerMultiplier
 		result.applyArmor(spell, isPeriodic, attackTable)
 		result.applyTargetModifiers(sim, spell, attackTable, isPeriodic)
+		result.PreOutcomeDamage = result.Damage
 
 		outcomeApplier(sim, result, attackTable)
 
@@ -263,6 +281,7 @@ func (spell *Spell) calcDamageInternal(sim *Simulation, target *Unit, baseDamage
 		result.applyArmor(spell, isPeriodic, attackTable)
 		result.applyTargetModifiers(sim, spell, attackTable, isPeriodic)
 		afterTargetMods := result.Damage
+		result.PreOutcomeDamage = result.Damage
 
 		outcomeApplier(sim, result, attackTable)
 
@@ -341,12 +360,17 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.SpellMetrics[result.Target.UnitIndex].TotalBlockDamage += result.Damage
 		}
 		spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+
+		if result.Target.Type == PlayerUnit && spell.Unit.IsOpponent(result.Target) {
+			result.Target.Metrics.addDamageTaken(spell, result)
+		}
 	}
 
 	// Mark total damage done in raid so far for health based fights.
 	// Don't include damage done by EnemyUnits to Players
 	if result.Target.Type == EnemyUnit {
 		sim.Encounter.DamageTaken += result.Damage
+		sim.Encounter.AllTargets[result.Target.Index].timeToDie.addDamage(sim, result.Damage)
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
@@ -356,6 +380,9 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		spell.logResultEvent(sim, result, isPeriodic, proto.CombatLogEventType_CombatLogEventDamage, proto.CombatLogEventType_CombatLogEventDamageTick)
+	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
 		if isPeriodic {
@@ -564,6 +591,8 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
 	if result.Target.HasHealthBar() {
+		missingHealth := result.Target.MaxHealth() - result.Target.CurrentHealth()
+		spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += max(0, result.Damage-missingHealth)
 		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
 	}
 
@@ -574,6 +603,9 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		spell.logResultEvent(sim, result, isPeriodic, proto.CombatLogEventType_CombatLogEventHealing, proto.CombatLogEventType_CombatLogEventHealingTick)
+	}
 
 	if isPeriodic {
 		spell.Unit.OnPeriodicHealDealt(sim, spell, result)
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const MaxStatPlotPoints = 1000
+
+// Returns the evenly spaced bonus values along an axis, or a single 0 when
+// the axis is unused.
+func statPlotAxisValues(axis *proto.StatPlotAxis) []float64 {
+	if axis == nil {
+		return []float64{0}
+	}
+
+	values := make([]float64, axis.Steps)
+	for i := range values {
+		values[i] = axis.Min + (axis.Max-axis.Min)*float64(i)/float64(axis.Steps-1)
+	}
+	return values
+}
+
+func validateStatPlotAxis(axis *proto.StatPlotAxis) error {
+	if axis.Stat < 0 || int(axis.Stat) >= int(stats.ProtoStatsLen) {
+		return fmt.Errorf("invalid stat %d", axis.Stat)
+	}
+	if axis.Steps < 2 {
+		return fmt.Errorf("%s axis needs at least 2 steps", axis.Stat)
+	}
+	if axis.Max <= axis.Min {
+		return fmt.Errorf("%s axis max must be greater than its min", axis.Stat)
+	}
+	return nil
+}
+
+// Run a stat plot, simming the player at every point of a one or two stat grid.
+func runStatPlot(request *proto.StatPlotRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatPlotResult {
+	errorResult := func(format string, args ...any) *proto.StatPlotResult {
+		return &proto.StatPlotResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if request.BaseSettings == nil || request.BaseSettings.Raid == nil || request.BaseSettings.SimOptions == nil {
+		return errorResult("Stat plot request is missing base settings!")
+	}
+	if request.XAxis == nil {
+		return errorResult("Stat plot request has no stat to plot!")
+	}
+	for _, axis := range []*proto.StatPlotAxis{request.XAxis, request.YAxis} {
+		if axis == nil {
+			continue
+		}
+		if err := validateStatPlotAxis(axis); err != nil {
+			return errorResult("Invalid stat plot axis: %s", err)
+		}
+	}
+	if request.YAxis != nil && request.YAxis.Stat == request.XAxis.Stat {
+		return errorResult("Stat plot axes must use different stats!")
+	}
+
+	baseRequest := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if len(baseRequest.Raid.Parties) == 0 || len(baseRequest.Raid.Parties[0].Players) == 0 {
+		return errorResult("Stat plot request has no player!")
+	}
+	if baseRequest.SimOptions.Iterations <= 0 {
+		return errorResult("Iterations can't be 0 or negative!")
+	}
+
+	xValues := statPlotAxisValues(request.XAxis)
+	yValues := statPlotAxisValues(request.YAxis)
+	numPoints := len(xValues) * len(yValues)
+	if numPoints > MaxStatPlotPoints {
+		return errorResult("Too many points (%d), the maximum is %d!", numPoints, MaxStatPlotPoints)
+	}
+
+	basePlayer := baseRequest.Raid.Parties[0].Players[0]
+	if basePlayer.BonusStats == nil {
+		basePlayer.BonusStats = &proto.UnitStats{}
+	}
+	if basePlayer.BonusStats.Stats == nil {
+		basePlayer.BonusStats.Stats = make([]float64, stats.ProtoStatsLen)
+	}
+	if basePlayer.BonusStats.PseudoStats == nil {
+		basePlayer.BonusStats.PseudoStats = make([]float64, stats.PseudoStatsLen)
+	}
+
+	// All points share a seed so that the curve is smooth and differences
+	// between neighbouring points come from the stats rather than RNG.
+	if baseRequest.SimOptions.RandomSeed == 0 {
+		baseRequest.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseRequest.SimOptions.UseLabeledRands = true
+	baseRequest.SimOptions.Debug = false
+	baseRequest.SimOptions.DebugFirstIteration = false
+
+	points := make([]*proto.StatPlotPoint, 0, numPoints)
+	for _, y := range yValues {
+		for _, x := range xValues {
+			points = append(points, &proto.StatPlotPoint{XValue: x, YValue: y})
+		}
+	}
+
+	buildPointRequest := func(point *proto.StatPlotPoint) *proto.RaidSimRequest {
+		pointRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+		bonusStats := pointRequest.Raid.Parties[0].Players[0].BonusStats
+		stats.UnitStatFromStat(stats.Stat(request.XAxis.Stat)).AddToStatsProto(bonusStats, point.XValue)
+		if request.YAxis != nil {
+			stats.UnitStatFromStat(stats.Stat(request.YAxis.Stat)).AddToStatsProto(bonusStats, point.YValue)
+		}
+		return pointRequest
+	}
+
+	simsTotal := int32(numPoints)
+	iterationsTotal := simsTotal * baseRequest.SimOptions.Iterations
+	var simsCompleted int32 = 0
+
+	errorOutcome := runSimBatch(numPoints, func(pointIdx int) *proto.RaidSimRequest {
+		return buildPointRequest(points[pointIdx])
+	}, func(pointIdx int, result *proto.RaidSimResult) {
+		playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+		point := points[pointIdx]
+		point.Dps = playerMetrics.Dps.Avg
+		point.DpsStdevOfMean = stdevOfMean(playerMetrics.Dps.Stdev, int(result.IterationsDone))
+		point.Hps = playerMetrics.Hps.Avg
+		point.HpsStdevOfMean = stdevOfMean(playerMetrics.Hps.Stdev, int(result.IterationsDone))
+
+		simsCompleted++
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     iterationsTotal,
+				CompletedIterations: simsCompleted * baseRequest.SimOptions.Iterations,
+				CompletedSims:       simsCompleted,
+				TotalSims:           simsTotal,
+			}
+		}
+	}, signals)
+	if errorOutcome != nil {
+		return &proto.StatPlotResult{Error: errorOutcome}
+	}
+
+	return &proto.StatPlotResult{
+		Points: points,
+	}
+}
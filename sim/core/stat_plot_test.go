@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestStatPlotAxisValues(t *testing.T) {
+	values := statPlotAxisValues(&proto.StatPlotAxis{Stat: proto.Stat_StatHasteRating, Min: -600, Max: 600, Steps: 5})
+	expected := []float64{-600, -300, 0, 300, 600}
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %d values, got %d", len(expected), len(values))
+	}
+	for i := range expected {
+		if !WithinToleranceFloat64(expected[i], values[i], 0.0001) {
+			t.Fatalf("Value %d: expected %0.2f, got %0.2f", i, expected[i], values[i])
+		}
+	}
+
+	if values := statPlotAxisValues(nil); len(values) != 1 || values[0] != 0 {
+		t.Fatalf("Expected a missing axis to contribute a single 0 point, got %v", values)
+	}
+}
+
+func TestStatPlotValidation(t *testing.T) {
+	baseSettings := &proto.RaidSimRequest{
+		Raid:       SinglePlayerRaidProto(&proto.Player{}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter:  &proto.Encounter{},
+		SimOptions: &proto.SimOptions{Iterations: 10},
+	}
+	hasteAxis := &proto.StatPlotAxis{Stat: proto.Stat_StatHasteRating, Min: 0, Max: 1000, Steps: 11}
+
+	for name, request := range map[string]*proto.StatPlotRequest{
+		"no axis":     {BaseSettings: baseSettings},
+		"one step":    {BaseSettings: baseSettings, XAxis: &proto.StatPlotAxis{Stat: proto.Stat_StatHasteRating, Max: 100, Steps: 1}},
+		"empty range": {BaseSettings: baseSettings, XAxis: &proto.StatPlotAxis{Stat: proto.Stat_StatHasteRating, Min: 100, Max: 100, Steps: 3}},
+		"same stats":  {BaseSettings: baseSettings, XAxis: hasteAxis, YAxis: hasteAxis},
+		"too many points": {
+			BaseSettings: baseSettings,
+			XAxis:        &proto.StatPlotAxis{Stat: proto.Stat_StatHasteRating, Max: 1000, Steps: 100},
+			YAxis:        &proto.StatPlotAxis{Stat: proto.Stat_StatMasteryRating, Max: 1000, Steps: 100},
+		},
+	} {
+		if result := RunStatPlot(request); result.Error == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+}
@@ -159,6 +159,8 @@ type Target struct {
 	Unit
 
 	AI TargetAI
+
+	timeToDie *TimeToDieEstimator
 }
 
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
@@ -184,6 +186,7 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 			enabled:               !options.DisabledAtStart,
 		},
 	}
+	target.timeToDie = newTimeToDieEstimator(target)
 	defaultRaidBossLevel := int32(CharacterLevel + 3)
 	target.GCD = target.NewTimer()
 	target.RotationTimer = target.NewTimer()
@@ -223,6 +226,7 @@ func (target *Target) Reset(sim *Simulation) {
 	}
 
 	target.SetGCDTimer(sim, 0)
+	target.timeToDie.reset()
 
 	if target.AI != nil {
 		target.AI.Reset(sim)
@@ -49,12 +49,14 @@ func (target *Target) initialize(config *proto.Target) {
 }
 
 // Empty Agent interface functions.
-func (target *Target) AddRaidBuffs(_ *proto.RaidBuffs)   {}
-func (target *Target) AddPartyBuffs(_ *proto.PartyBuffs) {}
-func (target *Target) ApplyTalents()                     {}
-func (target *Target) GetCharacter() *Character          { return nil }
-func (target *Target) Initialize()                       {}
-func (target *Target) OnEncounterStart(_ *Simulation)    {}
+func (target *Target) AddRaidBuffs(_ *proto.RaidBuffs)            {}
+func (target *Target) AddCompositionRaidBuffs(_ *proto.RaidBuffs) {}
+func (target *Target) AddPartyBuffs(_ *proto.PartyBuffs)          {}
+func (target *Target) AddRaidDebuffs(_ *proto.Debuffs)            {}
+func (target *Target) ApplyTalents()                              {}
+func (target *Target) GetCharacter() *Character                   { return nil }
+func (target *Target) Initialize()                                {}
+func (target *Target) OnEncounterStart(_ *Simulation)             {}
 
 func (target *Target) ExecuteCustomRotation(sim *Simulation) {
 	if (target.AI != nil) && target.IsEnabled() {
@@ -46,7 +46,9 @@ func (td *TargetDummy) GetCharacter() *Character {
 	return &td.Character
 }
 func (td *TargetDummy) AddRaidBuffs(raidBuffs *proto.RaidBuffs)    {}
+func (td *TargetDummy) AddCompositionRaidBuffs(_ *proto.RaidBuffs) {}
 func (td *TargetDummy) AddPartyBuffs(partyBuffs *proto.PartyBuffs) {}
+func (td *TargetDummy) AddRaidDebuffs(debuffs *proto.Debuffs)      {}
 func (td *TargetDummy) ApplyTalents()                              {}
 func (td *TargetDummy) Initialize()                                {}
 func (td *TargetDummy) Reset(sim *Simulation)                      {}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// How far back damage taken is considered when estimating a target's time to die.
+const TimeToDieWindow = time.Second * 10
+
+type timeToDieSample struct {
+	at     time.Duration
+	damage float64
+}
+
+// Estimates how long a target has left to live from its recent damage intake.
+type TimeToDieEstimator struct {
+	target *Target
+
+	damageTaken   float64
+	firstDamageAt time.Duration
+
+	// Damage events inside the window, oldest first, starting at windowStart.
+	samples      []timeToDieSample
+	windowStart  int
+	windowDamage float64
+}
+
+func newTimeToDieEstimator(target *Target) *TimeToDieEstimator {
+	return &TimeToDieEstimator{
+		target:        target,
+		firstDamageAt: -1,
+	}
+}
+
+func (ttd *TimeToDieEstimator) reset() {
+	ttd.damageTaken = 0
+	ttd.firstDamageAt = -1
+	ttd.samples = ttd.samples[:0]
+	ttd.windowStart = 0
+	ttd.windowDamage = 0
+}
+
+func (ttd *TimeToDieEstimator) addDamage(sim *Simulation, damage float64) {
+	if damage <= 0 {
+		return
+	}
+	if ttd.firstDamageAt < 0 {
+		ttd.firstDamageAt = sim.CurrentTime
+	}
+	ttd.damageTaken += damage
+	ttd.windowDamage += damage
+	ttd.samples = append(ttd.samples, timeToDieSample{at: sim.CurrentTime, damage: damage})
+	ttd.pruneWindow(sim)
+}
+
+func (ttd *TimeToDieEstimator) pruneWindow(sim *Simulation) {
+	windowStartAt := sim.CurrentTime - TimeToDieWindow
+	for ttd.windowStart < len(ttd.samples) && ttd.samples[ttd.windowStart].at <= windowStartAt {
+		ttd.windowDamage -= ttd.samples[ttd.windowStart].damage
+		ttd.windowStart++
+	}
+	if ttd.windowStart == len(ttd.samples) {
+		// Avoids accumulating float error over a long fight.
+		ttd.windowDamage = 0
+	}
+
+	// Reuse the space of expired samples once they make up most of the slice.
+	if ttd.windowStart > 256 && ttd.windowStart*2 > len(ttd.samples) {
+		ttd.samples = ttd.samples[:copy(ttd.samples, ttd.samples[ttd.windowStart:])]
+		ttd.windowStart = 0
+	}
+}
+
+// Total damage taken by the target this iteration.
+func (ttd *TimeToDieEstimator) DamageTaken() float64 {
+	return ttd.damageTaken
+}
+
+// Damage per second taken by the target over the last TimeToDieWindow, or
+// since it first took damage if that was more recent.
+func (ttd *TimeToDieEstimator) DamageRate(sim *Simulation) float64 {
+	if ttd.firstDamageAt < 0 {
+		return 0
+	}
+	ttd.pruneWindow(sim)
+
+	elapsed := min(sim.CurrentTime-ttd.firstDamageAt, TimeToDieWindow)
+	if elapsed <= 0 {
+		return 0
+	}
+	return max(ttd.windowDamage, 0) / elapsed.Seconds()
+}
+
+// Estimated time until the target's health drops to the given fraction (0-1)
+// of its maximum. Never longer than the remaining fight duration, which is
+// also returned while there is no damage intake to base an estimate on.
+//
+// Targets without a health value are assumed to lose health linearly over the
+// fight, the same way execute phases are timed.
+func (ttd *TimeToDieEstimator) TimeToPercent(sim *Simulation, percent float64) time.Duration {
+	remainingDuration := max(sim.GetRemainingDuration(), 0)
+
+	maxHealth := ttd.target.GetStat(stats.Health)
+	if maxHealth <= 0 {
+		return max(remainingDuration-DurationFromSeconds(percent*sim.Duration.Seconds()), 0)
+	}
+
+	healthToLose := maxHealth - ttd.damageTaken - percent*maxHealth
+	if healthToLose <= 0 {
+		return 0
+	}
+
+	rate := ttd.DamageRate(sim)
+	if rate <= 0 {
+		return remainingDuration
+	}
+	// Compared in seconds, as very low rates would overflow a Duration.
+	seconds := healthToLose / rate
+	if seconds >= remainingDuration.Seconds() {
+		return remainingDuration
+	}
+	return DurationFromSeconds(seconds)
+}
+
+// Estimated time until the target dies, see TimeToPercent.
+func (ttd *TimeToDieEstimator) TimeToDie(sim *Simulation) time.Duration {
+	return ttd.TimeToPercent(sim, 0)
+}
+
+func (target *Target) TimeToDie(sim *Simulation) time.Duration {
+	return target.timeToDie.TimeToDie(sim)
+}
+
+func (target *Target) TimeToPercent(sim *Simulation, percent float64) time.Duration {
+	return target.timeToDie.TimeToPercent(sim, percent)
+}
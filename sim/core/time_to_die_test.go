@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestTimeToDieFromRecentDamage(t *testing.T) {
+	sim := &Simulation{Environment: &Environment{}, Duration: time.Minute * 5}
+	target := NewTarget(&proto.Target{Stats: stats.Stats{stats.Health: 100_000}.ToProtoArray()}, 0)
+
+	if ttd := target.TimeToDie(sim); ttd != sim.Duration {
+		t.Fatalf("Expected the remaining duration before any damage, got %s", ttd)
+	}
+
+	// 1000 DPS for 20s, then 4000 DPS for 10s.
+	for sim.CurrentTime = time.Second; sim.CurrentTime <= time.Second*30; sim.CurrentTime += time.Second {
+		target.timeToDie.addDamage(sim, TernaryFloat64(sim.CurrentTime <= time.Second*20, 1000, 4000))
+	}
+	sim.CurrentTime -= time.Second
+
+	// Only the last 10s count, so 40000 health remain at 4000 DPS.
+	if ttd := target.TimeToDie(sim); ttd != time.Second*10 {
+		t.Fatalf("Expected 10s to die, got %s", ttd)
+	}
+	if ttd := target.TimeToPercent(sim, 0.2); ttd != time.Second*5 {
+		t.Fatalf("Expected 5s to 20%%, got %s", ttd)
+	}
+	if ttd := target.TimeToPercent(sim, 0.5); ttd != 0 {
+		t.Fatalf("Expected 0s to a percent already reached, got %s", ttd)
+	}
+
+	// Without damage the target outlives the fight.
+	sim.CurrentTime += time.Second * 15
+	if ttd := target.TimeToDie(sim); ttd != sim.Duration-sim.CurrentTime {
+		t.Fatalf("Expected the remaining duration without recent damage, got %s", ttd)
+	}
+
+	target.timeToDie.reset()
+	if damageTaken := target.timeToDie.DamageTaken(); damageTaken != 0 {
+		t.Fatalf("Expected no damage taken after reset, got %f", damageTaken)
+	}
+}
+
+func TestTimeToPercentWithoutHealth(t *testing.T) {
+	sim := &Simulation{Environment: &Environment{}, Duration: time.Minute * 5, CurrentTime: time.Minute}
+	target := NewTarget(&proto.Target{}, 0)
+
+	// Health is assumed to drop linearly, so 20% is reached a minute before the end.
+	if ttd := target.TimeToPercent(sim, 0.2); ttd != time.Minute*3 {
+		t.Fatalf("Expected 3m to 20%%, got %s", ttd)
+	}
+	if ttd := target.TimeToDie(sim); ttd != time.Minute*4 {
+		t.Fatalf("Expected 4m to die, got %s", ttd)
+	}
+}
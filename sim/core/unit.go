@@ -249,6 +249,31 @@ func (unit *Unit) Log(sim *Simulation, message string, vals ...interface{}) {
 	sim.Log(unit.LogLabel()+" "+message, vals...)
 }
 
+// Emits a structured combat log event with this unit as the source. Like Log,
+// callers should check sim.CombatLog != nil first.
+func (unit *Unit) LogEvent(sim *Simulation, target *Unit, event *proto.CombatLogEvent) {
+	event.UnitIndex = unit.UnitIndex
+	event.TargetUnitIndex = -1
+	if target != nil {
+		event.TargetUnitIndex = target.UnitIndex
+	}
+	sim.CombatLog(event)
+}
+
+func (unit *Unit) logResourceEvent(sim *Simulation, metrics *ResourceMetrics, amount float64, newValue float64, isSpend bool) {
+	eventType := proto.CombatLogEventType_CombatLogEventResourceGain
+	if isSpend {
+		eventType = proto.CombatLogEventType_CombatLogEventResourceSpend
+	}
+	unit.LogEvent(sim, nil, &proto.CombatLogEvent{
+		Type:          eventType,
+		ActionId:      metrics.ActionID.ToProto(),
+		Amount:        amount,
+		ResourceType:  metrics.Type,
+		ResourceValue: newValue,
+	})
+}
+
 func (unit *Unit) GetInitialStat(stat stats.Stat) float64 {
 	return unit.initialStats[stat]
 }
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// An error caused by invalid user settings rather than a bug in the sim.
+// Panicking with a *UserError lets the recovery points report which setting is
+// broken instead of a stack-traced message.
+type UserError struct {
+	Code      proto.ErrorCode
+	Unit      *proto.UnitReference // The unit whose settings are invalid, if any.
+	AplUuid   *proto.UUID          // The offending APL action, if any.
+	FieldPath string               // Path of the invalid field within the request, if known.
+	Message   string
+}
+
+func NewUserError(code proto.ErrorCode, unit *proto.UnitReference, fieldPath string, format string, args ...any) *UserError {
+	return &UserError{
+		Code:      code,
+		Unit:      unit,
+		FieldPath: fieldPath,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+func (err *UserError) Error() string {
+	if err.FieldPath != "" {
+		return fmt.Sprintf("%s (%s)", err.Message, err.FieldPath)
+	}
+	return err.Message
+}
+
+func (err *UserError) ToProto() *proto.ErrorOutcome {
+	return &proto.ErrorOutcome{
+		Type:      proto.ErrorOutcomeType_ErrorOutcomeError,
+		Message:   err.Message,
+		Code:      err.Code,
+		Unit:      err.Unit,
+		AplUuid:   err.AplUuid,
+		FieldPath: err.FieldPath,
+	}
+}
+
+// Converts a recovered panic value into an ErrorOutcome. User errors keep their
+// structured fields, anything else is reported with a stack trace. Must be
+// called from the deferred function doing the recover.
+func ErrorOutcomeFromPanic(err any) *proto.ErrorOutcome {
+	if userErr, ok := err.(*UserError); ok {
+		return userErr.ToProto()
+	}
+
+	errStr := ""
+	switch errt := err.(type) {
+	case string:
+		errStr = errt
+	case error:
+		errStr = errt.Error()
+	}
+
+	errStr += "\nStack Trace:\n" + string(debug.Stack())
+	return &proto.ErrorOutcome{Message: errStr}
+}
+
+func playerUnitReference(raidIndex int32) *proto.UnitReference {
+	return &proto.UnitReference{Type: proto.UnitReference_Player, Index: raidIndex}
+}
+
+// Returns a reference to the unit as it would appear in the request, or nil for pets.
+func (unit *Unit) protoReference() *proto.UnitReference {
+	switch unit.Type {
+	case PlayerUnit:
+		return playerUnitReference(unit.Index)
+	case EnemyUnit:
+		return &proto.UnitReference{Type: proto.UnitReference_Target, Index: unit.Index}
+	}
+	return nil
+}
+
+func playerFieldPath(raidIndex int32, field string) string {
+	return fmt.Sprintf("raid.parties[%d].players[%d].%s", raidIndex/5, raidIndex%5, field)
+}
+
+// Checks the raid settings which would otherwise panic deep inside environment
+// construction, so the error can name the offending player and field.
+func validateRaidProto(raidProto *proto.Raid) {
+	for partyIdx, partyProto := range raidProto.GetParties() {
+		for playerIdx, playerProto := range partyProto.GetPlayers() {
+			if playerProto == nil || playerProto.Class == proto.Class_ClassUnknown {
+				continue
+			}
+			if err := validatePlayerProto(playerProto, int32(partyIdx*5+playerIdx)); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func validatePlayerProto(player *proto.Player, raidIndex int32) *UserError {
+	unitRef := playerUnitReference(raidIndex)
+
+	if player.Spec == nil {
+		return NewUserError(proto.ErrorCode_ErrorCodeInvalidSpec, unitRef, playerFieldPath(raidIndex, "spec"), "%s has no spec selected.", player.Name)
+	}
+	if _, ok := agentFactories[specTypeName(player)]; !ok {
+		return NewUserError(proto.ErrorCode_ErrorCodeInvalidSpec, unitRef, playerFieldPath(raidIndex, "spec"), "%s has an unsupported spec: %s.", player.Name, specTypeName(player))
+	}
+
+	for talentIdx, talentValStr := range player.TalentsString {
+		// FillTalentsProto ignores anything other than a non-zero digit.
+		if talentValStr < '1' || talentValStr > '9' {
+			continue
+		}
+		if talentIdx >= NumTalentTiers || talentValStr > '3' {
+			return NewUserError(proto.ErrorCode_ErrorCodeInvalidTalents, unitRef, playerFieldPath(raidIndex, "talents_string"), "%s has an invalid talent string: %s.", player.Name, player.TalentsString)
+		}
+	}
+
+	for _, issue := range ValidateEquipment(player).Errors {
+		if issue.Type != proto.GearIssueType_GearIssueMissingFromDatabase && issue.Type != proto.GearIssueType_GearIssueReforge {
+			continue
+		}
+		return NewUserError(proto.ErrorCode_ErrorCodeInvalidEquipment, unitRef, playerFieldPath(raidIndex, fmt.Sprintf("equipment.items[%d]", issue.Slot)), "%s: %s", player.Name, issue.Message)
+	}
+
+	return nil
+}
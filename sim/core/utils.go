@@ -315,6 +315,16 @@ func (x *aggregator) meanAndStdDev() (float64, float64) {
 	return mean, stdDev
 }
 
+// Returns the standard error of a mean given the population standard deviation
+// of its n samples. Rounding can make the variance of identical samples
+// slightly negative, so NaN is treated as 0.
+func stdevOfMean(stdev float64, n int) float64 {
+	if n < 2 || math.IsNaN(stdev) {
+		return 0
+	}
+	return stdev / math.Sqrt(float64(n))
+}
+
 func GetCurrentProtoVersion() int32 {
 	versionMessage := &proto.ProtoVersion{}
 	options := versionMessage.ProtoReflect().Descriptor().Options()
@@ -81,6 +81,18 @@ func (dk *DeathKnight) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	}
 }
 
+func (dk *DeathKnight) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	raidBuffs.HornOfWinter = true
+}
+
+func (dk *DeathKnight) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	if dk.Spec == proto.Spec_SpecBloodDeathKnight {
+		debuffs.WeakenedBlows = true
+	} else {
+		debuffs.PhysicalVulnerability = true
+	}
+}
+
 func (dk *DeathKnight) Initialize() {
 	dk.registerAntiMagicShell()
 	dk.registerArmyOfTheDead()
@@ -95,6 +107,7 @@ func (dk *DeathKnight) Initialize() {
 	dk.registerHornOfWinter()
 	dk.registerIceboundFortitude()
 	dk.registerIcyTouch()
+	dk.registerMindFreeze()
 	dk.registerOutbreak()
 	dk.registerPestilence()
 	dk.registerPlagueStrike()
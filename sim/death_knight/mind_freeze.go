@@ -0,0 +1,31 @@
+package death_knight
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (dk *DeathKnight) registerMindFreeze() {
+	dk.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 47528},
+		SpellSchool: core.SpellSchoolFrost,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    core.MaxMeleeRange,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    dk.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMeleeSpecialHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*4)
+			}
+		},
+	})
+}
@@ -9,25 +9,18 @@ import (
 
 // Incites a friendly party or raid member into a killing frenzy for 30 sec, increasing the target's melee and ranged haste by 20%, but causing them to lose health equal to 2% of their maximum health every 3 sec.
 func (uhdk *UnholyDeathKnight) registerUnholyFrenzy() {
-	actionID := core.ActionID{SpellID: 49016, Tag: uhdk.Index}
+	actionID := core.ActionID{SpellID: 49016}
 
 	unholyFrenzyAuras := uhdk.NewAllyAuraArray(func(u *core.Unit) *core.Aura {
 		if u.Type == core.PetUnit {
 			return nil
 		}
 
-		return core.UnholyFrenzyAura(u, actionID.Tag, func() bool {
+		return core.UnholyFrenzyAura(u, uhdk.Index, func() bool {
 			return uhdk.T14Dps4pc.IsActive()
 		})
 	})
 	unholyFrenzyTarget := uhdk.GetUnit(uhdk.Inputs.UnholyFrenzyTarget)
-	if unholyFrenzyTarget == nil {
-		unholyFrenzyTarget = &uhdk.Unit
-	}
-
-	if unholyFrenzyTarget == nil {
-		return
-	}
 
 	unholyFrenzy := uhdk.Character.RegisterSpell(core.SpellConfig{
 		ActionID:       actionID,
@@ -45,12 +38,16 @@ func (uhdk *UnholyDeathKnight) registerUnholyFrenzy() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			// Major cooldowns are cast on the death knight, so only another raid member is an APL choice.
 			if unholyFrenzyTarget != nil {
 				unholyFrenzyAuras.Get(unholyFrenzyTarget).Activate(sim)
-			} else if target.Type == core.PlayerUnit {
+			} else if target.Type == core.PlayerUnit && target != &uhdk.Unit {
 				unholyFrenzyAuras.Get(target).Activate(sim)
+			} else {
+				unholyFrenzyAuras.Get(&uhdk.Unit).Activate(sim)
 			}
 		},
+		RelatedAuraArrays: unholyFrenzyAuras.ToMap(),
 	})
 
 	uhdk.AddMajorCooldown(core.MajorCooldown{
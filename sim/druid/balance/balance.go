@@ -77,6 +77,11 @@ func (moonkin *BalanceDruid) GetDruid() *druid.Druid {
 	return moonkin.Druid
 }
 
+func (moonkin *BalanceDruid) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	moonkin.Druid.AddCompositionRaidBuffs(raidBuffs)
+	raidBuffs.MoonkinAura = true
+}
+
 func (moonkin *BalanceDruid) Initialize() {
 	moonkin.Druid.Initialize()
 
@@ -175,6 +175,14 @@ func (druid *Druid) GetCharacter() *core.Character {
 // 	raidBuffs.MarkOfTheWild = true
 // }
 
+func (druid *Druid) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	raidBuffs.MarkOfTheWild = true
+}
+
+func (druid *Druid) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	debuffs.WeakenedArmor = true
+}
+
 func (druid *Druid) HasMajorGlyph(glyph proto.DruidMajorGlyph) bool {
 	return druid.HasGlyph(int32(glyph))
 }
@@ -267,6 +275,7 @@ func (druid *Druid) RegisterFeralCatSpells() {
 	druid.registerRakeSpell()
 	druid.registerRavageSpell()
 	druid.registerRipSpell()
+	druid.registerSkullBashSpell()
 	druid.registerSwipeBearSpell()
 	druid.registerSwipeCatSpell()
 	druid.registerThrashBearSpell()
@@ -286,6 +295,7 @@ func (druid *Druid) RegisterFeralTankSpells() {
 	druid.registerLacerateSpell()
 	druid.registerRakeSpell()
 	druid.registerRipSpell()
+	druid.registerSkullBashSpell()
 	druid.registerSurvivalInstinctsCD()
 	druid.registerSwipeBearSpell()
 	druid.registerThrashBearSpell()
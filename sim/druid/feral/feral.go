@@ -94,6 +94,11 @@ func (cat *FeralDruid) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.LeaderOfThePack = true
 }
 
+func (cat *FeralDruid) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	cat.Druid.AddRaidDebuffs(debuffs)
+	debuffs.WeakenedBlows = true
+}
+
 func (cat *FeralDruid) Initialize() {
 	cat.Druid.Initialize()
 	cat.RegisterFeralCatSpells()
@@ -87,6 +87,11 @@ func (bear *GuardianDruid) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.LeaderOfThePack = true
 }
 
+func (bear *GuardianDruid) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	bear.Druid.AddRaidDebuffs(debuffs)
+	debuffs.WeakenedBlows = true
+}
+
 func (bear *GuardianDruid) ApplyTalents() {
 	bear.Druid.ApplyTalents()
 	bear.applySpecTalents()
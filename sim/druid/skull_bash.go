@@ -0,0 +1,31 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (druid *Druid) registerSkullBashSpell() {
+	druid.RegisterSpell(Cat|Bear, core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 106839},
+		SpellSchool: core.SpellSchoolPhysical,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    13,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    druid.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMeleeSpecialHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*4)
+			}
+		},
+	})
+}
@@ -352,6 +352,7 @@ func (ai *GarajalAI) registerShadowBolt() {
 		ActionID:         core.ActionID{SpellID: 122118},
 		SpellSchool:      core.SpellSchoolShadow,
 		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagInterruptible,
 		DamageMultiplier: 1,
 
 		Cast: core.CastConfig{
@@ -0,0 +1,132 @@
+package scripted
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Reads an EncounterScript in protojson format.
+func LoadEncounterScript(path string) (*proto.EncounterScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	script := &proto.EncounterScript{}
+	if err := protojson.Unmarshal(data, script); err != nil {
+		return nil, fmt.Errorf("failed to parse encounter script %s: %w", path, err)
+	}
+
+	return script, nil
+}
+
+// Loads every .json file in dir as an EncounterScript and registers it as a
+// preset encounter.
+func LoadEncounterScripts(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		script, err := LoadEncounterScript(path)
+		if err != nil {
+			return err
+		}
+
+		if err := AddScriptedEncounter(script); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Registers the targets of the script as preset targets driven by a
+// ScriptedAI, and adds a preset encounter containing all of them.
+func AddScriptedEncounter(script *proto.EncounterScript) error {
+	if err := ValidateEncounterScript(script); err != nil {
+		return err
+	}
+
+	targetPaths := make([]string, len(script.Targets))
+	for i, scriptedTarget := range script.Targets {
+		presetTarget := &core.PresetTarget{
+			PathPrefix: script.PathPrefix,
+			Config:     scriptedTarget.Config,
+			AI:         NewScriptedAI(scriptedTarget),
+		}
+
+		core.AddPresetTarget(presetTarget)
+		targetPaths[i] = presetTarget.Path()
+	}
+
+	core.AddPresetEncounter(script.Name, targetPaths)
+	return nil
+}
+
+// Checks that a script is well-formed and doesn't collide with any existing
+// preset, since the preset registration functions treat that as fatal.
+func ValidateEncounterScript(script *proto.EncounterScript) error {
+	if script.Name == "" || script.PathPrefix == "" {
+		return fmt.Errorf("encounter script must have a name and path prefix")
+	}
+	if len(script.Targets) == 0 {
+		return fmt.Errorf("encounter script %s has no targets", script.Name)
+	}
+
+	for targetIdx, scriptedTarget := range script.Targets {
+		config := scriptedTarget.Config
+		if config == nil || config.Name == "" || config.Id == 0 {
+			return fmt.Errorf("target %d of %s must have a name and id", targetIdx, script.Name)
+		}
+		if preset := core.GetPresetTargetWithID(config.Id); preset != nil {
+			return fmt.Errorf("target id %d of %s is already used by %s", config.Id, script.Name, preset.Path())
+		}
+		if preset := core.GetPresetTargetWithPath(script.PathPrefix + "/" + config.Name); preset != nil {
+			return fmt.Errorf("preset target %s already exists", preset.Path())
+		}
+
+		for phaseIdx, phase := range scriptedTarget.Phases {
+			if (phaseIdx > 0) && (phase.StartTime <= 0) && (phase.StartHealthPercent <= 0) {
+				return fmt.Errorf("phase %d of %s needs a start time or health percent", phaseIdx+1, config.Name)
+			}
+
+			for _, idx := range slices.Concat(phase.EnableTargets, phase.DisableTargets) {
+				if (idx < 0) || (int(idx) >= len(script.Targets)) {
+					return fmt.Errorf("phase %d of %s references invalid target index %d", phaseIdx+1, config.Name, idx)
+				}
+			}
+
+			for _, spell := range phase.Spells {
+				if spell.SpellId <= 0 {
+					return fmt.Errorf("phase %d of %s has a spell without a spell id", phaseIdx+1, config.Name)
+				}
+				if spell.Interruptible && (spell.CastTime <= 0) {
+					return fmt.Errorf("interruptible spell %d of %s needs a cast time", spell.SpellId, config.Name)
+				}
+				if (spell.DamageReduction < 0) || (spell.DamageReduction >= 1) {
+					return fmt.Errorf("spell %d of %s has a damage reduction outside of [0, 1)", spell.SpellId, config.Name)
+				}
+				if (spell.DamageReduction > 0) && (spell.BuffDuration <= 0) {
+					return fmt.Errorf("spell %d of %s has a damage reduction without a buff duration", spell.SpellId, config.Name)
+				}
+			}
+
+			for _, movement := range phase.Movements {
+				if movement.Duration <= 0 {
+					return fmt.Errorf("phase %d of %s has a movement window without a duration", phaseIdx+1, config.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package scripted
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Generic TargetAI which runs the phases of a ScriptedTarget.
+type ScriptedAI struct {
+	Target *core.Target
+
+	script *proto.ScriptedTarget
+	phases []*scriptedPhase
+
+	// Dynamic state, cleared on reset
+	curPhase      int
+	phaseStart    time.Duration
+	phaseActions  []*core.PendingAction
+	nextSpellTime []time.Duration
+}
+
+type scriptedPhase struct {
+	config *proto.EncounterPhase
+	spells []*scriptedSpell
+}
+
+type scriptedSpell struct {
+	config *proto.ScriptedSpell
+	spell  *core.Spell
+}
+
+func NewScriptedAI(script *proto.ScriptedTarget) core.AIFactory {
+	return func() core.TargetAI {
+		return &ScriptedAI{
+			script: script,
+		}
+	}
+}
+
+func (ai *ScriptedAI) Initialize(target *core.Target, _ *proto.Target) {
+	ai.Target = target
+
+	ai.phases = make([]*scriptedPhase, len(ai.script.Phases))
+	for phaseIdx, phaseConfig := range ai.script.Phases {
+		phase := &scriptedPhase{
+			config: phaseConfig,
+		}
+
+		for _, spellConfig := range phaseConfig.Spells {
+			phase.spells = append(phase.spells, &scriptedSpell{
+				config: spellConfig,
+				spell:  ai.registerSpell(spellConfig, int32(phaseIdx)),
+			})
+		}
+
+		ai.phases[phaseIdx] = phase
+	}
+}
+
+func (ai *ScriptedAI) registerSpell(config *proto.ScriptedSpell, phaseIdx int32) *core.Spell {
+	baseDamage := config.Damage
+	damageSpread := config.Damage * config.DamageSpread
+	hitsRaid := config.Target == proto.ScriptedSpell_Raid
+
+	// Target rotations don't complete hardcasts themselves, so the GCD has
+	// to outlast the cast for the cast to land.
+	castTime := core.DurationFromSeconds(config.CastTime)
+
+	castConfig := core.CastConfig{
+		DefaultCast: core.Cast{
+			GCD:      max(core.BossGCD, castTime+time.Millisecond),
+			CastTime: castTime,
+		},
+
+		IgnoreHaste: true,
+	}
+	if config.Cooldown > 0 {
+		castConfig.CD = core.Cooldown{
+			Timer:    ai.Target.NewTimer(),
+			Duration: core.DurationFromSeconds(config.Cooldown),
+		}
+	}
+
+	// Phases are used as the tag so that the same boss ability can be
+	// scripted with different damage in different phases.
+	actionID := core.ActionID{SpellID: config.SpellId, Tag: phaseIdx}
+
+	flags := core.SpellFlagAPL
+	if config.Interruptible {
+		flags |= core.SpellFlagInterruptible
+	}
+
+	var buffAura *core.Aura
+	if config.DamageReduction > 0 {
+		damageTakenMultiplier := 1 - config.DamageReduction
+		buffAura = ai.Target.RegisterAura(core.Aura{
+			Label:    "Scripted Buff " + actionID.String(),
+			ActionID: actionID,
+			Duration: core.DurationFromSeconds(config.BuffDuration),
+			OnGain: func(aura *core.Aura, sim *core.Simulation) {
+				aura.Unit.PseudoStats.DamageTakenMultiplier *= damageTakenMultiplier
+			},
+			OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+				aura.Unit.PseudoStats.DamageTakenMultiplier /= damageTakenMultiplier
+			},
+		})
+	}
+
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         actionID,
+		SpellSchool:      core.SpellSchoolFromProto(config.School),
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            flags,
+		DamageMultiplier: 1,
+
+		Cast: castConfig,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if buffAura != nil {
+				buffAura.Activate(sim)
+			}
+
+			if baseDamage == 0 {
+				return
+			}
+
+			if !hitsRaid {
+				spell.CalcAndDealDamage(sim, target, baseDamage+damageSpread*sim.RandomFloat("Scripted Spell Damage"), spell.OutcomeAlwaysHit)
+				return
+			}
+
+			for _, unit := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, unit, baseDamage+damageSpread*sim.RandomFloat("Scripted Spell Damage"), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *ScriptedAI) Reset(_ *core.Simulation) {
+	ai.curPhase = -1
+	ai.phaseStart = 0
+	ai.phaseActions = ai.phaseActions[:0]
+}
+
+// Returns the index of the phase the target should currently be in.
+func (ai *ScriptedAI) targetPhase(sim *core.Simulation) int {
+	targetPhase := max(ai.curPhase, 0)
+	bossHealthPercent := sim.GetRemainingDurationPercent() * 100
+
+	for phaseIdx := targetPhase + 1; phaseIdx < len(ai.phases); phaseIdx++ {
+		config := ai.phases[phaseIdx].config
+		timeReached := (config.StartTime > 0) && (sim.CurrentTime >= core.DurationFromSeconds(config.StartTime))
+		healthReached := (config.StartHealthPercent > 0) && (bossHealthPercent <= config.StartHealthPercent)
+
+		if !timeReached && !healthReached {
+			break
+		}
+
+		targetPhase = phaseIdx
+	}
+
+	return targetPhase
+}
+
+func (ai *ScriptedAI) startPhase(sim *core.Simulation, phaseIdx int) {
+	for _, pa := range ai.phaseActions {
+		pa.Cancel(sim)
+	}
+	ai.phaseActions = ai.phaseActions[:0]
+
+	ai.curPhase = phaseIdx
+	ai.phaseStart = sim.CurrentTime
+	phase := ai.phases[phaseIdx]
+
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Entering phase %d (%s)", phaseIdx+1, phase.config.Name)
+	}
+
+	ai.nextSpellTime = ai.nextSpellTime[:0]
+	for _, spell := range phase.spells {
+		ai.nextSpellTime = append(ai.nextSpellTime, ai.phaseStart+core.DurationFromSeconds(spell.config.InitialDelay))
+	}
+
+	targets := sim.Encounter.AllTargetUnits
+	for _, targetIdx := range phase.config.EnableTargets {
+		sim.EnableTargetUnit(targets[targetIdx])
+	}
+	for _, targetIdx := range phase.config.DisableTargets {
+		sim.DisableTargetUnit(targets[targetIdx], true)
+	}
+
+	for _, movement := range phase.config.Movements {
+		ai.scheduleMovement(sim, movement)
+	}
+}
+
+func (ai *ScriptedAI) scheduleMovement(sim *core.Simulation, config *proto.ScriptedMovement) {
+	moveDuration := core.DurationFromSeconds(config.Duration)
+	interval := core.DurationFromSeconds(config.Interval)
+
+	pa := core.NewDelayedAction(core.DelayedActionOptions{
+		DoAt:     ai.phaseStart + core.DurationFromSeconds(config.Start),
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			moveRaid(sim, moveDuration)
+
+			if interval > 0 {
+				ai.phaseActions = append(ai.phaseActions, core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+					Period:   interval,
+					Priority: core.ActionPriorityDOT,
+
+					OnAction: func(sim *core.Simulation) {
+						moveRaid(sim, moveDuration)
+					},
+				}))
+			}
+		},
+	})
+
+	sim.AddPendingAction(pa)
+	ai.phaseActions = append(ai.phaseActions, pa)
+}
+
+// Forces every player to move for the given duration. Players in the middle
+// of a cast which can't be moved through finish their cast first.
+func moveRaid(sim *core.Simulation, duration time.Duration) {
+	for _, player := range sim.Raid.AllPlayerUnits {
+		if player.Hardcast.Expires > sim.CurrentTime && !player.Hardcast.CanMove {
+			pa := sim.GetConsumedPendingActionFromPool()
+			pa.NextActionAt = player.Hardcast.Expires
+			// Runs ahead of the player's own actions at the end of the cast, which
+			// use ActionPriorityGCD, so it is already moving when its rotation picks
+			// the next spell. Same priority as MovementAI uses.
+			pa.Priority = core.ActionPriorityPrePull + 1
+
+			pa.OnAction = func(sim *core.Simulation) {
+				player.MoveDuration(duration, sim)
+			}
+
+			sim.AddPendingAction(pa)
+		} else {
+			player.MoveDuration(duration, sim)
+		}
+	}
+}
+
+func (ai *ScriptedAI) spellTarget(sim *core.Simulation, config *proto.ScriptedSpell) *core.Unit {
+	players := sim.Raid.AllPlayerUnits
+
+	if config.Target == proto.ScriptedSpell_RandomRaidMember {
+		idx := int(sim.RandomFloat("Scripted Spell Target") * float64(len(players)))
+		return players[min(idx, len(players)-1)]
+	}
+
+	if ai.Target.CurrentTarget != nil {
+		return ai.Target.CurrentTarget
+	}
+
+	// For individual non tank sims we still want abilities to work
+	return players[0]
+}
+
+func (ai *ScriptedAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if ai.Target.Hardcast.Expires > sim.CurrentTime {
+		return
+	}
+
+	if len(ai.phases) == 0 {
+		ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+		return
+	}
+
+	if phaseIdx := ai.targetPhase(sim); phaseIdx != ai.curPhase {
+		ai.startPhase(sim, phaseIdx)
+	}
+
+	// Re-check the phase gates at least once per boss GCD.
+	waitUntil := sim.CurrentTime + core.BossGCD
+
+	for spellIdx, spell := range ai.phases[ai.curPhase].spells {
+		readyAt := max(ai.nextSpellTime[spellIdx], spell.spell.ReadyAt())
+		if readyAt > sim.CurrentTime {
+			waitUntil = min(waitUntil, readyAt)
+			continue
+		}
+
+		target := ai.spellTarget(sim, spell.config)
+		if spell.spell.CanCast(sim, target) {
+			spell.spell.Cast(sim, target)
+			return
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, waitUntil)
+}
@@ -0,0 +1,192 @@
+package scripted
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/priest/holy"
+	"github.com/wowsims/mop/sim/priest/shadow"
+)
+
+func init() {
+	holy.RegisterHolyPriest()
+	shadow.RegisterShadowPriest()
+
+	script, err := LoadEncounterScript("testdata/example.json")
+	if err != nil {
+		panic(err)
+	}
+	if err := AddScriptedEncounter(script); err != nil {
+		panic(err)
+	}
+}
+
+func TestValidateEncounterScript(t *testing.T) {
+	script, err := LoadEncounterScript("testdata/example.json")
+	if err != nil {
+		t.Fatalf("Failed to load script: %s", err)
+	}
+
+	if err := ValidateEncounterScript(script); err == nil {
+		t.Fatalf("Expected an error when registering the same target ids twice")
+	}
+
+	script.Targets[0].Config.Id = 990003
+	script.Targets[0].Config.Name = "Other Boss"
+	script.Targets[1].Config.Id = 990004
+	script.Targets[1].Config.Name = "Other Add"
+	script.Targets[0].Phases[1].EnableTargets = []int32{2}
+	if err := ValidateEncounterScript(script); err == nil {
+		t.Fatalf("Expected an error for an out of range target index")
+	}
+}
+
+func scriptedTestTargets(t *testing.T) []*proto.Target {
+	var encounterTargets []*proto.Target
+	for _, preset := range core.PresetEncounters {
+		if preset.Path == "Scripted Test/Scripted Test Boss" {
+			for _, presetTarget := range preset.Targets {
+				encounterTargets = append(encounterTargets, presetTarget.Target)
+			}
+		}
+	}
+	if len(encounterTargets) != 2 {
+		t.Fatalf("Expected the scripted encounter to be registered with 2 targets, got %d", len(encounterTargets))
+	}
+	return encounterTargets
+}
+
+func TestScriptedEncounter(t *testing.T) {
+	encounterTargets := scriptedTestTargets(t)
+
+	player := &proto.Player{
+		Name:      "Healer",
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassPriest,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_HolyPriest{HolyPriest: &proto.HolyPriest{
+			Options: &proto.HolyPriest_Options{ClassOptions: &proto.PriestOptions{}},
+		}},
+		Buffs:    &proto.IndividualBuffs{},
+		Rotation: &proto.APLRotation{},
+	}
+
+	result := core.RunRaidSim(&proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  encounterTargets,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 5,
+			RandomSeed: 101,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	casts := map[int32]int32{}
+	for _, action := range result.EncounterMetrics.Targets[0].Actions {
+		for _, targetMetrics := range action.Targets {
+			casts[action.Id.GetSpellId()] += targetMetrics.Casts
+		}
+	}
+
+	if casts[117218] == 0 {
+		t.Fatalf("Expected the phase 1 spell to be cast")
+	}
+	if casts[122118] == 0 {
+		t.Fatalf("Expected the phase 2 spell to be cast")
+	}
+}
+
+// Runs a Shadow Priest who interrupts whenever possible against the scripted
+// encounter, returning the metrics of the priest and the boss.
+func runScriptedInterrupts(t *testing.T, hitRating float64) (*proto.UnitMetrics, *proto.UnitMetrics) {
+	rotation := core.GetAplRotation("../../../ui/priest/shadow/apls", "default").Rotation
+	interrupt, err := core.APLRotationFromText("action interrupt()")
+	if err != nil {
+		t.Fatalf("Failed to parse rotation: %s", err)
+	}
+	rotation.PriorityList = append(interrupt.PriorityList, rotation.PriorityList...)
+
+	bonusStats := stats.Stats{}
+	bonusStats[stats.HitRating] = hitRating
+
+	player := &proto.Player{
+		Name:      "Shadow",
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassPriest,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_ShadowPriest{ShadowPriest: &proto.ShadowPriest{
+			Options: &proto.ShadowPriest_Options{ClassOptions: &proto.PriestOptions{}},
+		}},
+		Rotation:   rotation,
+		Buffs:      &proto.IndividualBuffs{},
+		BonusStats: &proto.UnitStats{Stats: bonusStats.ToProtoArray()},
+	}
+
+	result := core.RunRaidSim(&proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  scriptedTestTargets(t),
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			RandomSeed: 101,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	return result.RaidMetrics.Parties[0].Players[0], result.EncounterMetrics.Targets[0]
+}
+
+func TestScriptedInterrupts(t *testing.T) {
+	// Silence is on a 45s cooldown, so only the first cast is interrupted
+	// and the boss gets its buff from the later ones.
+	_, boss := runScriptedInterrupts(t, 100000)
+	if boss.CastsInterruptedAvg != 1 {
+		t.Fatalf("Expected 1 interrupt per iteration, got %f", boss.CastsInterruptedAvg)
+	}
+	if boss.InterruptibleCastsAvg <= boss.CastsInterruptedAvg {
+		t.Fatalf("Expected more interruptible casts than interrupts, got %f", boss.InterruptibleCastsAvg)
+	}
+
+	buffUptime := 0.0
+	for _, aura := range boss.Auras {
+		if aura.Id.GetSpellId() == 122118 {
+			buffUptime = aura.UptimeSecondsAvg
+		}
+	}
+	if buffUptime == 0 {
+		t.Fatalf("Expected the boss to gain its buff from uninterrupted casts")
+	}
+}
+
+func TestScriptedInterruptsMiss(t *testing.T) {
+	// Without hit rating Silence misses the boss some of the time, which
+	// doesn't interrupt the cast but still triggers the cooldown.
+	priest, boss := runScriptedInterrupts(t, 0)
+
+	var silenceCasts, silenceMisses int32
+	for _, action := range priest.Actions {
+		if action.Id.GetSpellId() == 15487 {
+			for _, targetMetrics := range action.Targets {
+				silenceCasts += targetMetrics.Casts
+				silenceMisses += targetMetrics.Misses
+			}
+		}
+	}
+	if silenceMisses == 0 || silenceMisses == silenceCasts {
+		t.Fatalf("Expected some of the %d Silence casts to miss, got %d misses", silenceCasts, silenceMisses)
+	}
+	if expected := float64(silenceCasts-silenceMisses) / 20; !core.WithinToleranceFloat64(expected, boss.CastsInterruptedAvg, 0.0001) {
+		t.Fatalf("Expected %f interrupts per iteration from the Silence hits, got %f", expected, boss.CastsInterruptedAvg)
+	}
+}
@@ -0,0 +1,31 @@
+package hunter
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (hunter *Hunter) registerCounterShotSpell() {
+	hunter.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 147362},
+		SpellSchool: core.SpellSchoolPhysical,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    40,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    hunter.NewTimer(),
+				Duration: time.Second * 24,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeRangedHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*3)
+			}
+		},
+	})
+}
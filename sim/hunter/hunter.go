@@ -146,6 +146,7 @@ func (hunter *Hunter) RegisterSpells() {
 	hunter.registerMultiShotSpell()
 	hunter.registerExplosiveTrapSpell()
 	hunter.registerCobraShotSpell()
+	hunter.registerCounterShotSpell()
 	hunter.registerRapidFireCD()
 	hunter.registerSilencingShotSpell()
 	hunter.registerHuntersMarkSpell()
@@ -213,6 +214,33 @@ func (hunter *Hunter) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	//	}
 }
 
+func (hunter *Hunter) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	if hunter.Pet == nil {
+		return
+	}
+	petConfig := DefaultPetConfigs[hunter.Options.PetType]
+	for _, ability := range []PetAbilityType{petConfig.SpecialAbility, petConfig.ExoticAbility} {
+		switch ability {
+		case Gore, Ravage, StampedeDebuff, AcidSpitDebuff:
+			debuffs.PhysicalVulnerability = true
+		case DemoralizingRoar, DemoralizingScreech:
+			debuffs.WeakenedBlows = true
+		case DustCloud, TearArmor:
+			debuffs.WeakenedArmor = true
+		case FireBreathDebuff:
+			debuffs.FireBreath = true
+		case LightningBreath:
+			debuffs.LightningBreath = true
+		case SporeCloud:
+			debuffs.SporeCloud = true
+		case LavaBreath:
+			debuffs.LavaBreath = true
+		case MonstrousBite:
+			debuffs.MortalWounds = true
+		}
+	}
+}
+
 func (hunter *Hunter) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
@@ -11,7 +11,7 @@ func (hunter *Hunter) registerSilencingShotSpell() {
 		ActionID:    core.ActionID{SpellID: 34490},
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskRangedSpecial,
-		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagAPL | core.SpellFlagReadinessTrinket,
+		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagAPL | core.SpellFlagReadinessTrinket | core.SpellFlagInterrupt,
 		MinRange:    5,
 		MaxRange:    40,
 		FocusCost: core.FocusCostOptions{
@@ -31,6 +31,10 @@ func (hunter *Hunter) registerSilencingShotSpell() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			focusMetics := hunter.NewFocusMetrics(core.ActionID{SpellID: 34490})
 			hunter.AddFocus(sim, 10, focusMetics)
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeRangedHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*3)
+			}
 		},
 	})
 }
@@ -0,0 +1,384 @@
+// Package env wraps the sim in a step-based environment, so that external
+// agents (e.g. reinforcement learning policies) can choose the actions of one
+// player instead of its APL.
+package env
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Passing WaitAction to Step lets time pass without casting anything.
+const WaitAction = -1
+
+const DefaultWaitDuration = time.Millisecond * 100
+
+type RewardType int
+
+const (
+	// Damage dealt by the player and its pets.
+	RewardDamage RewardType = iota
+	// Healing done by the player and its pets, excluding overhealing.
+	RewardHealing
+)
+
+type Options struct {
+	// Index of the controlled player within the raid.
+	PartyIndex  int
+	PlayerIndex int
+
+	Reward RewardType
+
+	// How long WaitAction and invalid actions pause the player for.
+	WaitDuration time.Duration
+}
+
+type Resource struct {
+	Name    string
+	Current float64
+	Max     float64
+}
+
+type SpellState struct {
+	ActionID    core.ActionID
+	CanCast     bool
+	TimeToReady float64
+}
+
+type AuraState struct {
+	ActionID  core.ActionID
+	Label     string
+	Active    bool
+	Remaining float64
+	Stacks    int32
+}
+
+// Snapshot of the controlled player's state. The layout of every list is
+// fixed for the lifetime of an Interactive, so Vector() can be fed directly to
+// a policy.
+type Observation struct {
+	CurrentTime         float64
+	RemainingDuration   float64
+	TargetHealthPercent float64
+	GCDRemaining        float64
+
+	Resources   []Resource
+	Spells      []SpellState
+	Auras       []AuraState
+	TargetAuras []AuraState
+}
+
+type StepResult struct {
+	Observation Observation
+	Reward      float64
+	Done        bool
+
+	// False if the action could not be cast, in which case the player waited instead.
+	ActionValid bool
+}
+
+type Interactive struct {
+	options Options
+
+	sim       *core.Simulation
+	character *core.Character
+
+	// Spells the player can choose from, indexed by action.
+	actions []*core.Spell
+
+	lastReward  float64
+	totalReward float64
+	started     bool
+	done        bool
+}
+
+// Creates an environment for the player at options.PartyIndex/PlayerIndex in
+// the request. Spec factories must already be registered, e.g. with
+// sim.RegisterAll(). Reset must be called before the first Step.
+func NewInteractive(request *proto.RaidSimRequest, options Options) (env *Interactive, err error) {
+	if request.Raid == nil || options.PartyIndex < 0 || options.PartyIndex >= len(request.Raid.Parties) {
+		return nil, fmt.Errorf("invalid party index %d", options.PartyIndex)
+	}
+	if party := request.Raid.Parties[options.PartyIndex]; options.PlayerIndex < 0 || options.PlayerIndex >= len(party.Players) {
+		return nil, fmt.Errorf("invalid player index %d", options.PlayerIndex)
+	}
+	if options.WaitDuration <= 0 {
+		options.WaitDuration = DefaultWaitDuration
+	}
+
+	request = googleProto.Clone(request).(*proto.RaidSimRequest)
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{}
+	}
+	request.SimOptions.Interactive = true
+	request.SimOptions.Iterations = 1
+
+	// Sim construction reports invalid settings by panicking.
+	defer func() {
+		if r := recover(); r != nil {
+			env = nil
+			err = fmt.Errorf("failed to create sim: %v", r)
+		}
+	}()
+
+	sim := core.NewSim(request, simsignals.CreateSignals())
+	party := sim.Raid.Parties[options.PartyIndex]
+	if options.PlayerIndex >= len(party.Players) {
+		return nil, fmt.Errorf("player %d in party %d is not simmed", options.PlayerIndex, options.PartyIndex)
+	}
+	character := party.Players[options.PlayerIndex].GetCharacter()
+	sim.InteractiveUnit = &character.Unit
+
+	env = &Interactive{
+		options:   options,
+		sim:       sim,
+		character: character,
+	}
+	for _, spell := range character.Spellbook {
+		if spell.Flags.Matches(core.SpellFlagAPL) {
+			env.actions = append(env.actions, spell)
+		}
+	}
+	return env, nil
+}
+
+// Action space of the environment. Action i casts Actions()[i].
+func (env *Interactive) Actions() []core.ActionID {
+	actionIDs := make([]core.ActionID, len(env.actions))
+	for i, spell := range env.actions {
+		actionIDs[i] = spell.ActionID
+	}
+	return actionIDs
+}
+
+func (env *Interactive) Sim() *core.Simulation {
+	return env.sim
+}
+
+func (env *Interactive) Character() *core.Character {
+	return env.character
+}
+
+// Starts a new episode using the given RNG seed and runs it until the player
+// first needs to act.
+func (env *Interactive) Reset(seed int64) Observation {
+	// Episodes which are abandoned early still need to be cleaned up.
+	if env.started && !env.done {
+		env.sim.Cleanup()
+	}
+	env.started = true
+
+	env.sim.Reseed(seed)
+	env.sim.Reset()
+	env.sim.PrePull()
+	env.sim.NeedsInput = false
+
+	env.lastReward = 0
+	env.totalReward = 0
+	env.done = false
+	env.advance()
+
+	return env.Observation()
+}
+
+// Performs an action and runs the sim until the player needs to act again.
+// Actions which can't be cast (or WaitAction) make the player wait for
+// Options.WaitDuration instead.
+func (env *Interactive) Step(action int) StepResult {
+	if env.done {
+		return StepResult{Observation: env.Observation(), Done: true}
+	}
+
+	sim := env.sim
+	target := env.character.CurrentTarget
+	actionValid := false
+	offGCD := false
+
+	if action >= 0 && action < len(env.actions) {
+		spell := env.actions[action]
+		if spell.CanCast(sim, target) && spell.Cast(sim, target) {
+			actionValid = true
+			offGCD = spell.CurCast.GCD == 0 && spell.CurCast.CastTime == 0
+		}
+	} else if action == WaitAction {
+		actionValid = true
+	}
+
+	if !actionValid || action == WaitAction {
+		env.character.WaitUntil(sim, sim.CurrentTime+env.options.WaitDuration)
+		sim.NeedsInput = false
+	} else if !offGCD {
+		sim.NeedsInput = false
+	}
+
+	// Off GCD actions can be chained at the same timestamp, so only advance
+	// once the player has used its GCD or chosen to wait.
+	env.advance()
+
+	reward := env.currentReward()
+	stepReward := reward - env.lastReward
+	env.lastReward = reward
+
+	return StepResult{
+		Observation: env.Observation(),
+		Reward:      stepReward,
+		Done:        env.done,
+		ActionValid: actionValid,
+	}
+}
+
+func (env *Interactive) advance() {
+	for !env.sim.NeedsInput {
+		if finished := env.sim.Step(); finished {
+			env.totalReward = env.currentReward()
+			env.sim.Cleanup()
+			env.done = true
+			return
+		}
+	}
+}
+
+func (env *Interactive) currentReward() float64 {
+	if env.done {
+		return env.totalReward
+	}
+
+	var reward float64
+	addUnitReward := func(unit *core.Unit) {
+		damage, healing := unit.IterationDamageAndHealing()
+		if env.options.Reward == RewardHealing {
+			reward += healing
+		} else {
+			reward += damage
+		}
+	}
+
+	addUnitReward(&env.character.Unit)
+	for _, pet := range env.character.PetAgents {
+		addUnitReward(&pet.GetPet().Unit)
+	}
+	return reward
+}
+
+// Total reward of the current episode per second of combat, e.g. the DPS of
+// the player, which can be compared with the DPS of an APL.
+func (env *Interactive) RewardPerSecond() float64 {
+	if env.sim.CurrentTime <= 0 {
+		return 0
+	}
+	return env.currentReward() / env.sim.CurrentTime.Seconds()
+}
+
+func (env *Interactive) Done() bool {
+	return env.done
+}
+
+func (env *Interactive) Observation() Observation {
+	sim := env.sim
+	character := env.character
+	remaining := sim.GetRemainingDuration()
+
+	obs := Observation{
+		CurrentTime:         sim.CurrentTime.Seconds(),
+		RemainingDuration:   remaining.Seconds(),
+		TargetHealthPercent: sim.GetRemainingDurationPercent() * 100,
+		GCDRemaining:        max(0, character.GCD.TimeToReady(sim).Seconds()),
+		Resources:           resources(&character.Unit),
+	}
+
+	for _, spell := range env.actions {
+		obs.Spells = append(obs.Spells, SpellState{
+			ActionID:    spell.ActionID,
+			CanCast:     !env.done && spell.CanCast(sim, character.CurrentTarget),
+			TimeToReady: spell.TimeToReady(sim).Seconds(),
+		})
+	}
+
+	obs.Auras = auraStates(sim, character.GetAuras(), remaining)
+	if character.CurrentTarget != nil {
+		obs.TargetAuras = auraStates(sim, character.CurrentTarget.GetAuras(), remaining)
+	}
+	return obs
+}
+
+func auraStates(sim *core.Simulation, auras []*core.Aura, remaining time.Duration) []AuraState {
+	states := make([]AuraState, len(auras))
+	for i, aura := range auras {
+		states[i] = AuraState{
+			ActionID: aura.ActionID,
+			Label:    aura.Label,
+			Active:   aura.IsActive(),
+		}
+		if aura.IsActive() {
+			// Permanent auras last until the end of the fight.
+			states[i].Remaining = min(aura.RemainingDuration(sim), remaining).Seconds()
+			states[i].Stacks = aura.GetStacks()
+		}
+	}
+	return states
+}
+
+func resources(unit *core.Unit) []Resource {
+	var bars []Resource
+
+	if unit.HasHealthBar() {
+		bars = append(bars, Resource{Name: "Health", Current: unit.CurrentHealth(), Max: unit.MaxHealth()})
+	}
+	if unit.HasManaBar() {
+		bars = append(bars, Resource{Name: "Mana", Current: unit.CurrentMana(), Max: unit.MaxMana()})
+	}
+	if unit.HasRageBar() {
+		bars = append(bars, Resource{Name: "Rage", Current: unit.CurrentRage(), Max: unit.MaximumRage()})
+	}
+	if unit.HasEnergyBar() {
+		bars = append(bars,
+			Resource{Name: "Energy", Current: unit.CurrentEnergy(), Max: unit.MaximumEnergy()},
+			Resource{Name: "Combo Points", Current: float64(unit.ComboPoints()), Max: float64(unit.MaxComboPoints())},
+		)
+	}
+	if unit.HasFocusBar() {
+		bars = append(bars, Resource{Name: "Focus", Current: unit.CurrentFocus(), Max: unit.MaximumFocus()})
+	}
+	if unit.HasRunicPowerBar() {
+		bars = append(bars,
+			Resource{Name: "Runic Power", Current: unit.CurrentRunicPower(), Max: unit.MaximumRunicPower()},
+			Resource{Name: "Blood Runes", Current: float64(unit.CurrentBloodRunes()), Max: 2},
+			Resource{Name: "Frost Runes", Current: float64(unit.CurrentFrostRunes()), Max: 2},
+			Resource{Name: "Unholy Runes", Current: float64(unit.CurrentUnholyRunes()), Max: 2},
+			Resource{Name: "Death Runes", Current: float64(unit.CurrentDeathRunes()), Max: 6},
+		)
+	}
+	if bar := unit.GetSecondaryResourceBar(); bar != nil {
+		resource := Resource{Name: "Secondary", Current: float64(bar.Value())}
+		if bounded, ok := bar.(interface{ Max() int32 }); ok {
+			resource.Max = float64(bounded.Max())
+		}
+		bars = append(bars, resource)
+	}
+
+	return bars
+}
+
+// Flattens the observation into a fixed length feature vector.
+func (obs Observation) Vector() []float64 {
+	vector := []float64{obs.CurrentTime, obs.RemainingDuration, obs.TargetHealthPercent, obs.GCDRemaining}
+
+	for _, resource := range obs.Resources {
+		vector = append(vector, resource.Current)
+	}
+	for _, spell := range obs.Spells {
+		vector = append(vector, core.TernaryFloat64(spell.CanCast, 1, 0), spell.TimeToReady)
+	}
+	for _, auras := range [][]AuraState{obs.Auras, obs.TargetAuras} {
+		for _, aura := range auras {
+			vector = append(vector, core.TernaryFloat64(aura.Active, 1, 0), aura.Remaining, float64(aura.Stacks))
+		}
+	}
+
+	return vector
+}
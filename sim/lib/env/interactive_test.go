@@ -0,0 +1,110 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/priest/shadow"
+)
+
+func init() {
+	shadow.RegisterShadowPriest()
+}
+
+func newTestEnv(t *testing.T) *Interactive {
+	player := &proto.Player{
+		Name:      "Agent",
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassPriest,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_ShadowPriest{ShadowPriest: &proto.ShadowPriest{
+			Options: &proto.ShadowPriest_Options{ClassOptions: &proto.PriestOptions{}},
+		}},
+		Buffs: &proto.IndividualBuffs{},
+	}
+
+	env, err := NewInteractive(&proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 30,
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+		},
+	}, Options{})
+	if err != nil {
+		t.Fatalf("Failed to create environment: %s", err)
+	}
+	return env
+}
+
+// Always casts the first castable spell, or waits.
+func runGreedyEpisode(env *Interactive, seed int64) (float64, int) {
+	obs := env.Reset(seed)
+	totalReward := 0.0
+	steps := 0
+
+	for !env.Done() {
+		action := WaitAction
+		for i, spell := range obs.Spells {
+			if spell.CanCast {
+				action = i
+				break
+			}
+		}
+
+		result := env.Step(action)
+		totalReward += result.Reward
+		obs = result.Observation
+		steps++
+	}
+
+	return totalReward, steps
+}
+
+func TestInteractiveEpisode(t *testing.T) {
+	env := newTestEnv(t)
+	if len(env.Actions()) == 0 {
+		t.Fatalf("Expected castable spells in the action space")
+	}
+
+	obs := env.Reset(1)
+	hasMana := false
+	for _, resource := range obs.Resources {
+		hasMana = hasMana || (resource.Name == "Mana" && resource.Current > 0)
+	}
+	if !hasMana {
+		t.Fatalf("Expected a mana bar in the observation, got %v", obs.Resources)
+	}
+	if len(obs.Vector()) != len(env.Observation().Vector()) {
+		t.Fatalf("Expected the observation vector to have a fixed length")
+	}
+
+	totalReward, steps := runGreedyEpisode(env, 1)
+	if totalReward <= 0 || steps == 0 {
+		t.Fatalf("Expected the episode to deal damage, got %0.0f over %d steps", totalReward, steps)
+	}
+	if !core.WithinToleranceFloat64(totalReward/30, env.RewardPerSecond(), 0.001) {
+		t.Fatalf("Expected step rewards to add up to the episode DPS, got %0.2f and %0.2f", totalReward/30, env.RewardPerSecond())
+	}
+
+	// Same seed, same actions, same result.
+	repeatReward, repeatSteps := runGreedyEpisode(env, 1)
+	if repeatReward != totalReward || repeatSteps != steps {
+		t.Fatalf("Expected a reset with the same seed to replay the episode, got %0.0f/%d vs %0.0f/%d", repeatReward, repeatSteps, totalReward, steps)
+	}
+}
+
+func TestInteractiveInvalidAction(t *testing.T) {
+	env := newTestEnv(t)
+	start := env.Reset(1).CurrentTime
+
+	result := env.Step(len(env.Actions()))
+	if result.ActionValid {
+		t.Fatalf("Expected an out of range action to be invalid")
+	}
+	if result.Observation.CurrentTime <= start {
+		t.Fatalf("Expected an invalid action to let time pass")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"compress/zlib"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"log"
 	"unsafe"
 
@@ -14,16 +15,12 @@ import (
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/lib/env"
 	"google.golang.org/protobuf/encoding/protojson"
 	goproto "google.golang.org/protobuf/proto"
 )
 
-var _default_rsr = proto.RaidSimRequest{
-	Raid:       &proto.Raid{},
-	Encounter:  &proto.Encounter{},
-	SimOptions: &proto.SimOptions{},
-}
-var _active_sim = core.NewSim(&_default_rsr, simsignals.Signals{})
+var _active_env *env.Interactive
 var _active_seed int64 = 1
 var _aura_labels = []string{}
 var _target_aura_labels = []string{}
@@ -120,6 +117,11 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 			SetId:            item.SetID,
 			ScalingOptions:   item.ScalingOptions,
 			ItemEffect:       item.ItemEffect,
+
+			Unique:             item.Unique,
+			LimitCategory:      item.LimitCategory,
+			ClassAllowlist:     item.ClassAllowlist,
+			RequiredProfession: item.RequiredProfession,
 		}
 	}
 	for i, enchantId := range eids {
@@ -130,6 +132,9 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 			EnchantEffect: enchant.EnchantEffect,
 			Name:          enchant.Name,
 			Type:          enchant.Type,
+
+			ClassAllowlist:     enchant.ClassAllowlist,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 	for i, gemId := range gids {
@@ -140,6 +145,8 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 			Color:                   gem.Color,
 			Stats:                   gem.Stats[:],
 			DisabledInChallengeMode: gem.DisabledInChallengeMode,
+			Unique:                  gem.Unique,
+			RequiredProfession:      gem.RequiredProfession,
 		}
 	}
 	out, err := protojson.Marshal(simDB)
@@ -158,39 +165,85 @@ func new(json *C.char) {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
 	sim.RegisterAll()
-	_active_sim = core.NewSim(input, simsignals.Signals{})
-	_active_sim.Reseed(_active_seed)
+	_active_env, err = env.NewInteractive(input, env.Options{})
+	if err != nil {
+		log.Fatalf("failed to create environment: %s", err)
+	}
+	_active_env.Reset(_active_seed)
 	_active_seed += 1
-	_active_sim.Reset()
-	_active_sim.PrePull()
+}
+
+//export newEnv
+func newEnv(json *C.char, partyIndex int, playerIndex int, rewardType int) *C.char {
+	input := &proto.RaidSimRequest{}
+	jsonString := C.GoString(json)
+	err := protojson.Unmarshal([]byte(jsonString), input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+	sim.RegisterAll()
+	_active_env, err = env.NewInteractive(input, env.Options{
+		PartyIndex:  partyIndex,
+		PlayerIndex: playerIndex,
+		Reward:      env.RewardType(rewardType),
+	})
+	if err != nil {
+		log.Fatalf("failed to create environment: %s", err)
+	}
+	return marshalJSON(_active_env.Actions())
+}
+
+//export resetEnv
+func resetEnv(seed int64) *C.char {
+	if !hasActiveEnv() {
+		return marshalError(errNoActiveEnv)
+	}
+	return marshalJSON(_active_env.Reset(seed))
+}
+
+//export stepEnv
+func stepEnv(action int) *C.char {
+	if !hasActiveEnv() {
+		return marshalError(errNoActiveEnv)
+	}
+	return marshalJSON(_active_env.Step(action))
+}
+
+//export getObservation
+func getObservation() *C.char {
+	if !hasActiveEnv() {
+		return marshalError(errNoActiveEnv)
+	}
+	return marshalJSON(_active_env.Observation())
+}
+
+//export getRewardPerSecond
+func getRewardPerSecond() float64 {
+	if !hasActiveEnv() {
+		return 0
+	}
+	return _active_env.RewardPerSecond()
 }
 
 //export trySpell
 func trySpell(act int) bool {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spells := player.GetCharacter().Spellbook
+	if !hasActiveEnv() {
+		return false
+	}
+	activeSim := _active_env.Sim()
+	character := _active_env.Character()
+	spells := character.Spellbook
 	if act >= len(spells) || act < 0 {
 		return false
 	}
 	spell := spells[act]
-	target := player.GetCharacter().CurrentTarget
+	target := character.CurrentTarget
 	casted := false
 
-	// FIXME : This is a hack to allow Heroic strike to work
-	if spell.ActionID.SpellID == 47450 {
-		aura := player.GetCharacter().GetAura("HS Queue Aura")
-		if aura.IsActive() {
-			return false
-		}
-		aura.Activate(_active_sim)
-		return true
-	}
-	// End of Heroic strike hack
-
-	if spell.CanCast(_active_sim, target) {
-		casted = spell.Cast(_active_sim, target)
+	if spell.CanCast(activeSim, target) {
+		casted = spell.Cast(activeSim, target)
 		if casted && spell.CurCast.GCD > 0 {
-			_active_sim.NeedsInput = false
+			activeSim.NeedsInput = false
 		}
 	}
 	return casted
@@ -203,41 +256,58 @@ func doNothing() bool {
 
 //export getRemainingDuration
 func getRemainingDuration() float64 {
-	return _active_sim.GetRemainingDuration().Seconds()
+	if !hasActiveEnv() {
+		return 0
+	}
+	return _active_env.Sim().GetRemainingDuration().Seconds()
 }
 
 //export getEnergy
 func getEnergy() float64 {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	if !player.GetCharacter().HasEnergyBar() {
+	if !hasActiveEnv() {
+		return 0
+	}
+	character := _active_env.Character()
+	if !character.HasEnergyBar() {
 		return 0.0
 	}
-	return player.GetCharacter().CurrentEnergy()
+	return character.CurrentEnergy()
 }
 
 //export getComboPoints
 func getComboPoints() int {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	if !player.GetCharacter().HasEnergyBar() {
+	if !hasActiveEnv() {
+		return 0
+	}
+	character := _active_env.Character()
+	if !character.HasEnergyBar() {
 		return 0
 	}
-	return int(player.GetCharacter().ComboPoints())
+	return int(character.ComboPoints())
 }
 
 //export getUnitCount
 func getUnitCount() int {
-	return len(_active_sim.AllUnits)
+	if !hasActiveEnv() {
+		return 0
+	}
+	return len(_active_env.Sim().AllUnits)
 }
 
 //export getSpellCount
 func getSpellCount() int {
-	return len(_active_sim.Raid.Parties[0].Players[0].GetCharacter().Spellbook)
+	if !hasActiveEnv() {
+		return 0
+	}
+	return len(_active_env.Character().Spellbook)
 }
 
 //export getSpells
 func getSpells(storage *int32, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spellbook := player.GetCharacter().Spellbook
+	if !hasActiveEnv() {
+		return
+	}
+	spellbook := _active_env.Character().Spellbook
 	spells := unsafe.Slice(storage, n)
 	for i, spell := range spellbook[:n] {
 		if spell.Tag != -1 {
@@ -251,14 +321,16 @@ func getSpells(storage *int32, n int32) {
 
 //export getCooldowns
 func getCooldowns(storage *float64, spellbookIndices *int32, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spellbook := player.GetCharacter().Spellbook
+	if !hasActiveEnv() {
+		return
+	}
+	spellbook := _active_env.Character().Spellbook
 	spells := unsafe.Slice(spellbookIndices, n)
 	cds := unsafe.Slice(storage, n)
 	for i := int32(0); i < n; i++ {
 		spellbookIndex := spells[i]
 		spell := spellbook[spellbookIndex]
-		cds[i] = spell.TimeToReady(_active_sim).Seconds()
+		cds[i] = spell.TimeToReady(_active_env.Sim()).Seconds()
 	}
 }
 
@@ -282,12 +354,15 @@ func registerTargetAuras(strings **C.char) {
 
 //export getAuras
 func getAuras(storage *float64, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	if !hasActiveEnv() {
+		return
+	}
+	character := _active_env.Character()
 	auras := unsafe.Slice(storage, n)
 	for i, label := range _aura_labels {
-		aura := player.GetCharacter().GetAura(label)
+		aura := character.GetAura(label)
 		if aura != nil {
-			auras[i] = aura.RemainingDuration(_active_sim).Seconds()
+			auras[i] = aura.RemainingDuration(_active_env.Sim()).Seconds()
 		} else {
 			auras[i] = 0.0
 		}
@@ -296,13 +371,15 @@ func getAuras(storage *float64, n int32) {
 
 //export getTargetAuras
 func getTargetAuras(storage *float64, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	target := player.GetCharacter().CurrentTarget
+	if !hasActiveEnv() {
+		return
+	}
+	target := _active_env.Character().CurrentTarget
 	auras := unsafe.Slice(storage, n)
 	for i, label := range _target_aura_labels {
 		aura := target.GetAura(label)
 		if aura != nil {
-			auras[i] = aura.RemainingDuration(_active_sim).Seconds()
+			auras[i] = aura.RemainingDuration(_active_env.Sim()).Seconds()
 		} else {
 			auras[i] = 0.0
 		}
@@ -311,8 +388,10 @@ func getTargetAuras(storage *float64, n int32) {
 
 //export getDamageDone
 func getDamageDone() float64 {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spellbook := player.GetCharacter().Spellbook
+	if !hasActiveEnv() {
+		return 0
+	}
+	spellbook := _active_env.Character().Spellbook
 	totalDamage := 0.0
 	for _, spell := range spellbook {
 		for _, metrics := range spell.SpellMetrics {
@@ -324,9 +403,11 @@ func getDamageDone() float64 {
 
 //export getSpellMetrics
 func getSpellMetrics() *C.char {
+	if !hasActiveEnv() {
+		return marshalError(errNoActiveEnv)
+	}
 	all_metrics := make(map[int32][]core.SpellMetrics)
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spellbook := player.GetCharacter().Spellbook
+	spellbook := _active_env.Character().Spellbook
 	for _, spell := range spellbook {
 		spell_id := spell.ActionID.SpellID
 		for _, metrics := range spell.SpellMetrics {
@@ -344,17 +425,50 @@ func getSpellMetrics() *C.char {
 
 //export step
 func step() bool {
-	return _active_sim.Step()
+	if !hasActiveEnv() {
+		return false
+	}
+	return _active_env.Sim().Step()
 }
 
 //export needsInput
 func needsInput() bool {
-	return _active_sim.NeedsInput
+	if !hasActiveEnv() {
+		return false
+	}
+	return _active_env.Sim().NeedsInput
 }
 
 //export cleanup
 func cleanup() {
-	_active_sim.Cleanup()
+	if !hasActiveEnv() {
+		return
+	}
+	_active_env.Sim().Cleanup()
+}
+
+var errNoActiveEnv = errors.New("no active environment, call new or newEnv first")
+
+// Entry points which need an environment bail out with an error instead of
+// crashing the host process when none has been created yet.
+func hasActiveEnv() bool {
+	if _active_env == nil {
+		log.Print(errNoActiveEnv)
+		return false
+	}
+	return true
+}
+
+func marshalError(err error) *C.char {
+	return marshalJSON(map[string]string{"error": err.Error()})
+}
+
+func marshalJSON(v any) *C.char {
+	out, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return C.CString(string(out))
 }
 
 //export FreeCString
@@ -0,0 +1,31 @@
+package mage
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (mage *Mage) registerCounterspellSpell() {
+	mage.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 2139},
+		SpellSchool: core.SpellSchoolArcane,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    40,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    mage.NewTimer(),
+				Duration: time.Second * 24,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMagicHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*6)
+			}
+		},
+	})
+}
@@ -121,6 +121,7 @@ func (mage *Mage) registerSpells() {
 	mage.registerArcaneExplosionSpell()
 	mage.registerBlizzardSpell()
 	mage.registerConeOfColdSpell()
+	mage.registerCounterspellSpell()
 	mage.registerDeepFreezeSpell()
 	mage.registerFlamestrikeSpell()
 	mage.registerIceLanceSpell()
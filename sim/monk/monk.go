@@ -195,6 +195,7 @@ func (monk *Monk) registerSpells() {
 	monk.registerTouchOfDeath()
 	monk.registerCracklingJadeLightning()
 	monk.registerStormEarthAndFire()
+	monk.registerSpearHandStrike()
 
 	// Windwalker
 	// Required to be registered on monk so it can interact with SEF
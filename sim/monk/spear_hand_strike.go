@@ -0,0 +1,31 @@
+package monk
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (monk *Monk) registerSpearHandStrike() {
+	monk.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 116705},
+		SpellSchool: core.SpellSchoolPhysical,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    core.MaxMeleeRange,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    monk.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMeleeSpecialHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*4)
+			}
+		},
+	})
+}
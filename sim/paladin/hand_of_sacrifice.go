@@ -0,0 +1,56 @@
+package paladin
+
+import (
+	"github.com/wowsims/mop/sim/core"
+)
+
+/*
+Places a Hand on a party or raid member, transferring 30% damage taken to the Paladin.
+Lasts 12 sec or until the Paladin has transferred 100% of their maximum health.
+*/
+func (paladin *Paladin) registerHandOfSacrifice() {
+	// The damage transferred from the target.
+	transferSpell := paladin.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 6940}.WithTag(1),
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskEmpty,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagIgnoreModifiers | core.SpellFlagNoSpellMods | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		DamageMultiplier: 1,
+	})
+
+	handOfSacrificeAuras := paladin.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit || unit == &paladin.Unit {
+			return nil
+		}
+		return core.HandOfSacrificeAura(unit, transferSpell, paladin.Index)
+	})
+
+	paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 6940},
+		Flags:          core.SpellFlagAPL | core.SpellFlagHelpful,
+		ClassSpellMask: SpellMaskHandOfSacrifice,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 7,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				NonEmpty: true,
+			},
+			CD: core.Cooldown{
+				Timer:    paladin.NewTimer(),
+				Duration: core.HandOfSacrificeCD,
+			},
+		},
+
+		// Can only be cast on other raid members.
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return target.Type == core.PlayerUnit && target != &paladin.Unit
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			handOfSacrificeAuras.Get(target).Activate(sim)
+		},
+		RelatedAuraArrays: handOfSacrificeAuras.ToMap(),
+	})
+}
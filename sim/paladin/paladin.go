@@ -82,9 +82,49 @@ func (paladin *Paladin) GetPaladin() *Paladin {
 func (paladin *Paladin) AddRaidBuffs(_ *proto.RaidBuffs) {
 }
 
+// Paladins only keep one blessing up. Kings comes from the first Paladin in the
+// raid, unless another class already provides the stats buff, and every other
+// Paladin provides Might. This looks at the whole raid so the choice doesn't
+// depend on which players have already added their buffs.
+func (paladin *Paladin) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	otherBuffs := &proto.RaidBuffs{}
+	isFirstPaladin := true
+	foundSelf := false
+	for _, party := range paladin.Env.Raid.Parties {
+		for _, player := range party.Players {
+			if otherPaladin, ok := player.(PaladinAgent); ok {
+				if otherPaladin.GetPaladin() == paladin {
+					foundSelf = true
+				} else if !foundSelf {
+					isFirstPaladin = false
+				}
+				continue
+			}
+			player.AddRaidBuffs(otherBuffs)
+			player.AddCompositionRaidBuffs(otherBuffs)
+		}
+	}
+
+	if isFirstPaladin && !otherBuffs.MarkOfTheWild && !otherBuffs.LegacyOfTheEmperor && !otherBuffs.EmbraceOfTheShaleSpider {
+		raidBuffs.BlessingOfKings = true
+	} else {
+		raidBuffs.BlessingOfMight = true
+	}
+}
+
 func (paladin *Paladin) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
+func (paladin *Paladin) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	switch paladin.Spec {
+	case proto.Spec_SpecProtectionPaladin:
+		debuffs.WeakenedBlows = true
+	case proto.Spec_SpecRetributionPaladin:
+		debuffs.WeakenedBlows = true
+		debuffs.PhysicalVulnerability = true
+	}
+}
+
 func (paladin *Paladin) Initialize() {
 	paladin.registerGlyphs()
 	paladin.registerSpells()
@@ -101,8 +141,10 @@ func (paladin *Paladin) registerSpells() {
 	paladin.registerGuardianOfAncientKings()
 	paladin.registerHammerOfTheRighteous()
 	paladin.registerHammerOfWrath()
+	paladin.registerHandOfSacrifice()
 	paladin.registerJudgment()
 	paladin.registerLayOnHands()
+	paladin.registerRebuke()
 	paladin.registerSanctityOfBattle()
 	paladin.registerSealOfInsight()
 	paladin.registerSealOfRighteousness()
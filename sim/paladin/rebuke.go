@@ -0,0 +1,31 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (paladin *Paladin) registerRebuke() {
+	paladin.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 96231},
+		SpellSchool: core.SpellSchoolHoly,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    core.MaxMeleeRange,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    paladin.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMeleeSpecialHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*4)
+			}
+		},
+	})
+}
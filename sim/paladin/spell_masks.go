@@ -14,6 +14,7 @@ const (
 	SpellMaskHammerOfTheRighteousMelee
 	SpellMaskHammerOfTheRighteousAoe
 	SpellMaskHandOfProtection
+	SpellMaskHandOfSacrifice
 	SpellMaskJudgment
 	SpellMaskLayOnHands
 	SpellMaskSealOfInsight
@@ -0,0 +1,76 @@
+package holy
+
+import (
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func (holy *HolyPriest) NewAPLValue(rot *core.APLRotation, config *proto.APLValue) core.APLValue {
+	switch config.Value.(type) {
+	case *proto.APLValue_HolyPriestCurrentChakra:
+		return holy.newValueCurrentChakra(config.GetHolyPriestCurrentChakra(), config.Uuid)
+	case *proto.APLValue_HolyPriestSerendipityStacks:
+		return holy.newValueSerendipityStacks(config.GetHolyPriestSerendipityStacks(), config.Uuid)
+	default:
+		return nil
+	}
+}
+
+type APLValueCurrentChakra struct {
+	core.DefaultAPLValueImpl
+	chakra proto.APLValueChakra
+	holy   *HolyPriest
+}
+
+func (holy *HolyPriest) newValueCurrentChakra(config *proto.APLValueHolyPriestCurrentChakra, uuid *proto.UUID) core.APLValue {
+	return &APLValueCurrentChakra{
+		holy:   holy,
+		chakra: config.Chakra,
+	}
+}
+
+func (value *APLValueCurrentChakra) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+
+func (value *APLValueCurrentChakra) GetBool(sim *core.Simulation) bool {
+	switch value.chakra {
+	case proto.APLValueChakra_ChakraSerenity:
+		return value.holy.ChakraSerenityAura.IsActive()
+	case proto.APLValueChakra_ChakraSanctuary:
+		return value.holy.ChakraSanctuaryAura.IsActive()
+	case proto.APLValueChakra_ChakraChastise:
+		return value.holy.ChakraChastiseAura.IsActive()
+	}
+
+	return !value.holy.ChakraSerenityAura.IsActive() &&
+		!value.holy.ChakraSanctuaryAura.IsActive() &&
+		!value.holy.ChakraChastiseAura.IsActive()
+}
+
+func (value *APLValueCurrentChakra) String() string {
+	return "Current Chakra(" + value.chakra.String() + ")"
+}
+
+type APLValueSerendipityStacks struct {
+	core.DefaultAPLValueImpl
+	holy *HolyPriest
+}
+
+func (holy *HolyPriest) newValueSerendipityStacks(_ *proto.APLValueHolyPriestSerendipityStacks, uuid *proto.UUID) core.APLValue {
+	return &APLValueSerendipityStacks{
+		holy: holy,
+	}
+}
+
+func (value *APLValueSerendipityStacks) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+
+func (value *APLValueSerendipityStacks) GetInt(sim *core.Simulation) int32 {
+	return value.holy.SerendipityAura.GetStacks()
+}
+
+func (value *APLValueSerendipityStacks) String() string {
+	return "Serendipity Stacks"
+}
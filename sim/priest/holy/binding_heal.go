@@ -0,0 +1,51 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const BindingHealScale = 9.494
+const BindingHealVariance = 0.25
+const BindingHealCoeff = 0.899
+
+// Binding Heal heals both the target and the priest. Cast on the priest
+// itself it only heals once.
+func (holy *HolyPriest) registerBindingHealSpell() {
+	holy.BindingHeal = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 32546},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellBindingHeal,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.4,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 0.5,
+		BonusCoefficient: BindingHealCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+
+			holy.calcAndDealDirectHeal(sim, target, spell, holy.CalcAndRollDamageRange(sim, BindingHealScale, BindingHealVariance))
+			if target != &holy.Unit {
+				holy.calcAndDealDirectHeal(sim, &holy.Unit, spell, holy.CalcAndRollDamageRange(sim, BindingHealScale, BindingHealVariance))
+			}
+		},
+	})
+}
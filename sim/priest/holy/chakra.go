@@ -0,0 +1,109 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const singleTargetHeals = priest.PriestSpellFlashHeal |
+	priest.PriestSpellHeal |
+	priest.PriestSpellGreaterHeal |
+	priest.PriestSpellBindingHeal |
+	priest.PriestSpellRenew |
+	priest.PriestSpellEmpoweredRenew |
+	priest.PriestSpellHolyWordSerenity
+
+const aoeHeals = priest.PriestSpellPrayerOfHealing |
+	priest.PriestSpellCircleOfHealing |
+	priest.PriestSpellPrayerOfMending |
+	priest.PriestSpellHolyWordSanctuary |
+	priest.PriestSpellCascade |
+	priest.PriestSpellDivineStar |
+	priest.PriestSpellHalo
+
+// Chakra: Serenity refreshes Renew on the target of these heals.
+const renewRefreshingHeals = priest.PriestSpellFlashHeal |
+	priest.PriestSpellHeal |
+	priest.PriestSpellGreaterHeal |
+	priest.PriestSpellBindingHeal |
+	priest.PriestSpellHolyWordSerenity
+
+// The three Chakra states share a 30 sec cooldown and only one of them can be
+// active at a time.
+func (holy *HolyPriest) registerChakras() {
+	chakraTimer := holy.NewTimer()
+
+	holy.ChakraSerenityAura = holy.RegisterAura(core.Aura{
+		Label:    "Chakra: Serenity",
+		ActionID: core.ActionID{SpellID: 81208},
+		Duration: core.NeverExpires,
+		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if !spell.Matches(renewRefreshingHeals) {
+				return
+			}
+
+			renew := holy.Renew.Hot(result.Target)
+			if renew != nil && renew.IsActive() {
+				renew.ApplyRollover(sim)
+			}
+		},
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  singleTargetHeals,
+		FloatValue: 0.25,
+	})
+
+	holy.ChakraSanctuaryAura = holy.RegisterAura(core.Aura{
+		Label:    "Chakra: Sanctuary",
+		ActionID: core.ActionID{SpellID: 81206},
+		Duration: core.NeverExpires,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  aoeHeals,
+		FloatValue: 0.25,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:      core.SpellMod_Cooldown_Flat,
+		ClassMask: priest.PriestSpellCircleOfHealing,
+		TimeValue: -time.Second * 2,
+	})
+
+	holy.ChakraChastiseAura = holy.RegisterAura(core.Aura{
+		Label:    "Chakra: Chastise",
+		ActionID: core.ActionID{SpellID: 81209},
+		Duration: core.NeverExpires,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		School:     core.SpellSchoolHoly | core.SpellSchoolShadow,
+		ProcMask:   core.ProcMaskSpellDamage,
+		FloatValue: 0.5,
+	})
+
+	chakraAuras := []*core.Aura{holy.ChakraSerenityAura, holy.ChakraSanctuaryAura, holy.ChakraChastiseAura}
+	for _, chakraAura := range chakraAuras {
+		holy.RegisterSpell(core.SpellConfig{
+			ActionID:       chakraAura.ActionID,
+			SpellSchool:    core.SpellSchoolHoly,
+			ProcMask:       core.ProcMaskEmpty,
+			Flags:          core.SpellFlagAPL,
+			ClassSpellMask: priest.PriestSpellChakra,
+
+			Cast: core.CastConfig{
+				CD: core.Cooldown{
+					Timer:    chakraTimer,
+					Duration: time.Second * 30,
+				},
+			},
+
+			ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+				for _, otherAura := range chakraAuras {
+					if otherAura != chakraAura {
+						otherAura.Deactivate(sim)
+					}
+				}
+				chakraAura.Activate(sim)
+			},
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const CircleOfHealingScale = 4.5
+const CircleOfHealingVariance = 0.1
+const CircleOfHealingCoeff = 0.467
+
+func (holy *HolyPriest) registerCircleOfHealingSpell() {
+	numTargets := 5 + core.TernaryInt32(holy.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfCircleOfHealing), 1, 0)
+
+	var targets []*core.Unit
+	holy.CircleOfHealing = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 34861},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellCircleOfHealing,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.2,
+			PercentModifier: core.TernaryFloat64(holy.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfCircleOfHealing), 1.2, 1),
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holy.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: CircleOfHealingCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if targets == nil {
+				targets = holy.Env.Raid.GetFirstNPlayersOrPets(numTargets)
+			}
+
+			for _, aoeTarget := range targets {
+				holy.calcAndDealDirectHeal(sim, aoeTarget, spell, holy.CalcAndRollDamageRange(sim, CircleOfHealingScale, CircleOfHealingVariance))
+			}
+		},
+	})
+}
@@ -0,0 +1,59 @@
+// Implements the holy priest's mastery
+// Direct heals leave a HoT on the target healing for a percentage of the
+// heal over 6 sec. New heals roll the remaining healing into the refreshed HoT.
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+func (holy *HolyPriest) echoOfLightPercent() float64 {
+	return (holy.GetMasteryPoints()*1.25 + 8*1.25) / 100
+}
+
+func (holy *HolyPriest) registerEchoOfLight() {
+	holy.EchoOfLight = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 77489},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagIgnoreModifiers | core.SpellFlagNoSpellMods | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		ClassSpellMask: priest.PriestSpellEchoOfLight,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Echo of Light",
+			},
+			NumberOfTicks: 6,
+			TickLength:    time.Second,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.SnapshotAttackerMultiplier = 1
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(target).Apply(sim)
+		},
+	})
+
+	core.MakePermanent(holy.RegisterAura(core.Aura{
+		Label: "Echo of Light (Mastery)",
+		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if result.Damage == 0 || spell.ClassSpellMask == 0 || spell.Matches(priest.PriestSpellEchoOfLight) {
+				return
+			}
+
+			hot := holy.EchoOfLight.Hot(result.Target)
+			newHealing := result.Damage * holy.echoOfLightPercent()
+			hot.SnapshotBaseDamage = (hot.OutstandingDmg() + newHealing) / float64(hot.BaseTickCount+core.TernaryInt32(hot.IsActive(), 1, 0))
+			holy.EchoOfLight.Cast(sim, result.Target)
+		},
+	}))
+}
@@ -0,0 +1,46 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const FlashHealScale = 12.296
+const FlashHealVariance = 0.15
+const FlashHealCoeff = 1.314
+
+func (holy *HolyPriest) registerFlashHealSpell() {
+	holy.FlashHeal = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2061},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellFlashHeal,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2.8,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: FlashHealCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			baseHealing := holy.CalcAndRollDamageRange(sim, FlashHealScale, FlashHealVariance)
+			holy.calcAndDealDirectHeal(sim, target, spell, baseHealing)
+		},
+	})
+}
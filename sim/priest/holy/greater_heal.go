@@ -0,0 +1,46 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const GreaterHealScale = 21.909
+const GreaterHealVariance = 0.15
+const GreaterHealCoeff = 2.19
+
+func (holy *HolyPriest) registerGreaterHealSpell() {
+	holy.GreaterHeal = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2060},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellGreaterHeal,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.9,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: GreaterHealCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			baseHealing := holy.CalcAndRollDamageRange(sim, GreaterHealScale, GreaterHealVariance)
+			holy.calcAndDealDirectHeal(sim, target, spell, baseHealing)
+		},
+	})
+}
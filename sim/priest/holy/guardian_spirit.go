@@ -0,0 +1,48 @@
+package holy
+
+import (
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+// Calls upon a guardian spirit to watch over the friendly target for 10 sec,
+// increasing healing received by 40%. If the target would die, the spirit
+// sacrifices itself and instead restores the target to 50% of maximum health.
+func (holy *HolyPriest) registerGuardianSpiritSpell() {
+	guardianSpiritAuras := holy.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+		return core.GuardianSpiritAura(unit, holy.Index)
+	})
+
+	holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 47788},
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellGuardianSpirit,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 1.2,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				NonEmpty: true,
+			},
+			CD: core.Cooldown{
+				Timer:    holy.NewTimer(),
+				Duration: core.GuardianSpiritCD,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			if target.Type != core.PlayerUnit {
+				target = &holy.Unit
+			}
+			guardianSpiritAuras.Get(target).Activate(sim)
+		},
+		RelatedAuraArrays: guardianSpiritAuras.ToMap(),
+	})
+}
@@ -0,0 +1,46 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const HealScale = 9.494
+const HealVariance = 0.15
+const HealCoeff = 1.024
+
+func (holy *HolyPriest) registerHealSpell() {
+	holy.Heal = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2050},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellHeal,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 1.9,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: HealCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			baseHealing := holy.CalcAndRollDamageRange(sim, HealScale, HealVariance)
+			holy.calcAndDealDirectHeal(sim, target, spell, baseHealing)
+		},
+	})
+}
@@ -41,24 +41,83 @@ func NewHolyPriest(character *core.Character, options *proto.Player) *HolyPriest
 
 type HolyPriest struct {
 	*priest.Priest
+
+	Heal                 *core.Spell
+	HolyWordSerenity     *core.Spell
+	HolyWordSanctuary    *core.Spell
+	EchoOfLight          *core.Spell
+	DivineInsightPoM     *core.Aura
+	SerendipityAura      *core.Aura
+	HolyWordSerenityAura core.AuraArray
+
+	ChakraSerenityAura  *core.Aura
+	ChakraSanctuaryAura *core.Aura
+	ChakraChastiseAura  *core.Aura
+}
+
+func (holy *HolyPriest) GetPriest() *priest.Priest {
+	return holy.Priest
+}
+
+func (holy *HolyPriest) Initialize() {
+	holy.Priest.Initialize()
+
+	// Meditation
+	holy.PseudoStats.SpiritRegenRateCombat = 0.5
+
+	holy.registerFlashHealSpell()
+	holy.registerHealSpell()
+	holy.registerGreaterHealSpell()
+	holy.registerBindingHealSpell()
+	holy.registerRenewSpell()
+	holy.registerPrayerOfMendingSpell()
+	holy.registerPrayerOfHealingSpell()
+	holy.registerCircleOfHealingSpell()
+	holy.registerGuardianSpiritSpell()
+	holy.registerChakras()
+	holy.registerHolyWordSerenitySpell()
+	holy.registerHolyWordSanctuarySpell()
+	holy.registerSerendipity()
+	holy.registerEchoOfLight() // Mastery
 }
 
-func (holyPriest *HolyPriest) GetPriest() *priest.Priest {
-	return holyPriest.Priest
+func (holy *HolyPriest) ApplyTalents() {
+	holy.Priest.ApplyTalents()
+
+	holy.registerSurgeOfLight()
+	holy.registerTwistOfFate()
+	holy.registerDivineInsight()
 }
 
-func (holyPriest *HolyPriest) Initialize() {
-	holyPriest.Priest.Initialize()
+func (holy *HolyPriest) Reset(sim *core.Simulation) {
+	holy.Priest.Reset(sim)
+}
 
-	// holyPriest.RegisterHolyFireSpell()
-	// holyPriest.RegisterSmiteSpell()
-	// holyPriest.RegisterPenanceSpell()
-	// holyPriest.RegisterHymnOfHopeCD()
+func (holy *HolyPriest) OnEncounterStart(sim *core.Simulation) {
+	holy.Priest.OnEncounterStart(sim)
 }
 
-func (holyPriest *HolyPriest) ApplyTalents() {
+// Heals always land on a friendly unit. Casting a heal on an enemy falls back
+// to healing the priest, matching in-game smart targeting.
+func (holy *HolyPriest) healTarget(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&holy.Unit) {
+		return &holy.Unit
+	}
+	return target
 }
 
-func (holyPriest *HolyPriest) Reset(sim *core.Simulation) {
-	//holyPriest.Priest.Reset(sim)
+// Direct heals go through here so that Holy Word: Serenity's crit bonus is
+// applied for the target being healed.
+func (holy *HolyPriest) calcAndDealDirectHeal(sim *core.Simulation, target *core.Unit, spell *core.Spell, baseHealing float64) *core.SpellResult {
+	bonusCrit := 0.0
+	if serenity := holy.HolyWordSerenityAura.Get(target); serenity != nil && serenity.IsActive() {
+		bonusCrit = 25
+	}
+
+	spell.BonusCritPercent += bonusCrit
+	result := spell.CalcHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+	spell.BonusCritPercent -= bonusCrit
+
+	spell.DealHealing(sim, result)
+	return result
 }
@@ -0,0 +1,131 @@
+package holy
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const HolyWordSerenityScale = 12.6
+const HolyWordSerenityVariance = 0.16
+const HolyWordSerenityCoeff = 1.3
+
+const HolyWordSanctuaryScale = 0.587
+const HolyWordSanctuaryCoeff = 0.0583
+const HolyWordSanctuaryTargets = 6
+
+// Holy Word: Serenity is only available while in Chakra: Serenity. Besides the
+// heal, it increases the critical effect chance of the priest's heals on the
+// target by 25% for 6 sec.
+func (holy *HolyPriest) registerHolyWordSerenitySpell() {
+	actionID := core.ActionID{SpellID: 88684}
+
+	holy.HolyWordSerenityAura = holy.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		return unit.RegisterAura(core.Aura{
+			Label:    "Holy Word: Serenity" + strconv.Itoa(int(holy.Index)),
+			ActionID: actionID,
+			Duration: time.Second * 6,
+		})
+	})
+
+	holy.HolyWordSerenity = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellHolyWordSerenity,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holy.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: HolyWordSerenityCoeff,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return holy.ChakraSerenityAura.IsActive()
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			holy.calcAndDealDirectHeal(sim, target, spell, holy.CalcAndRollDamageRange(sim, HolyWordSerenityScale, HolyWordSerenityVariance))
+			holy.HolyWordSerenityAura.Get(target).Activate(sim)
+		},
+	})
+}
+
+// Holy Word: Sanctuary is only available while in Chakra: Sanctuary. It blesses
+// the ground for 30 sec, healing up to 6 allies standing in it every 2 sec.
+func (holy *HolyPriest) registerHolyWordSanctuarySpell() {
+	var targets []*core.Unit
+
+	holy.HolyWordSanctuary = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 88685},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellHolyWordSanctuary,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 4,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holy.NewTimer(),
+				Duration: time.Second * 40,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: HolyWordSanctuaryCoeff,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return holy.ChakraSanctuaryAura.IsActive()
+		},
+
+		Hot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Holy Word: Sanctuary",
+			},
+			NumberOfTicks: 15,
+			TickLength:    time.Second * 2,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				if targets == nil {
+					targets = holy.Env.Raid.GetFirstNPlayersOrPets(HolyWordSanctuaryTargets)
+				}
+
+				for _, aoeTarget := range targets {
+					dot.Spell.CalcAndDealPeriodicHealing(sim, aoeTarget, holy.CalcScalingSpellDmg(HolyWordSanctuaryScale), dot.Spell.OutcomeHealingCrit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEHot().Apply(sim)
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const PrayerOfHealingScale = 8.297
+const PrayerOfHealingVariance = 0.055
+const PrayerOfHealingCoeff = 0.838
+
+// Prayer of Healing heals every member of the target's party.
+func (holy *HolyPriest) registerPrayerOfHealingSpell() {
+	holy.PrayerOfHealing = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 596},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellPrayerOfHealing,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 4.5,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PrayerOfHealingCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+
+			targetAgent := holy.Env.Raid.GetPlayerFromUnitIndex(target.UnitIndex)
+			if targetAgent == nil {
+				holy.calcAndDealDirectHeal(sim, target, spell, holy.CalcAndRollDamageRange(sim, PrayerOfHealingScale, PrayerOfHealingVariance))
+				return
+			}
+
+			for _, partyAgent := range targetAgent.GetCharacter().Party.PlayersAndPets {
+				partyTarget := &partyAgent.GetCharacter().Unit
+				holy.calcAndDealDirectHeal(sim, partyTarget, spell, holy.CalcAndRollDamageRange(sim, PrayerOfHealingScale, PrayerOfHealingVariance))
+			}
+		},
+	})
+}
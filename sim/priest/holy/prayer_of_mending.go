@@ -0,0 +1,115 @@
+package holy
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const PrayerOfMendingScale = 5.6
+const PrayerOfMendingCoeff = 0.571
+const PrayerOfMendingJumps = 5
+
+func (holy *HolyPriest) registerPrayerOfMendingSpell() {
+	pomAuras := holy.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		return holy.makePrayerOfMendingAura(unit)
+	})
+
+	var curTarget *core.Unit
+	var remainingJumps int
+	holy.ProcPrayerOfMending = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+		holy.calcAndDealDirectHeal(sim, target, spell, holy.CalcScalingSpellDmg(PrayerOfMendingScale))
+
+		pomAuras.Get(target).Deactivate(sim)
+		curTarget = nil
+
+		// Bounce to new ally.
+		if remainingJumps == 0 {
+			return
+		}
+
+		// Find ally with lowest % HP and is not the current mending target.
+		var newTarget *core.Unit
+		for _, raidUnit := range holy.Env.Raid.AllUnits {
+			if raidUnit == target || !raidUnit.HasHealthBar() {
+				continue
+			}
+
+			if newTarget == nil || raidUnit.CurrentHealthPercent() < newTarget.CurrentHealthPercent() {
+				newTarget = raidUnit
+			}
+		}
+
+		if newTarget != nil {
+			pomAuras.Get(newTarget).Activate(sim)
+			curTarget = newTarget
+			remainingJumps--
+		}
+	}
+
+	holy.PrayerOfMending = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33076},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellPrayerOfMending,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.5,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holy.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PrayerOfMendingCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			if curTarget != nil {
+				pomAuras.Get(curTarget).Deactivate(sim)
+			}
+
+			pomAuras.Get(target).Activate(sim)
+			curTarget = target
+			remainingJumps = PrayerOfMendingJumps - 1
+		},
+	})
+}
+
+func (holy *HolyPriest) makePrayerOfMendingAura(target *core.Unit) *core.Aura {
+	// Damage taken isn't modelled for most allies, so the heal procs on a fixed
+	// delay instead of on the next hit.
+	var procAction *core.PendingAction
+
+	return target.RegisterAura(core.Aura{
+		Label:    "PrayerOfMending" + strconv.Itoa(int(holy.Index)),
+		ActionID: core.ActionID{SpellID: 41635},
+		Duration: time.Second * 30,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			procAction = core.NewDelayedAction(core.DelayedActionOptions{
+				DoAt: sim.CurrentTime + time.Second*5,
+				OnAction: func(sim *core.Simulation) {
+					holy.ProcPrayerOfMending(sim, aura.Unit, holy.PrayerOfMending)
+				},
+			})
+			sim.AddPendingAction(procAction)
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			procAction.Cancel(sim)
+		},
+	})
+}
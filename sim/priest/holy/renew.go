@@ -0,0 +1,86 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const RenewScale = 2.051
+const RenewCoeff = 0.207
+
+// Rapid Renewal: Renew's global cooldown is reduced by 0.5 sec and it
+// instantly heals the target for 15% of its total periodic effect.
+const RapidRenewalHealPct = 0.15
+
+func (holy *HolyPriest) registerRenewSpell() {
+	renewTicks := int32(4)
+
+	holy.EmpoweredRenew = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 63544},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		ClassSpellMask: priest.PriestSpellEmpoweredRenew,
+
+		DamageMultiplier: float64(renewTicks) * RapidRenewalHealPct,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: RenewCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			holy.calcAndDealDirectHeal(sim, target, spell, holy.CalcScalingSpellDmg(RenewScale))
+		},
+	})
+
+	holy.Renew = holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 139},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellRenew,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2.6,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault - time.Millisecond*500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holy.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Renew",
+			},
+			NumberOfTicks:       renewTicks,
+			TickLength:          time.Second * 3,
+			AffectedByCastSpeed: true,
+			BonusCoefficient:    RenewCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				// Chakra: Serenity refreshes Renew without changing its snapshot.
+				if !isRollover {
+					dot.SnapshotHeal(target, holy.CalcScalingSpellDmg(RenewScale))
+				}
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = holy.healTarget(target)
+			spell.Hot(target).Apply(sim)
+			holy.EmpoweredRenew.Cast(sim, target)
+		},
+	})
+}
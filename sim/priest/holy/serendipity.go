@@ -0,0 +1,58 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+// Serendipity: Flash Heal and Binding Heal reduce the cast time and mana cost
+// of the priest's next Greater Heal or Prayer of Healing by 20%, stacking up to
+// 2 times.
+func (holy *HolyPriest) registerSerendipity() {
+	serendipityConsumers := priest.PriestSpellGreaterHeal | priest.PriestSpellPrayerOfHealing
+
+	castTimeMod := holy.AddDynamicMod(core.SpellModConfig{
+		ClassMask: serendipityConsumers,
+		Kind:      core.SpellMod_CastTime_Pct,
+	})
+	costMod := holy.AddDynamicMod(core.SpellModConfig{
+		ClassMask: serendipityConsumers,
+		Kind:      core.SpellMod_PowerCost_Pct,
+	})
+
+	holy.SerendipityAura = holy.RegisterAura(core.Aura{
+		Label:     "Serendipity",
+		ActionID:  core.ActionID{SpellID: 63735},
+		Duration:  time.Second * 20,
+		MaxStacks: 2,
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			castTimeMod.UpdateFloatValue(-0.2 * float64(newStacks))
+			costMod.UpdateFloatValue(-0.2 * float64(newStacks))
+		},
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Activate()
+			costMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Deactivate()
+			costMod.Deactivate()
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.Matches(serendipityConsumers) {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	core.MakePermanent(holy.RegisterAura(core.Aura{
+		Label: "Serendipity (Passive)",
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.Matches(priest.PriestSpellFlashHeal | priest.PriestSpellBindingHeal) {
+				holy.SerendipityAura.Activate(sim)
+				holy.SerendipityAura.AddStack(sim)
+			}
+		},
+	}))
+}
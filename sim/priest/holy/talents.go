@@ -0,0 +1,132 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+// From Darkness, Comes Light: healing spells have a 15% chance to make the
+// next Flash Heal instant and free.
+func (holy *HolyPriest) registerSurgeOfLight() {
+	holy.SurgeOfLightProcAura = core.BlockPrepull(holy.RegisterAura(core.Aura{
+		Label:     "Surge of Light",
+		ActionID:  core.ActionID{SpellID: 114255},
+		Duration:  time.Second * 20,
+		MaxStacks: 2,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell == holy.FlashHeal {
+				aura.RemoveStack(sim)
+			}
+		},
+	})).AttachSpellMod(core.SpellModConfig{
+		ClassMask:  priest.PriestSpellFlashHeal,
+		Kind:       core.SpellMod_PowerCost_Pct,
+		FloatValue: -2,
+	}).AttachSpellMod(core.SpellModConfig{
+		ClassMask:  priest.PriestSpellFlashHeal,
+		Kind:       core.SpellMod_CastTime_Pct,
+		FloatValue: -1,
+	})
+
+	// Always register the auras above, so APL conditions referencing them
+	// still resolve when the talent is not taken.
+	if !holy.Talents.FromDarknessComesLight {
+		return
+	}
+
+	core.MakePermanent(holy.RegisterAura(core.Aura{
+		Label: "Surge of Light (Talent)",
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if !spell.Matches(singleTargetHeals|aoeHeals) || spell == holy.FlashHeal {
+				return
+			}
+
+			if sim.Proc(0.15, "Surge of Light (Proc)") {
+				holy.SurgeOfLightProcAura.Activate(sim)
+				holy.SurgeOfLightProcAura.AddStack(sim)
+			}
+		},
+	}))
+}
+
+// Twist of Fate: healing a target below 35% health, or damaging one, increases
+// all damage and healing done by 15% for 10 sec.
+func (holy *HolyPriest) registerTwistOfFate() {
+	if !holy.Talents.TwistOfFate {
+		return
+	}
+
+	tofAura := holy.RegisterAura(core.Aura{
+		Label:    "Twist of Fate",
+		ActionID: core.ActionID{SpellID: 123254},
+		Duration: time.Second * 10,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		School:     core.SpellSchoolShadow | core.SpellSchoolHoly,
+		FloatValue: 0.15,
+	})
+
+	core.MakePermanent(holy.RegisterAura(core.Aura{
+		Label: "Twist of Fate (Talent)",
+		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if isBelowTwistOfFateThreshold(sim, result.Target) {
+				tofAura.Activate(sim)
+			}
+		},
+		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if result.Landed() && isBelowTwistOfFateThreshold(sim, result.Target) {
+				tofAura.Activate(sim)
+			}
+		},
+	}))
+}
+
+// Twist of Fate checks the health of the unit being healed or damaged. Targets
+// without a health bar fall back to the encounter's execute phase.
+func isBelowTwistOfFateThreshold(sim *core.Simulation, target *core.Unit) bool {
+	if target.HasHealthBar() {
+		return target.CurrentHealthPercent() < 0.35
+	}
+	return target.Type == core.EnemyUnit && sim.IsExecutePhase35()
+}
+
+// Divine Insight: Greater Heal and Prayer of Healing have a 40% chance to make
+// the next Prayer of Mending free and reset its cooldown.
+func (holy *HolyPriest) registerDivineInsight() {
+	holy.DivineInsightPoM = core.BlockPrepull(holy.RegisterAura(core.Aura{
+		Label:    "Divine Insight",
+		ActionID: core.ActionID{SpellID: 123267},
+		Duration: time.Second * 10,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			holy.PrayerOfMending.CD.Reset()
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell == holy.PrayerOfMending {
+				aura.Deactivate(sim)
+			}
+		},
+	})).AttachSpellMod(core.SpellModConfig{
+		ClassMask:  priest.PriestSpellPrayerOfMending,
+		Kind:       core.SpellMod_PowerCost_Pct,
+		FloatValue: -2,
+	})
+
+	if !holy.Talents.DivineInsight {
+		return
+	}
+
+	core.MakePermanent(holy.RegisterAura(core.Aura{
+		Label: "Divine Insight (Talent)",
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if !spell.Matches(priest.PriestSpellGreaterHeal | priest.PriestSpellPrayerOfHealing) {
+				return
+			}
+
+			if sim.Proc(0.4, "Divine Insight (Proc)") {
+				holy.DivineInsightPoM.Activate(sim)
+			}
+		},
+	}))
+}
@@ -1,45 +1,32 @@
 package priest
 
 import (
-	"time"
-
 	"github.com/wowsims/mop/sim/core"
 )
 
-const PowerInfusionDuration = time.Second * 20
-const PowerInfusionCD = time.Minute * 2
-
 func (priest *Priest) registerPowerInfusionSpell() {
 	if !priest.Talents.PowerInfusion {
 		return
 	}
 	actionID := core.ActionID{SpellID: 10060}
-	piAura := priest.GetOrRegisterAura(core.Aura{
-		Label:    "PowerInfusion-Aura",
-		ActionID: actionID,
-		Duration: PowerInfusionDuration,
-	}).AttachSpellMod(core.SpellModConfig{
-		Kind:       core.SpellMod_DamageDone_Pct,
-		FloatValue: 0.05,
-	}).AttachMultiplyCastSpeed(1.2)
 
-	piAura.NewExclusiveEffect("ManaCost", true, core.ExclusiveEffect{
-		Priority: -20,
-		OnGain: func(ee *core.ExclusiveEffect, sim *core.Simulation) {
-			ee.Aura.Unit.PseudoStats.SpellCostPercentModifier -= 20
-		},
-		OnExpire: func(ee *core.ExclusiveEffect, sim *core.Simulation) {
-			ee.Aura.Unit.PseudoStats.SpellCostPercentModifier += 20
-		},
+	piAuras := priest.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+		return core.PowerInfusionAura(unit, priest.Index)
 	})
 
+	piTarget := priest.GetUnit(priest.SelfBuffs.PowerInfusionTarget)
+
 	piSpell := priest.RegisterSpell(core.SpellConfig{
-		ActionID: actionID,
-		Flags:    core.SpellFlagHelpful,
+		ActionID:       actionID,
+		Flags:          core.SpellFlagAPL | core.SpellFlagHelpful,
+		ClassSpellMask: PriestSpellPowerInfusion,
 		Cast: core.CastConfig{
 			CD: core.Cooldown{
 				Timer:    priest.NewTimer(),
-				Duration: PowerInfusionCD,
+				Duration: core.PowerInfusionCD,
 			},
 			DefaultCast: core.Cast{
 				NonEmpty: true,
@@ -47,8 +34,16 @@ func (priest *Priest) registerPowerInfusionSpell() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
-			piAura.Activate(sim)
+			// Major cooldowns are cast on the priest, so only another raid member is an APL choice.
+			if piTarget != nil {
+				piAuras.Get(piTarget).Activate(sim)
+			} else if target.Type == core.PlayerUnit && target != &priest.Unit {
+				piAuras.Get(target).Activate(sim)
+			} else {
+				piAuras.Get(&priest.Unit).Activate(sim)
+			}
 		},
+		RelatedAuraArrays: piAuras.ToMap(),
 	})
 
 	priest.AddMajorCooldown(core.MajorCooldown{
@@ -75,6 +75,10 @@ func (priest *Priest) GetCharacter() *core.Character {
 	return &priest.Character
 }
 
+func (priest *Priest) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	raidBuffs.PowerWordFortitude = true
+}
+
 func (priest *Priest) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
@@ -167,6 +171,7 @@ const (
 	PriestSpellDarkArchangel
 	PriestSpellBindingHeal
 	PriestSpellCascade
+	PriestSpellChakra
 	PriestSpellCircleOfHealing
 	PriestSpellDevouringPlague
 	PriestSpellDevouringPlagueDoT
@@ -176,12 +181,14 @@ const (
 	PriestSpellDivineAegis
 	PriestSpellDivineHymn
 	PriestSpellDivineStar
+	PriestSpellEchoOfLight
 	PriestSpellEmpoweredRenew
 	PriestSpellFade
 	PriestSpellFlashHeal
 	PriestSpellGreaterHeal
 	PriestSpellGuardianSpirit
 	PriestSpellHalo
+	PriestSpellHeal
 	PriestSpellHolyFire
 	PriestSpellHolyNova
 	PriestSpellHolyWordChastise
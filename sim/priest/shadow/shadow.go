@@ -67,6 +67,11 @@ func (spriest *ShadowPriest) GetPriest() *priest.Priest {
 	return spriest.Priest
 }
 
+func (spriest *ShadowPriest) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	spriest.Priest.AddCompositionRaidBuffs(raidBuffs)
+	raidBuffs.MindQuickening = true
+}
+
 func (spriest *ShadowPriest) Initialize() {
 	spriest.Priest.Initialize()
 
@@ -79,6 +84,7 @@ func (spriest *ShadowPriest) Initialize() {
 	spriest.registerMindFlaySpell()
 	spriest.registerShadowyRecall() // Mastery
 	spriest.registerShadowyApparition()
+	spriest.registerSilenceSpell()
 }
 
 func (spriest *ShadowPriest) Reset(sim *core.Simulation) {
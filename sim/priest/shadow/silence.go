@@ -0,0 +1,32 @@
+package shadow
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (spriest *ShadowPriest) registerSilenceSpell() {
+	spriest.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 15487},
+		SpellSchool: core.SpellSchoolShadow,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    30,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    spriest.NewTimer(),
+				Duration: time.Second * 45,
+			},
+		},
+
+		// Casts of non-player targets are interrupted as well as silenced.
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMagicHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*3)
+			}
+		},
+	})
+}
@@ -1,11 +1,14 @@
 package sim
 
 import (
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 func init() {
@@ -81,6 +84,567 @@ func TestBasicRaid(t *testing.T) {
 	core.RaidSimTest("P1 ST", t, rsr, 6323.79)
 }
 
+func compositionTestRaid(players ...*proto.Player) *proto.Raid {
+	return &proto.Raid{
+		Parties: []*proto.Party{{Players: players}},
+
+		// Toggled buffs and debuffs which aren't provided by the roster, and so must be ignored.
+		Buffs:                &proto.RaidBuffs{HornOfWinter: true},
+		Debuffs:              &proto.Debuffs{CurseOfEnfeeblement: true},
+		BuffsFromComposition: true,
+	}
+}
+
+// Tests that raid buffs and debuffs come from the players in the raid when
+// BuffsFromComposition is set.
+func TestRaidBuffsFromComposition(t *testing.T) {
+	warrior := &proto.Player{
+		Name:      "Warrior",
+		Class:     proto.Class_ClassWarrior,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_ArmsWarrior{ArmsWarrior: &proto.ArmsWarrior{
+			Options: &proto.ArmsWarrior_Options{ClassOptions: &proto.WarriorOptions{}},
+		}},
+	}
+	rogue := &proto.Player{
+		Name:      "Rogue",
+		Class:     proto.Class_ClassRogue,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_CombatRogue{CombatRogue: &proto.CombatRogue{
+			Options: &proto.CombatRogue_Options{ClassOptions: &proto.RogueOptions{LethalPoison: proto.RogueOptions_DeadlyPoison}},
+		}},
+	}
+	warlock := &proto.Player{
+		Name:      "Warlock",
+		Class:     proto.Class_ClassWarlock,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_AfflictionWarlock{AfflictionWarlock: &proto.AfflictionWarlock{
+			Options: &proto.AfflictionWarlock_Options{ClassOptions: &proto.WarlockOptions{Summon: proto.WarlockOptions_Felhunter}},
+		}},
+	}
+	hunter := &proto.Player{
+		Name:      "Hunter",
+		Class:     proto.Class_ClassHunter,
+		Race:      proto.Race_RaceDwarf,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_BeastMasteryHunter{BeastMasteryHunter: &proto.BeastMasteryHunter{
+			Options: &proto.BeastMasteryHunter_Options{ClassOptions: &proto.HunterOptions{PetType: proto.HunterOptions_Boar, PetUptime: 1}},
+		}},
+	}
+
+	result := core.ComputeStats(&proto.ComputeStatsRequest{
+		Raid:      compositionTestRaid(warrior, rogue, warlock, hunter),
+		Encounter: STEncounter,
+	})
+	if result.ErrorResult != "" {
+		t.Fatalf("Failed to compute stats: %s", result.ErrorResult)
+	}
+
+	expectedBuffs := &proto.RaidBuffs{
+		BattleShout:        true,
+		TrueshotAura:       true,
+		SwiftbladesCunning: true,
+		DarkIntent:         true,
+	}
+	if !googleProto.Equal(result.RaidStats.Buffs, expectedBuffs) {
+		t.Errorf("Expected raid buffs %v, got %v", expectedBuffs, result.RaidStats.Buffs)
+	}
+
+	expectedDebuffs := &proto.Debuffs{
+		WeakenedBlows:         true,
+		PhysicalVulnerability: true,
+		WeakenedArmor:         true,
+		MasterPoisoner:        true,
+		CurseOfElements:       true,
+	}
+	if !googleProto.Equal(result.RaidStats.Debuffs, expectedDebuffs) {
+		t.Errorf("Expected raid debuffs %v, got %v", expectedDebuffs, result.RaidStats.Debuffs)
+	}
+
+	// Without BuffsFromComposition, only the configured buffs and the buffs
+	// players always provide apply.
+	result = core.ComputeStats(&proto.ComputeStatsRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: []*proto.Player{warrior, rogue, warlock, hunter}}},
+			Buffs:   &proto.RaidBuffs{HornOfWinter: true},
+		},
+		Encounter: STEncounter,
+	})
+	if result.ErrorResult != "" {
+		t.Fatalf("Failed to compute stats: %s", result.ErrorResult)
+	}
+	expectedBuffs = &proto.RaidBuffs{
+		HornOfWinter: true,
+		TrueshotAura: true,
+	}
+	if !googleProto.Equal(result.RaidStats.Buffs, expectedBuffs) {
+		t.Errorf("Expected raid buffs %v, got %v", expectedBuffs, result.RaidStats.Buffs)
+	}
+}
+
+// Tests the buffs which each class only provides in composition mode.
+func TestRaidBuffsFromCompositionPerClass(t *testing.T) {
+	testCases := []struct {
+		name     string
+		player   *proto.Player
+		expected *proto.RaidBuffs
+	}{
+		{
+			"balance druid",
+			&proto.Player{Class: proto.Class_ClassDruid, Race: proto.Race_RaceTauren, Equipment: &proto.EquipmentSpec{},
+				Spec: &proto.Player_BalanceDruid{BalanceDruid: &proto.BalanceDruid{Options: &proto.BalanceDruid_Options{ClassOptions: &proto.DruidOptions{}}}}},
+			&proto.RaidBuffs{MarkOfTheWild: true, MoonkinAura: true},
+		},
+		{
+			"shadow priest",
+			&proto.Player{Class: proto.Class_ClassPriest, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{},
+				Spec: &proto.Player_ShadowPriest{ShadowPriest: &proto.ShadowPriest{Options: &proto.ShadowPriest_Options{ClassOptions: &proto.PriestOptions{}}}}},
+			&proto.RaidBuffs{PowerWordFortitude: true, MindQuickening: true},
+		},
+		{
+			"protection warrior",
+			&proto.Player{Class: proto.Class_ClassWarrior, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{},
+				Spec: &proto.Player_ProtectionWarrior{ProtectionWarrior: &proto.ProtectionWarrior{Options: &proto.ProtectionWarrior_Options{ClassOptions: &proto.WarriorOptions{}}}}},
+			&proto.RaidBuffs{CommandingShout: true},
+		},
+		{
+			"frost death knight",
+			&proto.Player{Class: proto.Class_ClassDeathKnight, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{},
+				Spec: &proto.Player_FrostDeathKnight{FrostDeathKnight: &proto.FrostDeathKnight{Options: &proto.FrostDeathKnight_Options{ClassOptions: &proto.DeathKnightOptions{}}}}},
+			&proto.RaidBuffs{HornOfWinter: true, UnholyAura: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.player.Name = tc.name
+			raid := compositionTestRaid(tc.player)
+			raid.Buffs = &proto.RaidBuffs{}
+			result := core.ComputeStats(&proto.ComputeStatsRequest{
+				Raid:      raid,
+				Encounter: STEncounter,
+			})
+			if result.ErrorResult != "" {
+				t.Fatalf("Failed to compute stats: %s", result.ErrorResult)
+			}
+			if !googleProto.Equal(result.RaidStats.Buffs, tc.expected) {
+				t.Errorf("Expected raid buffs %v, got %v", tc.expected, result.RaidStats.Buffs)
+			}
+		})
+	}
+}
+
+// Tests that a Paladin provides Might when another class provides the stats
+// buff, regardless of the order of the players in the raid.
+func TestRaidBuffsFromCompositionPaladinBlessing(t *testing.T) {
+	paladin := &proto.Player{
+		Name:      "Paladin",
+		Class:     proto.Class_ClassPaladin,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_RetributionPaladin{RetributionPaladin: &proto.RetributionPaladin{
+			Options: &proto.RetributionPaladin_Options{ClassOptions: &proto.PaladinOptions{}},
+		}},
+	}
+	druid := &proto.Player{
+		Name:      "Druid",
+		Class:     proto.Class_ClassDruid,
+		Race:      proto.Race_RaceTauren,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_BalanceDruid{BalanceDruid: &proto.BalanceDruid{
+			Options: &proto.BalanceDruid_Options{ClassOptions: &proto.DruidOptions{}},
+		}},
+	}
+
+	testCases := []struct {
+		name    string
+		players []*proto.Player
+		kings   bool
+		might   bool
+	}{
+		{"paladin", []*proto.Player{paladin}, true, false},
+		{"paladin first", []*proto.Player{paladin, druid}, false, true},
+		{"druid first", []*proto.Player{druid, paladin}, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := core.ComputeStats(&proto.ComputeStatsRequest{
+				Raid:      compositionTestRaid(tc.players...),
+				Encounter: STEncounter,
+			})
+			if result.ErrorResult != "" {
+				t.Fatalf("Failed to compute stats: %s", result.ErrorResult)
+			}
+
+			buffs := result.RaidStats.Buffs
+			if buffs.BlessingOfKings != tc.kings || buffs.BlessingOfMight != tc.might {
+				t.Errorf("Expected Kings %t and Might %t, got Kings %t and Might %t", tc.kings, tc.might, buffs.BlessingOfKings, buffs.BlessingOfMight)
+			}
+		})
+	}
+}
+
+// Tests that the raid comp optimizer takes buffs from the whole composition,
+// so a Paladin alongside a Druid can cover Might.
+func TestRaidCompOptimizeCompositionBuffs(t *testing.T) {
+	request := &proto.RaidCompOptimizeRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			Raid: &proto.Raid{
+				Parties: []*proto.Party{{Players: []*proto.Player{
+					{
+						Name:      "Paladin",
+						Class:     proto.Class_ClassPaladin,
+						Race:      proto.Race_RaceHuman,
+						Equipment: &proto.EquipmentSpec{},
+						Spec: &proto.Player_RetributionPaladin{RetributionPaladin: &proto.RetributionPaladin{
+							Options: &proto.RetributionPaladin_Options{ClassOptions: &proto.PaladinOptions{}},
+						}},
+					},
+					{
+						Name:      "Druid",
+						Class:     proto.Class_ClassDruid,
+						Race:      proto.Race_RaceTauren,
+						Equipment: &proto.EquipmentSpec{},
+						Spec: &proto.Player_BalanceDruid{BalanceDruid: &proto.BalanceDruid{
+							Options: &proto.BalanceDruid_Options{ClassOptions: &proto.DruidOptions{}},
+						}},
+					},
+				}}},
+			},
+			Encounter:  STEncounter,
+			SimOptions: &proto.SimOptions{Iterations: 1, RandomSeed: 101},
+		},
+		RaidSize:      2,
+		RequiredBuffs: &proto.RaidBuffs{BlessingOfMight: true, MarkOfTheWild: true},
+	}
+	result := core.RunRaidCompOptimize(request)
+	if result.Error != nil {
+		t.Fatalf("Raid comp optimize failed: %s", result.Error.Message)
+	}
+
+	if len(result.Compositions) != 1 || !result.Compositions[0].Buffs.BlessingOfMight || result.Compositions[0].Buffs.BlessingOfKings {
+		t.Fatalf("Expected one composition with Might instead of Kings, got %v", result.Compositions)
+	}
+
+	// The Paladin can provide either blessing, but not both at once.
+	request.RequiredBuffs = &proto.RaidBuffs{BlessingOfKings: true, BlessingOfMight: true}
+	if result := core.RunRaidCompOptimize(request); result.Error == nil || result.Error.Message != "No composition meets the constraints!" {
+		t.Fatalf("Expected an error for unmet constraints, got %v", result.Error)
+	}
+}
+
+// Tests that the raid comp optimizer can meet requirements for buffs which
+// classes only provide in composition mode.
+func TestRaidCompOptimizeClassBuffs(t *testing.T) {
+	players := []*proto.Player{
+		{Name: "Warrior", Class: proto.Class_ClassWarrior, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{}, Rotation: &proto.APLRotation{},
+			Spec: &proto.Player_ArmsWarrior{ArmsWarrior: &proto.ArmsWarrior{Options: &proto.ArmsWarrior_Options{ClassOptions: &proto.WarriorOptions{}}}}},
+		{Name: "Rogue", Class: proto.Class_ClassRogue, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{}, Rotation: &proto.APLRotation{},
+			Spec: &proto.Player_CombatRogue{CombatRogue: &proto.CombatRogue{Options: &proto.CombatRogue_Options{ClassOptions: &proto.RogueOptions{}}}}},
+		{Name: "Death Knight", Class: proto.Class_ClassDeathKnight, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{}, Rotation: &proto.APLRotation{},
+			Spec: &proto.Player_FrostDeathKnight{FrostDeathKnight: &proto.FrostDeathKnight{Options: &proto.FrostDeathKnight_Options{ClassOptions: &proto.DeathKnightOptions{}}}}},
+		{Name: "Druid", Class: proto.Class_ClassDruid, Race: proto.Race_RaceTauren, Equipment: &proto.EquipmentSpec{}, Rotation: &proto.APLRotation{},
+			Spec: &proto.Player_BalanceDruid{BalanceDruid: &proto.BalanceDruid{Options: &proto.BalanceDruid_Options{ClassOptions: &proto.DruidOptions{}}}}},
+		{Name: "Priest", Class: proto.Class_ClassPriest, Race: proto.Race_RaceHuman, Equipment: &proto.EquipmentSpec{}, Rotation: &proto.APLRotation{},
+			Spec: &proto.Player_ShadowPriest{ShadowPriest: &proto.ShadowPriest{Options: &proto.ShadowPriest_Options{ClassOptions: &proto.PriestOptions{}}}}},
+	}
+
+	result := core.RunRaidCompOptimize(&proto.RaidCompOptimizeRequest{
+		BaseSettings: &proto.RaidSimRequest{
+			Raid:       &proto.Raid{Parties: []*proto.Party{{Players: players}}},
+			Encounter:  STEncounter,
+			SimOptions: &proto.SimOptions{Iterations: 1, RandomSeed: 101},
+		},
+		RaidSize:      4,
+		RequiredBuffs: &proto.RaidBuffs{BattleShout: true, HornOfWinter: true, MoonkinAura: true, MindQuickening: true},
+	})
+	if result.Error != nil {
+		t.Fatalf("Raid comp optimize failed: %s", result.Error.Message)
+	}
+
+	// Only leaving out the rogue provides every required buff.
+	if len(result.Compositions) != 1 || slices.ContainsFunc(result.Compositions[0].Players, func(player *proto.UnitReference) bool { return player.Index == 1 }) {
+		t.Fatalf("Expected one composition without the rogue, got %v", result.Compositions)
+	}
+}
+
+// Tests that external cooldowns are cast by the giving player onto the raid
+// member chosen by its APL, and show up in the metrics of both players.
+func TestExternalCooldownCastOnRaidMember(t *testing.T) {
+	holyPriest := func(name string) *proto.Player {
+		return &proto.Player{
+			Name:      name,
+			Class:     proto.Class_ClassPriest,
+			Race:      proto.Race_RaceHuman,
+			Equipment: &proto.EquipmentSpec{},
+			Spec: &proto.Player_HolyPriest{HolyPriest: &proto.HolyPriest{
+				Options: &proto.HolyPriest_Options{ClassOptions: &proto.PriestOptions{}},
+			}},
+		}
+	}
+
+	giver := holyPriest("Giver")
+	giver.Rotation = &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		PriorityList: []*proto.APLListItem{{
+			Action: &proto.APLAction{Action: &proto.APLAction_CastFriendlySpell{CastFriendlySpell: &proto.APLActionCastFriendlySpell{
+				SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 47788}},
+				Target:  &proto.UnitReference{Type: proto.UnitReference_Player, Index: 1},
+			}}},
+		}},
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: []*proto.Player{giver, holyPriest("Receiver")}}},
+		},
+		Encounter:  STEncounter,
+		SimOptions: SimOptions,
+	}
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Raid sim failed: %s", result.Error.Message)
+	}
+
+	guardianSpirit := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 47788}}
+	giverMetrics := result.RaidMetrics.Parties[0].Players[0]
+	receiverMetrics := result.RaidMetrics.Parties[0].Players[1]
+
+	var casts int32
+	for _, action := range giverMetrics.Actions {
+		if googleProto.Equal(action.Id, guardianSpirit) {
+			for _, target := range action.Targets {
+				if target.UnitIndex == receiverMetrics.UnitIndex {
+					casts += target.Casts
+				}
+			}
+		}
+	}
+	if casts == 0 {
+		t.Errorf("Expected Guardian Spirit casts on the receiver in the giver's metrics")
+	}
+
+	// The receiver's aura is tagged with the giver's raid index. 10s uptime for
+	// each cast in the 5 minute encounter, on a 3 minute cooldown.
+	givenAura := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 47788}, Tag: 0}
+	var uptime float64
+	for _, aura := range receiverMetrics.Auras {
+		if googleProto.Equal(aura.Id, givenAura) {
+			uptime = aura.UptimeSecondsAvg
+		}
+	}
+	if uptime != 20 {
+		t.Errorf("Expected 20s of Guardian Spirit uptime tagged with the giver on the receiver, got %0.1f", uptime)
+	}
+}
+
+// Tests that a major cooldown with a configured target buffs that target rather
+// than the player casting it.
+func TestExternalCooldownConfiguredTarget(t *testing.T) {
+	unholyDeathKnight := &proto.Player{
+		Name:      "Death Knight",
+		Class:     proto.Class_ClassDeathKnight,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_UnholyDeathKnight{UnholyDeathKnight: &proto.UnholyDeathKnight{
+			Options: &proto.UnholyDeathKnight_Options{
+				ClassOptions:       &proto.DeathKnightOptions{},
+				UnholyFrenzyTarget: &proto.UnitReference{Type: proto.UnitReference_Player, Index: 1},
+			},
+		}},
+		Rotation: &proto.APLRotation{
+			Type: proto.APLRotation_TypeAPL,
+			PriorityList: []*proto.APLListItem{{
+				Action: &proto.APLAction{Action: &proto.APLAction_AutocastOtherCooldowns{AutocastOtherCooldowns: &proto.APLActionAutocastOtherCooldowns{}}},
+			}},
+		},
+	}
+	receiver := &proto.Player{
+		Name:      "Receiver",
+		Class:     proto.Class_ClassWarrior,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_FuryWarrior{FuryWarrior: &proto.FuryWarrior{
+			Options: &proto.FuryWarrior_Options{ClassOptions: &proto.WarriorOptions{}},
+		}},
+		Rotation: &proto.APLRotation{},
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: []*proto.Player{unholyDeathKnight, receiver}}},
+		},
+		Encounter:  STEncounter,
+		SimOptions: SimOptions,
+	}
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Raid sim failed: %s", result.Error.Message)
+	}
+
+	// Both auras are tagged with the death knight's raid index.
+	unholyFrenzy := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 49016}, Tag: 0}
+	uptime := func(metrics *proto.UnitMetrics) float64 {
+		for _, aura := range metrics.Auras {
+			if googleProto.Equal(aura.Id, unholyFrenzy) {
+				return aura.UptimeSecondsAvg
+			}
+		}
+		return 0
+	}
+
+	if casterUptime := uptime(result.RaidMetrics.Parties[0].Players[0]); casterUptime != 0 {
+		t.Errorf("Expected no Unholy Frenzy uptime on the death knight, got %0.1f", casterUptime)
+	}
+	if receiverUptime := uptime(result.RaidMetrics.Parties[0].Players[1]); receiverUptime == 0 {
+		t.Errorf("Expected Unholy Frenzy uptime on the configured target")
+	}
+}
+
+// Tests that Hand of Sacrifice moves part of the damage its target takes to
+// the paladin who cast it.
+func TestHandOfSacrificeTransfersDamage(t *testing.T) {
+	paladin := &proto.Player{
+		Name:      "Paladin",
+		Class:     proto.Class_ClassPaladin,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_HolyPaladin{HolyPaladin: &proto.HolyPaladin{
+			Options: &proto.HolyPaladin_Options{ClassOptions: &proto.PaladinOptions{}},
+		}},
+		Rotation: &proto.APLRotation{
+			Type: proto.APLRotation_TypeAPL,
+			PriorityList: []*proto.APLListItem{{
+				Action: &proto.APLAction{Action: &proto.APLAction_CastFriendlySpell{CastFriendlySpell: &proto.APLActionCastFriendlySpell{
+					SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 6940}},
+					Target:  &proto.UnitReference{Type: proto.UnitReference_Player, Index: 1},
+				}}},
+			}},
+		},
+	}
+	tank := &proto.Player{
+		Name:      "Tank",
+		Class:     proto.Class_ClassWarrior,
+		Race:      proto.Race_RaceHuman,
+		Equipment: &proto.EquipmentSpec{},
+		Spec: &proto.Player_ProtectionWarrior{ProtectionWarrior: &proto.ProtectionWarrior{
+			Options: &proto.ProtectionWarrior_Options{ClassOptions: &proto.WarriorOptions{}},
+		}},
+		Rotation: &proto.APLRotation{},
+	}
+
+	result := core.RunRaidSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: []*proto.Player{paladin, tank}}},
+			Tanks:   []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 1}},
+		},
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets: []*proto.Target{{
+				Stats:         stats.Stats{stats.Armor: 7684}.ToProtoArray(),
+				MobType:       proto.MobType_MobTypeDemon,
+				MinBaseDamage: 10000,
+				SwingSpeed:    2,
+			}},
+		},
+		SimOptions: &proto.SimOptions{Iterations: 1, RandomSeed: 101},
+	})
+	if result.Error != nil {
+		t.Fatalf("Raid sim failed: %s", result.Error.Message)
+	}
+
+	transfer := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 6940}, Tag: 1}
+	paladinMetrics := result.RaidMetrics.Parties[0].Players[0]
+	var transferred, damageDone float64
+	for _, action := range paladinMetrics.Actions {
+		for _, target := range action.Targets {
+			if target.UnitIndex != paladinMetrics.UnitIndex {
+				damageDone += target.Damage
+			} else if googleProto.Equal(action.Id, transfer) {
+				transferred += target.Damage
+			}
+		}
+	}
+	if transferred <= 0 {
+		t.Errorf("Expected damage transferred to the paladin in its metrics")
+	}
+	if math.Abs(paladinMetrics.Dps.Avg*60-damageDone) > 1 {
+		t.Errorf("Expected the transferred damage not to count as paladin DPS, got %0.1f DPS from %0.1f damage done", paladinMetrics.Dps.Avg, damageDone)
+	}
+}
+
+func TestInvalidSettingsErrorOutcome(t *testing.T) {
+	priest := func() *proto.Player {
+		return &proto.Player{
+			Name:      "Priest",
+			Class:     proto.Class_ClassPriest,
+			Race:      proto.Race_RaceHuman,
+			Equipment: &proto.EquipmentSpec{},
+			Spec: &proto.Player_HolyPriest{HolyPriest: &proto.HolyPriest{
+				Options: &proto.HolyPriest_Options{ClassOptions: &proto.PriestOptions{}},
+			}},
+		}
+	}
+
+	badTalents := priest()
+	badTalents.TalentsString = "1234"
+	missingItem := priest()
+	missingItem.Equipment = &proto.EquipmentSpec{Items: []*proto.ItemSpec{{}, {Id: 999999999}}}
+	// Activating an aura is always ready, so the rotation never gives up control.
+	infiniteLoop := priest()
+	infiniteLoop.Rotation = &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		PriorityList: []*proto.APLListItem{{Action: &proto.APLAction{
+			Uuid: &proto.UUID{Value: "infinite-loop"},
+			Action: &proto.APLAction_ActivateAura{ActivateAura: &proto.APLActionActivateAura{
+				AuraId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 81208}},
+			}},
+		}}},
+	}
+
+	testCases := []struct {
+		name      string
+		player    *proto.Player
+		code      proto.ErrorCode
+		fieldPath string
+		aplUuid   *proto.UUID
+	}{
+		{"talents", badTalents, proto.ErrorCode_ErrorCodeInvalidTalents, "raid.parties[0].players[1].talents_string", nil},
+		{"equipment", missingItem, proto.ErrorCode_ErrorCodeInvalidEquipment, "raid.parties[0].players[1].equipment.items[1]", nil},
+		{"infinite loop", infiniteLoop, proto.ErrorCode_ErrorCodeAplInfiniteLoop, "raid.parties[0].players[1].rotation", &proto.UUID{Value: "infinite-loop"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := core.RunRaidSim(&proto.RaidSimRequest{
+				Raid: &proto.Raid{
+					Parties: []*proto.Party{{Players: []*proto.Player{priest(), tc.player}}},
+				},
+				Encounter:  STEncounter,
+				SimOptions: &proto.SimOptions{Iterations: 1},
+			})
+			if result.Error == nil {
+				t.Fatalf("Expected an error outcome")
+			}
+
+			expected := &proto.ErrorOutcome{
+				Message:   result.Error.Message,
+				Code:      tc.code,
+				Unit:      &proto.UnitReference{Type: proto.UnitReference_Player, Index: 1},
+				FieldPath: tc.fieldPath,
+				AplUuid:   tc.aplUuid,
+			}
+			if !googleProto.Equal(result.Error, expected) {
+				t.Errorf("Expected error outcome %v, got %v", expected, result.Error)
+			}
+		})
+	}
+}
+
 // To quickly debug raid sim issues, uncomment this test and copy in a request string.
 /*
 func testRaidString(t *testing.T, raidString string) {
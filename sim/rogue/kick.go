@@ -0,0 +1,31 @@
+package rogue
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (rogue *Rogue) registerKick() {
+	rogue.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 1766},
+		SpellSchool: core.SpellSchoolPhysical,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    core.MaxMeleeRange,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    rogue.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMeleeSpecialHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*5)
+			}
+		},
+	})
+}
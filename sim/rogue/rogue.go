@@ -121,6 +121,15 @@ func (rogue *Rogue) GetRogue() *Rogue {
 
 func (rogue *Rogue) AddRaidBuffs(_ *proto.RaidBuffs)   {}
 func (rogue *Rogue) AddPartyBuffs(_ *proto.PartyBuffs) {}
+func (rogue *Rogue) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	raidBuffs.SwiftbladesCunning = true
+}
+func (rogue *Rogue) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	debuffs.WeakenedArmor = true
+	if rogue.Options.LethalPoison != proto.RogueOptions_NoPoison {
+		debuffs.MasterPoisoner = true
+	}
+}
 
 func (rogue *Rogue) AddComboPointsOrAnticipation(sim *core.Simulation, numPoints int32, metric *core.ResourceMetrics) {
 	if rogue.Talents.Anticipation && rogue.ComboPoints()+numPoints > 5 {
@@ -210,6 +219,7 @@ func (rogue *Rogue) Initialize() {
 	rogue.registerShadowBladesCD()
 	rogue.registerCrimsonTempest()
 	rogue.registerPreparationCD()
+	rogue.registerKick()
 
 	rogue.ruthlessnessMetrics = rogue.NewComboPointMetrics(core.ActionID{SpellID: 14161})
 	rogue.relentlessStrikesMetrics = rogue.NewEnergyMetrics(core.ActionID{SpellID: 58423})
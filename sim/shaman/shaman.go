@@ -240,6 +240,7 @@ func (shaman *Shaman) Initialize() {
 	shaman.registerShocks()
 	shaman.registerUnleashElements()
 	shaman.registerAscendanceSpell()
+	shaman.registerWindShearSpell()
 
 	shaman.registerBloodlustCD()
 	shaman.registerStormlashCD()
@@ -0,0 +1,31 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+func (shaman *Shaman) registerWindShearSpell() {
+	shaman.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 57994},
+		SpellSchool: core.SpellSchoolNature,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagAPL | core.SpellFlagInterrupt,
+		MaxRange:    25,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    shaman.NewTimer(),
+				Duration: time.Second * 12,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMagicHit)
+			if result.Landed() {
+				target.InterruptCast(sim, spell, time.Second*3)
+			}
+		},
+	})
+}
@@ -87,6 +87,14 @@ func (warlock *Warlock) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 
 }
 
+func (warlock *Warlock) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	raidBuffs.DarkIntent = true
+}
+
+func (warlock *Warlock) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	debuffs.CurseOfElements = true
+}
+
 func (warlock *Warlock) Reset(sim *core.Simulation) {
 }
 
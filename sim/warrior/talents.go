@@ -23,6 +23,7 @@ func (warrior *Warrior) ApplyTalents() {
 	warrior.registerDragonRoar()
 
 	// Level 75
+	warrior.registerVigilance()
 
 	// Level 90
 	warrior.registerAvatar()
@@ -453,3 +454,42 @@ func (war *Warrior) registerStormBolt() {
 		},
 	})
 }
+
+func (war *Warrior) registerVigilance() {
+	if !war.Talents.Vigilance {
+		return
+	}
+
+	vigilanceAuras := war.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+		return core.VigilanceAura(unit, war.Index)
+	})
+
+	war.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: core.VigilanceSpellID},
+		Flags:          core.SpellFlagAPL | core.SpellFlagHelpful,
+		ClassSpellMask: SpellMaskVigilance,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				NonEmpty: true,
+			},
+			IgnoreHaste: true,
+			CD: core.Cooldown{
+				Timer:    war.NewTimer(),
+				Duration: core.VigilanceCD,
+			},
+		},
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return target.Type == core.PlayerUnit && target != &war.Unit
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			vigilanceAuras.Get(target).Activate(sim)
+		},
+		RelatedAuraArrays: vigilanceAuras.ToMap(),
+	})
+}
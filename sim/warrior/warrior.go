@@ -30,6 +30,7 @@ const (
 	SpellMaskDemoralizingBanner
 	SpellMaskAvatar
 	SpellMaskDemoralizingShout
+	SpellMaskVigilance
 
 	// Special attacks
 	SpellMaskSweepingStrikes
@@ -151,9 +152,26 @@ func (warrior *Warrior) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 
 }
 
+// Tanks shout for stamina, damage dealers for attack power.
+func (warrior *Warrior) AddCompositionRaidBuffs(raidBuffs *proto.RaidBuffs) {
+	if warrior.Spec == proto.Spec_SpecProtectionWarrior {
+		raidBuffs.CommandingShout = true
+	} else {
+		raidBuffs.BattleShout = true
+	}
+}
+
 func (warrior *Warrior) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
+func (warrior *Warrior) AddRaidDebuffs(debuffs *proto.Debuffs) {
+	debuffs.WeakenedArmor = true
+	debuffs.WeakenedBlows = true
+	if warrior.Spec != proto.Spec_SpecProtectionWarrior {
+		debuffs.PhysicalVulnerability = true
+	}
+}
+
 func (warrior *Warrior) Initialize() {
 	warrior.sharedHSCleaveCD = warrior.NewTimer()
 	warrior.sharedShoutsCD = warrior.NewTimer()
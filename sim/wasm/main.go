@@ -290,15 +290,7 @@ func raidSimResultCombination(this js.Value, args []js.Value) interface{} {
 	combineRes := func() (res *proto.RaidSimResult) {
 		defer func() {
 			if err := recover(); err != nil {
-				errStr := ""
-				switch errt := err.(type) {
-				case string:
-					errStr = errt
-				case error:
-					errStr = errt.Error()
-				}
-				errStr += "\nStack Trace:\n" + string(debug.Stack())
-				res = &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: errStr}}
+				res = &proto.RaidSimResult{Error: core.ErrorOutcomeFromPanic(err)}
 			}
 		}()
 		return core.CombineConcurrentSimResults(combRequest.Results, false)
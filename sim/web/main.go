@@ -25,6 +25,7 @@ import (
 	"github.com/wowsims/mop/sim/core"
 	proto "github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/encounters/scripted"
 
 	googleProto "google.golang.org/protobuf/proto"
 )
@@ -48,10 +49,22 @@ func main() {
 	var host = flag.String("host", "localhost:3333", "URL to host the interface on.")
 	var launch = flag.Bool("launch", true, "auto launch browser")
 	var skipVersionCheck = flag.Bool("nvc", false, "set true to skip version check")
+	var presimCacheDir = flag.String("presimcache", "", "Folder to cache presim results in across restarts. Disabled when empty.")
+	var encounterScriptsDir = flag.String("encounterscripts", "", "Folder of EncounterScript files (protojson) to load as preset encounters.")
 
 	flag.Parse()
 
 	fmt.Printf("Version: %s\n", Version)
+	if *presimCacheDir != "" {
+		if err := core.EnablePresimDiskCache(*presimCacheDir); err != nil {
+			log.Printf("Presim disk cache disabled: %s", err)
+		}
+	}
+	if *encounterScriptsDir != "" {
+		if err := scripted.LoadEncounterScripts(*encounterScriptsDir); err != nil {
+			log.Fatalf("Failed to load encounter scripts: %s", err)
+		}
+	}
 	if !*skipVersionCheck && Version != "development" {
 		go func() {
 			resp, err := http.Get("https://api.github.com/repos/wowsims/mop/releases/latest")
@@ -106,6 +119,15 @@ var handlers = map[string]apiHandler{
 	"/computeStats": {msg: func() googleProto.Message { return &proto.ComputeStatsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.ComputeStats(msg.(*proto.ComputeStatsRequest))
 	}},
+	"/validateGear": {msg: func() googleProto.Message { return &proto.ValidateGearRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.ValidateGear(msg.(*proto.ValidateGearRequest))
+	}},
+	"/compareSims": {msg: func() googleProto.Message { return &proto.CompareSimsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.CompareSims(msg.(*proto.CompareSimsRequest))
+	}},
+	"/reforgeOptimize": {msg: func() googleProto.Message { return &proto.ReforgeOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.OptimizeReforges(msg.(*proto.ReforgeOptimizeRequest))
+	}},
 	"/abortById": {msg: func() googleProto.Message { return &proto.AbortRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		requestId := msg.(*proto.AbortRequest).RequestId
 		triggered := simsignals.AbortById(requestId)
@@ -120,6 +142,21 @@ var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter, requestId)
 	}},
+	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
+	}},
+	"/statPlotAsync": {msg: func() googleProto.Message { return &proto.StatPlotRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunStatPlotAsync(msg.(*proto.StatPlotRequest), reporter, requestId)
+	}},
+	"/aplTuningAsync": {msg: func() googleProto.Message { return &proto.APLTuningRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunAPLTuningAsync(msg.(*proto.APLTuningRequest), reporter, requestId)
+	}},
+	"/defensivePlanAsync": {msg: func() googleProto.Message { return &proto.DefensivePlanRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunDefensivePlanAsync(msg.(*proto.DefensivePlanRequest), reporter, requestId)
+	}},
+	"/raidCompOptimizeAsync": {msg: func() googleProto.Message { return &proto.RaidCompOptimizeRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunRaidCompOptimizeAsync(msg.(*proto.RaidCompOptimizeRequest), reporter, requestId)
+	}},
 }
 
 type server struct {
@@ -200,7 +237,7 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil {
+				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalStatPlotResult != nil || progMetric.FinalAplTuningResult != nil || progMetric.FinalDefensivePlanResult != nil || progMetric.FinalRaidCompResult != nil {
 					return
 				}
 			}
@@ -258,7 +295,7 @@ func (s *server) setupAsyncServer() {
 		}
 
 		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil {
+		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil || latest.FinalStatPlotResult != nil || latest.FinalAplTuningResult != nil || latest.FinalDefensivePlanResult != nil || latest.FinalRaidCompResult != nil {
 			s.progMut.Lock()
 			delete(s.asyncProgresses, msg.ProgressId)
 			s.progMut.Unlock()
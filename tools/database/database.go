@@ -274,7 +274,10 @@ func ReadDatabaseFromJson(jsonStr string) *WowDatabase {
 	if err := protojson.Unmarshal([]byte(jsonStr), dbProto); err != nil {
 		panic(err)
 	}
+	return databaseFromUIProto(dbProto)
+}
 
+func databaseFromUIProto(dbProto *proto.UIDatabase) *WowDatabase {
 	enchants := make(map[int32]*proto.UIEnchant, len(dbProto.Enchants))
 	for _, v := range dbProto.Enchants {
 		enchants[v.EffectId] = v
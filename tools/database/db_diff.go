@@ -0,0 +1,276 @@
+package database
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldChange is a single changed field of a database entry.
+type FieldChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+type EntryDiff struct {
+	ID      int32
+	Name    string
+	Changes []FieldChange
+}
+
+type CategoryDiff struct {
+	Name    string
+	Added   []EntryDiff
+	Removed []EntryDiff
+	Changed []EntryDiff
+}
+
+// DatabaseDiff lists the differences between two generated databases, e.g.
+// to review data changes after a client data refresh.
+type DatabaseDiff struct {
+	Categories []*CategoryDiff
+}
+
+// Reads a database written by WriteJson or WriteBinary, depending on the file extension.
+func ReadDatabaseFromFile(filePath string) (*WowDatabase, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dbProto := &proto.UIDatabase{}
+	if strings.HasSuffix(filePath, ".json") {
+		err = protojson.Unmarshal(data, dbProto)
+	} else {
+		err = googleProto.Unmarshal(data, dbProto)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return databaseFromUIProto(dbProto), nil
+}
+
+func DiffDatabases(oldDB *WowDatabase, newDB *WowDatabase) *DatabaseDiff {
+	return &DatabaseDiff{
+		Categories: []*CategoryDiff{
+			diffCategory("Items", oldDB.Items, newDB.Items, (*proto.UIItem).GetName),
+			diffCategory("Gems", oldDB.Gems, newDB.Gems, (*proto.UIGem).GetName),
+			diffCategory("Enchants", oldDB.Enchants, newDB.Enchants, (*proto.UIEnchant).GetName),
+			diffCategory("Reforge Stats", oldDB.ReforgeStats, newDB.ReforgeStats, func(reforge *proto.ReforgeStat) string {
+				return reforge.FromStat.String() + " -> " + reforge.ToStat.String()
+			}),
+		},
+	}
+}
+
+func (diff *DatabaseDiff) IsEmpty() bool {
+	for _, category := range diff.Categories {
+		if len(category.Added)+len(category.Removed)+len(category.Changed) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (diff *DatabaseDiff) WriteReport(w io.Writer) {
+	for _, category := range diff.Categories {
+		fmt.Fprintf(w, "%s: %d added, %d removed, %d changed\n", category.Name, len(category.Added), len(category.Removed), len(category.Changed))
+		for _, entry := range category.Added {
+			fmt.Fprintf(w, "  + %d %s\n", entry.ID, entry.Name)
+		}
+		for _, entry := range category.Removed {
+			fmt.Fprintf(w, "  - %d %s\n", entry.ID, entry.Name)
+		}
+		for _, entry := range category.Changed {
+			fmt.Fprintf(w, "  ~ %d %s\n", entry.ID, entry.Name)
+			for _, change := range entry.Changes {
+				fmt.Fprintf(w, "      %s: %s -> %s\n", change.Path, change.Old, change.New)
+			}
+		}
+	}
+}
+
+func diffCategory[T googleProto.Message](name string, oldEntries map[int32]T, newEntries map[int32]T, entryName func(T) string) *CategoryDiff {
+	category := &CategoryDiff{Name: name}
+
+	ids := slices.Collect(maps.Keys(oldEntries))
+	for id := range newEntries {
+		if _, ok := oldEntries[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		oldEntry, inOld := oldEntries[id]
+		newEntry, inNew := newEntries[id]
+		switch {
+		case !inOld:
+			category.Added = append(category.Added, EntryDiff{ID: id, Name: entryName(newEntry)})
+		case !inNew:
+			category.Removed = append(category.Removed, EntryDiff{ID: id, Name: entryName(oldEntry)})
+		default:
+			changes := diffMessage("", oldEntry.ProtoReflect(), newEntry.ProtoReflect(), nil)
+			if len(changes) > 0 {
+				category.Changed = append(category.Changed, EntryDiff{ID: id, Name: entryName(newEntry), Changes: changes})
+			}
+		}
+	}
+
+	return category
+}
+
+// Compares all fields of the two messages, which must be of the same type.
+func diffMessage(path string, oldMsg protoreflect.Message, newMsg protoreflect.Message, changes []FieldChange) []FieldChange {
+	fields := oldMsg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fieldPath := fd.JSONName()
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		switch {
+		case fd.IsList():
+			changes = diffList(fieldPath, fd, oldMsg.Get(fd).List(), newMsg.Get(fd).List(), changes)
+		case fd.IsMap():
+			changes = diffMap(fieldPath, fd, oldMsg.Get(fd).Map(), newMsg.Get(fd).Map(), changes)
+		case fd.Message() != nil:
+			if oldMsg.Has(fd) != newMsg.Has(fd) {
+				changes = append(changes, FieldChange{Path: fieldPath, Old: formatMessage(oldMsg, fd), New: formatMessage(newMsg, fd)})
+			} else if oldMsg.Has(fd) {
+				changes = diffMessage(fieldPath, oldMsg.Get(fd).Message(), newMsg.Get(fd).Message(), changes)
+			}
+		default:
+			changes = diffValue(fieldPath, fd, oldMsg.Get(fd), newMsg.Get(fd), changes)
+		}
+	}
+	return changes
+}
+
+func diffList(path string, fd protoreflect.FieldDescriptor, oldList protoreflect.List, newList protoreflect.List, changes []FieldChange) []FieldChange {
+	for i := 0; i < max(oldList.Len(), newList.Len()); i++ {
+		elemPath := fmt.Sprintf("%s[%s]", path, indexLabel(fd, int64(i)))
+		switch {
+		case i >= oldList.Len():
+			changes = append(changes, FieldChange{Path: elemPath, Old: "-", New: formatValue(fd, newList.Get(i))})
+		case i >= newList.Len():
+			changes = append(changes, FieldChange{Path: elemPath, Old: formatValue(fd, oldList.Get(i)), New: "-"})
+		case fd.Message() != nil:
+			changes = diffMessage(elemPath, oldList.Get(i).Message(), newList.Get(i).Message(), changes)
+		default:
+			changes = diffValue(elemPath, fd, oldList.Get(i), newList.Get(i), changes)
+		}
+	}
+	return changes
+}
+
+func diffMap(path string, fd protoreflect.FieldDescriptor, oldMap protoreflect.Map, newMap protoreflect.Map, changes []FieldChange) []FieldChange {
+	keys := map[any]protoreflect.MapKey{}
+	collectKey := func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys[key.Interface()] = key
+		return true
+	}
+	oldMap.Range(collectKey)
+	newMap.Range(collectKey)
+
+	isIntKey := false
+	switch fd.MapKey().Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		isIntKey = true
+	}
+
+	sortedKeys := slices.SortedFunc(maps.Values(keys), func(a, b protoreflect.MapKey) int {
+		if isIntKey {
+			return cmp.Compare(a.Int(), b.Int())
+		}
+		return strings.Compare(a.String(), b.String())
+	})
+
+	valueFd := fd.MapValue()
+	for _, key := range sortedKeys {
+		keyPath := path + "[" + key.String() + "]"
+		if isIntKey {
+			keyPath = fmt.Sprintf("%s[%s]", path, indexLabel(fd, key.Int()))
+		}
+
+		switch {
+		case !oldMap.Has(key):
+			changes = append(changes, FieldChange{Path: keyPath, Old: "-", New: formatValue(valueFd, newMap.Get(key))})
+		case !newMap.Has(key):
+			changes = append(changes, FieldChange{Path: keyPath, Old: formatValue(valueFd, oldMap.Get(key)), New: "-"})
+		case valueFd.Message() != nil:
+			changes = diffMessage(keyPath, oldMap.Get(key).Message(), newMap.Get(key).Message(), changes)
+		default:
+			changes = diffValue(keyPath, valueFd, oldMap.Get(key), newMap.Get(key), changes)
+		}
+	}
+	return changes
+}
+
+func diffValue(path string, fd protoreflect.FieldDescriptor, oldValue protoreflect.Value, newValue protoreflect.Value, changes []FieldChange) []FieldChange {
+	if oldValue.Equal(newValue) {
+		return changes
+	}
+	return append(changes, FieldChange{Path: path, Old: formatValue(fd, oldValue), New: formatValue(fd, newValue)})
+}
+
+// Stats are stored as arrays or maps indexed by stat, and scaling options are
+// keyed by item level state, so use their names instead of the raw numbers.
+func indexLabel(fd protoreflect.FieldDescriptor, index int64) string {
+	switch {
+	case fd.Name() == "stats" || fd.Name() == "socketBonus":
+		return proto.Stat(index).String()
+	case fd.Name() == "scaling_options":
+		return proto.ItemLevelState(index).String()
+	default:
+		return strconv.FormatInt(index, 10)
+	}
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case protoreflect.MessageKind:
+		data, err := protojson.Marshal(value.Message().Interface())
+		if err != nil {
+			return "?"
+		}
+
+		// protojson randomizes whitespace, so compact it for a stable report.
+		buffer := new(bytes.Buffer)
+		json.Compact(buffer, data)
+		return buffer.String()
+	default:
+		return value.String()
+	}
+}
+
+func formatMessage(msg protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !msg.Has(fd) {
+		return "-"
+	}
+	return formatValue(fd, msg.Get(fd))
+}
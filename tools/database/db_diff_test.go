@@ -0,0 +1,78 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestDiffDatabases(t *testing.T) {
+	oldDB := NewWowDatabase()
+	oldDB.Items[1] = &proto.UIItem{
+		Id:    1,
+		Name:  "Test Sword",
+		Ilvl:  489,
+		Stats: stats.Stats{stats.Strength: 100}.ToProtoArray(),
+		ScalingOptions: map[int32]*proto.ScalingItemProperties{
+			int32(proto.ItemLevelState_Base): {Ilvl: 489, Stats: map[int32]float64{int32(proto.Stat_StatStrength): 100}},
+		},
+	}
+	oldDB.Gems[2] = &proto.UIGem{Id: 2, Name: "Old Gem"}
+	oldDB.ReforgeStats[3] = &proto.ReforgeStat{Id: 3, FromStat: proto.Stat_StatHitRating, ToStat: proto.Stat_StatCritRating, Multiplier: 0.4}
+
+	newDB := NewWowDatabase()
+	newDB.Items[1] = googleProto.Clone(oldDB.Items[1]).(*proto.UIItem)
+	newDB.Items[1].Ilvl = 496
+	newDB.Items[1].Stats[proto.Stat_StatStrength] = 110
+	newDB.Items[1].ScalingOptions[int32(proto.ItemLevelState_Base)].Stats[int32(proto.Stat_StatStrength)] = 110
+	newDB.Items[1].ScalingOptions[int32(proto.ItemLevelState_Base)].Ilvl = 496
+	newDB.Gems[4] = &proto.UIGem{Id: 4, Name: "New Gem"}
+	newDB.ReforgeStats[3] = &proto.ReforgeStat{Id: 3, FromStat: proto.Stat_StatHitRating, ToStat: proto.Stat_StatCritRating, Multiplier: 0.5}
+
+	diff := DiffDatabases(oldDB, newDB)
+	if diff.IsEmpty() {
+		t.Fatal("Expected differences")
+	}
+
+	items := diff.Categories[0]
+	if len(items.Changed) != 1 {
+		t.Fatalf("Expected 1 changed item, got %d", len(items.Changed))
+	}
+	expectedChanges := []FieldChange{
+		{Path: "stats[StatStrength]", Old: "100", New: "110"},
+		{Path: "ilvl", Old: "489", New: "496"},
+		{Path: "scalingOptions[Base].stats[StatStrength]", Old: "100", New: "110"},
+		{Path: "scalingOptions[Base].ilvl", Old: "489", New: "496"},
+	}
+	if len(items.Changed[0].Changes) != len(expectedChanges) {
+		t.Fatalf("Expected changes %v, got %v", expectedChanges, items.Changed[0].Changes)
+	}
+	for i, change := range items.Changed[0].Changes {
+		if change != expectedChanges[i] {
+			t.Errorf("Expected change %v, got %v", expectedChanges[i], change)
+		}
+	}
+
+	gems := diff.Categories[1]
+	if len(gems.Added) != 1 || gems.Added[0].ID != 4 || len(gems.Removed) != 1 || gems.Removed[0].ID != 2 {
+		t.Errorf("Expected gem 4 to be added and gem 2 to be removed, got %v", gems)
+	}
+
+	reforges := diff.Categories[3]
+	if len(reforges.Changed) != 1 || reforges.Changed[0].Changes[0].Path != "multiplier" {
+		t.Errorf("Expected reforge multiplier change, got %v", reforges.Changed)
+	}
+
+	report := &strings.Builder{}
+	diff.WriteReport(report)
+	if !strings.Contains(report.String(), "  ~ 1 Test Sword\n      stats[StatStrength]: 100 -> 110\n") {
+		t.Errorf("Unexpected report:\n%s", report.String())
+	}
+
+	if !DiffDatabases(oldDB, oldDB).IsEmpty() {
+		t.Error("Expected no differences between identical databases")
+	}
+}
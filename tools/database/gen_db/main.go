@@ -23,6 +23,7 @@ import (
 // To do a full re-scrape, delete the previous output file first.
 // go run ./tools/database/gen_db -outDir=assets -gen=atlasloot
 // go run ./tools/database/gen_db -outDir=assets -gen=db
+// go run ./tools/database/gen_db diff old_db.json assets/database/db.bin
 
 var outDir = flag.String("outDir", "assets", "Path to output directory for writing generated .go files.")
 var genAsset = flag.String("gen", "", "Asset to generate. Valid values are 'db', 'atlasloot', 'wowhead-items', 'wowhead-spells', 'wowhead-itemdb', 'mop-items', and 'wago-db2-items'")
@@ -31,6 +32,11 @@ var dbPath = flag.String("dbPath", "./tools/database/wowsims.db", "Location of w
 func main() {
 	flag.Parse()
 
+	if flag.Arg(0) == "diff" {
+		diffDatabases(flag.Args()[1:])
+		return
+	}
+
 	database.DatabasePath = *dbPath
 
 	if *outDir == "" {
@@ -962,3 +968,27 @@ func addSpellIcons(db *database.WowDatabase, spellIds []int32, icons map[int]dat
 		}
 	}
 }
+
+// Prints the items, gems, enchants and reforge stats which differ between two
+// generated databases, in either JSON or binary format.
+func diffDatabases(args []string) {
+	if len(args) != 2 {
+		log.Fatalf("Usage: gen_db diff <old db> <new db>")
+	}
+
+	oldDB, err := database.ReadDatabaseFromFile(args[0])
+	if err != nil {
+		log.Fatalf("failed to read old database: %v", err)
+	}
+	newDB, err := database.ReadDatabaseFromFile(args[1])
+	if err != nil {
+		log.Fatalf("failed to read new database: %v", err)
+	}
+
+	diff := database.DiffDatabases(oldDB, newDB)
+	if diff.IsEmpty() {
+		fmt.Println("No differences found.")
+		return
+	}
+	diff.WriteReport(os.Stdout)
+}
@@ -32,10 +32,29 @@ type Variant struct {
 	Name string
 }
 
+// ProcSpellInfo describes item procs which cast a spell that deals damage,
+// absorbs damage or returns mana, instead of granting stats.
+type ProcSpellInfo struct {
+	Type             string // Suffix of the shared.ProcSpellType constant
+	SpellID          int
+	School           dbc.SpellSchool
+	Coefficient      float64
+	BaseValue        float64
+	Variance         float64
+	BonusCoefficient float64
+	DurationMs       int
+	ProcChance       float64
+	Rppm             float64
+	RppmHasteMod     bool
+	RppmCritMod      bool
+	IcdMs            int
+}
+
 type Entry struct {
 	Variants  []*Variant
 	Tooltip   []string
 	ProcInfo  ProcInfo
+	ProcSpell *ProcSpellInfo // Only set for procs which don't grant stats
 	Supported bool
 }
 
@@ -50,6 +69,21 @@ var missingEffectsMap = map[string][]Variant{
 	"ItemEffects":    {},
 }
 
+// MissingEffect is an item or enchant effect which could neither be
+// generated nor has a manual implementation.
+type MissingEffect struct {
+	ID        int
+	Name      string
+	Tooltip   []string
+	ProcInfo  ProcInfo
+	ProcSpell *ProcSpellInfo
+}
+
+var missingEffectsReport = map[string][]*MissingEffect{
+	"EnchantEffects": {},
+	"ItemEffects":    {},
+}
+
 type EffectParseResult byte
 
 const (
@@ -82,6 +116,9 @@ func GenerateEffectsFile(groups []*Group, outFile string, templateString string)
 		"asCoreCallback": asCoreCallback,
 		"asCoreProcMask": asCoreProcMask,
 		"asCoreOutcome":  asCoreOutcome,
+		"asCoreSchool":   asCoreSchool,
+		"asCoreDuration": asCoreDuration,
+		"asRppmConfig":   asRppmConfig,
 		"formatStrings":  formatStrings,
 	}
 	tmpl := template.Must(template.New("effects").Funcs(funcMap).Parse(templateString))
@@ -125,6 +162,63 @@ func GenerateMissingEffectsFile() error {
 	return nil
 }
 
+// Writes every item and enchant effect which lacks an implementation, with
+// its parsed proc info and tooltip, to help with implementing them by hand.
+func GenerateEffectsReport(outFile string) error {
+	tmpl := template.Must(template.New("effectsReport").Funcs(map[string]any{
+		"asCoreCallback": asCoreCallback,
+		"asCoreProcMask": asCoreProcMask,
+		"asCoreOutcome":  asCoreOutcome,
+		"asCoreDuration": asCoreDuration,
+		"asRppmConfig":   asRppmConfig,
+	}).Parse(TmplStrEffectsReport))
+	f, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", outFile, err)
+	}
+	defer f.Close()
+
+	for _, effects := range missingEffectsReport {
+		sort.Slice(effects, func(i, j int) bool {
+			return effects[i].ID < effects[j].ID
+		})
+	}
+
+	if err := tmpl.Execute(f, map[string]any{
+		"MinIlvl":        MIN_EFFECT_ILVL,
+		"ItemEffects":    missingEffectsReport["ItemEffects"],
+		"EnchantEffects": missingEffectsReport["EnchantEffects"],
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+func BuildMissingItemEffect(parsed *proto.UIItem, name string, instance *dbc.DBC) *MissingEffect {
+	tooltipString, id := dbc.GetItemEffectSpellTooltip(int(parsed.Id))
+	tooltip, _ := tooltip.ParseTooltip(tooltipString, tooltip.DBCTooltipDataProvider{DBC: instance}, int64(id))
+	renderedTooltip := tooltip.String()
+
+	effect := &MissingEffect{ID: int(parsed.Id), Name: name, Tooltip: strings.Split(renderedTooltip, "\n")}
+	effect.ProcInfo, _ = BuildProcInfo(parsed, instance, renderedTooltip)
+	effect.ProcSpell, _ = BuildProcSpellInfo(parsed, instance)
+	return effect
+}
+
+func BuildMissingEnchantEffect(enchant *proto.UIEnchant, name string, instance *dbc.DBC, enchantSpellEffects map[int]*dbc.SpellEffect) *MissingEffect {
+	effect := &MissingEffect{ID: int(enchant.EffectId), Name: name}
+	if enchantingSpell, ok := enchantSpellEffects[int(enchant.EffectId)]; ok {
+		tooltipString := instance.Spells[enchantingSpell.SpellID].Description
+		tooltip, _ := tooltip.ParseTooltip(tooltipString, tooltip.DBCTooltipDataProvider{DBC: instance}, int64(enchantingSpell.SpellID))
+		renderedTooltip := tooltip.String()
+
+		effect.Tooltip = strings.Split(renderedTooltip, "\n")
+		effect.ProcInfo, _ = BuildEnchantProcInfo(enchant, instance, renderedTooltip)
+	}
+	return effect
+}
+
 func GenerateEnchantEffects(instance *dbc.DBC, db *WowDatabase) {
 	groupMapProc := map[string]Group{}
 	enchantSpellEffects := map[int]*dbc.SpellEffect{}
@@ -143,6 +237,8 @@ func GenerateEnchantEffects(instance *dbc.DBC, db *WowDatabase) {
 
 		if TryParseEnchantEffect(parsed, groupMapProc, instance, enchantSpellEffects) == EffectParseResultUnsupported {
 			missingEffectsMap["EnchantEffects"] = append(missingEffectsMap["EnchantEffects"], Variant{ID: enchant.EffectId, Name: enchant.Name})
+			missingEffectsReport["EnchantEffects"] = append(missingEffectsReport["EnchantEffects"],
+				BuildMissingEnchantEffect(parsed, enchant.Name, instance, enchantSpellEffects))
 		}
 	}
 
@@ -168,11 +264,13 @@ func GenerateItemEffects(instance *dbc.DBC, db *WowDatabase, itemSources map[int
 
 		if (result == EffectParseResultUnsupported) ||
 			(TryParseProcEffect(parsed, instance, groupMapProc) == EffectParseResultUnsupported) {
+			name := parsed.Name + BuildItemDifficultyPostfix(itemSources, int(parsed.Id), instance)
 			missingEffectsMap["ItemEffects"] = append(missingEffectsMap["ItemEffects"],
 				Variant{
 					ID:   int(parsed.Id),
-					Name: parsed.Name + BuildItemDifficultyPostfix(itemSources, int(parsed.Id), instance),
+					Name: name,
 				})
+			missingEffectsReport["ItemEffects"] = append(missingEffectsReport["ItemEffects"], BuildMissingItemEffect(parsed, name, instance))
 		}
 	}
 
@@ -319,11 +417,11 @@ func TryParseProcEffect(parsed *proto.UIItem, instance *dbc.DBC, groupMapProc ma
 		return EffectParseResultSuccess
 	}
 
-	// check if the item has any kind of proc as we only support stat proc parsing right now
+	// Items without a stat proc can still proc a spell which deals damage, absorbs damage or returns mana
 	if effects, ok := instance.ItemEffectsByParentID[int(parsed.Id)]; ok && parsed.ScalingOptions[0].Ilvl > MIN_EFFECT_ILVL {
 		for _, effect := range effects {
 			if SpellHasTriggerEffect(effect.SpellID, instance) {
-				return EffectParseResultUnsupported
+				return TryParseProcSpellEffect(parsed, instance, groupMapProc)
 			}
 		}
 	}
@@ -331,6 +429,35 @@ func TryParseProcEffect(parsed *proto.UIItem, instance *dbc.DBC, groupMapProc ma
 	return EffectParseResultInvalid
 }
 
+func TryParseProcSpellEffect(parsed *proto.UIItem, instance *dbc.DBC, groupMapProc map[string]Group) EffectParseResult {
+	procSpell, supported := BuildProcSpellInfo(parsed, instance)
+	if procSpell == nil {
+		return EffectParseResultUnsupported
+	}
+
+	tooltipString, id := dbc.GetItemEffectSpellTooltip(int(parsed.Id))
+	tooltip, _ := tooltip.ParseTooltip(tooltipString, tooltip.DBCTooltipDataProvider{DBC: instance}, int64(id))
+
+	groupName := procSpell.Type + " Procs"
+	grp, exists := groupMapProc[groupName]
+	if !exists {
+		grp = Group{Name: groupName}
+	}
+
+	renderedTooltip := tooltip.String()
+	entry := Entry{Tooltip: strings.Split(renderedTooltip, "\n"), Variants: []*Variant{{ID: int(parsed.Id), Name: parsed.Name}}, ProcSpell: procSpell}
+	entry.ProcInfo, entry.Supported = BuildProcInfo(parsed, instance, renderedTooltip)
+	entry.Supported = entry.Supported && supported
+	grp.Entries = append(grp.Entries, &entry)
+	groupMapProc[groupName] = grp
+
+	if !entry.Supported {
+		return EffectParseResultUnsupported
+	}
+
+	return EffectParseResultSuccess
+}
+
 func TryParseOnUseEffect(parsed *proto.UIItem, groupMap map[string]Group) EffectParseResult {
 	// Effect was already manually implemented
 	if core.HasItemEffect(parsed.Id) {
@@ -438,6 +565,89 @@ func BuildProcInfo(parsed *proto.UIItem, instance *dbc.DBC, tooltip string) (Pro
 	return ProcInfo{}, false
 }
 
+// Returns nil if the item does not proc a damage, absorb or mana spell.
+func BuildProcSpellInfo(parsed *proto.UIItem, instance *dbc.DBC) (*ProcSpellInfo, bool) {
+	// we do not support generation of more than one proc effect right now
+	itemEffectInfo := instance.ItemEffectsByParentID[int(parsed.Id)]
+	if len(itemEffectInfo) != 1 {
+		return nil, false
+	}
+
+	procSpell, ok := instance.Spells[itemEffectInfo[0].SpellID]
+	if !ok {
+		return nil, false
+	}
+
+	triggerSpellID := 0
+	for _, effect := range instance.SpellEffects[itemEffectInfo[0].SpellID] {
+		if effect.EffectAura == dbc.A_PROC_TRIGGER_SPELL {
+			triggerSpellID = effect.EffectTriggerSpell
+		}
+	}
+
+	triggerSpell, ok := instance.Spells[triggerSpellID]
+	if !ok || len(instance.SpellEffects[triggerSpellID]) != 1 {
+		return nil, false
+	}
+
+	var effect dbc.SpellEffect
+	for _, triggerEffect := range instance.SpellEffects[triggerSpellID] {
+		effect = triggerEffect
+	}
+
+	info := &ProcSpellInfo{
+		SpellID:     triggerSpellID,
+		Coefficient: effect.Coefficient,
+		BaseValue:   float64(effect.EffectBasePoints),
+		Variance:    effect.Variance,
+		IcdMs:       int(procSpell.ProcCategoryRecovery),
+	}
+
+	supported := true
+	switch {
+	case effect.EffectType == dbc.E_SCHOOL_DAMAGE:
+		info.Type = "Damage"
+		info.School = dbc.SpellSchool(triggerSpell.SchoolMask)
+		info.BonusCoefficient = effect.EffectBonusCoefficient
+
+		// Attack power scaling is not supported by the shared helper
+		supported = effect.BonusCoefficientFromAP == 0
+	case effect.EffectAura == dbc.A_SCHOOL_ABSORB:
+		info.Type = "Absorb"
+		info.DurationMs = int(triggerSpell.Duration)
+	case effect.EffectType == dbc.E_ENERGIZE && len(effect.EffectMiscValues) > 0 &&
+		dbc.MapPowerTypeEnumToResourceType[int32(effect.EffectMiscValues[0])] == proto.ResourceType_ResourceTypeMana:
+		info.Type = "Mana"
+	default:
+		return nil, false
+	}
+
+	if procSpell.SpellProcsPerMinute > 0 {
+		info.Rppm = float64(procSpell.SpellProcsPerMinute)
+		for _, mod := range procSpell.RppmModifiers {
+			switch mod.ModifierType {
+			case dbc.RPPMModifierHaste:
+				info.RppmHasteMod = true
+			case dbc.RPPMModifierCrit:
+				info.RppmCritMod = true
+			default:
+				// Spec, class and item level modifiers need a manual implementation
+				supported = false
+			}
+		}
+	} else if procSpell.ProcChance > 0 && procSpell.ProcChance <= 100 {
+		info.ProcChance = float64(procSpell.ProcChance) / 100
+	} else {
+		supported = false
+	}
+
+	if SpellUsesStacks(triggerSpellID, instance) {
+		supported = false
+	}
+
+	return info, supported
+}
+
 func BuildEnchantProcInfo(enchant *proto.UIEnchant, instance *dbc.DBC, tooltip string) (ProcInfo, bool) {
 	procSpellID := enchant.SpellId
 	if procSpellID == 0 {
@@ -639,6 +849,47 @@ func asCoreOutcome(outcome core.HitOutcome) string {
 	return "core.OutcomeEmpty"
 }
 
+func asCoreSchool(school dbc.SpellSchool) string {
+	schools := []string{}
+	for _, flag := range []struct {
+		school dbc.SpellSchool
+		name   string
+	}{
+		{dbc.PHYSICAL, "core.SpellSchoolPhysical"},
+		{dbc.HOLY, "core.SpellSchoolHoly"},
+		{dbc.FIRE, "core.SpellSchoolFire"},
+		{dbc.NATURE, "core.SpellSchoolNature"},
+		{dbc.FROST, "core.SpellSchoolFrost"},
+		{dbc.SHADOW, "core.SpellSchoolShadow"},
+		{dbc.ARCANE, "core.SpellSchoolArcane"},
+	} {
+		if school.Has(flag.school) {
+			schools = append(schools, flag.name)
+		}
+	}
+
+	if len(schools) == 0 {
+		return "core.SpellSchoolPhysical"
+	}
+
+	return strings.Join(schools, " | ")
+}
+
+func asCoreDuration(ms int) string {
+	return "core.DurationFromSeconds(" + strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64) + ")"
+}
+
+func asRppmConfig(info *ProcSpellInfo) string {
+	config := "core.RPPMConfig{PPM: " + strconv.FormatFloat(info.Rppm, 'f', -1, 32) + "}"
+	if info.RppmHasteMod {
+		config += ".WithHasteMod()"
+	}
+	if info.RppmCritMod {
+		config += ".WithCritMod()"
+	}
+	return config
+}
+
 func (entry *Entry) AddVariant(variant *Variant) {
 	entry.Variants = append(entry.Variants, variant)
 	sort.Slice(entry.Variants, func(i, j int) bool {
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/tools/database"
+	"github.com/wowsims/mop/tools/database/dbc"
+)
+
+// Regenerates the item and enchant effect files from the outputs of gen_db,
+// without re-reading wowsims.db.
+// go run ./tools/database/gen_effects -outDir=assets
+// go run ./tools/database/gen_effects -outDir=assets --report=effects_report.txt
+
+var outDir = flag.String("outDir", "assets", "Path to the gen_db output directory.")
+var report = flag.String("report", "", "Path to write a report of item and enchant effects which lack an implementation, with their parsed proc info and tooltips")
+
+func main() {
+	flag.Parse()
+
+	db, err := database.ReadDatabaseFromFile(fmt.Sprintf("%s/database/db.bin", *outDir))
+	if err != nil {
+		log.Fatalf("failed to read database: %v", err)
+	}
+	instance := dbc.GetDBC()
+
+	dropSources := map[int][]*proto.DropSource{}
+	for _, item := range db.Items {
+		for _, source := range item.Sources {
+			if drop := source.GetDrop(); drop != nil {
+				dropSources[int(item.Id)] = append(dropSources[int(item.Id)], drop)
+			}
+		}
+	}
+
+	database.GenerateItemEffects(instance, db, dropSources)
+	database.GenerateEnchantEffects(instance, db)
+	if err := database.GenerateMissingEffectsFile(); err != nil {
+		log.Fatalf("failed to write missing effects file: %v", err)
+	}
+	if *report != "" {
+		if err := database.GenerateEffectsReport(*report); err != nil {
+			log.Fatalf("failed to write effects report: %v", err)
+		}
+	}
+}
@@ -39,7 +39,9 @@ func RegisterAllProcs() {
 	{{- range (.Tooltip | formatStrings 100) }}
 	// {{.}}
 	{{- end}}
-	{{- if .Supported}}
+	{{- if .ProcSpell}}
+	{{- template "procSpell" .}}
+	{{- else if .Supported}}
 	{{- if len .Variants | eq 1}}
 	shared.NewProcStatBonusEffect(shared.ProcStatBonusEffect{
 		{{with index .Variants 0 -}}
@@ -91,7 +93,67 @@ func RegisterAllProcs() {
 {{- end }}
 
 {{- end }}
-}`
+}
+
+{{- define "procSpell"}}
+	{{- $c := ""}}{{if not .Supported}}{{$c = "// "}}{{end}}
+	{{- if len .Variants | eq 1}}
+	{{$c}}shared.NewProcSpellEffect(shared.ProcSpellEffect{
+		{{- with index .Variants 0}}
+	{{$c}}	Name:               "{{ .Name }}",
+	{{$c}}	ItemID:             {{ .ID }},
+		{{- end}}
+	{{- template "procSpellConfig" .}}
+	{{$c}}})
+	{{- else}}
+	{{$c}}shared.NewProcSpellEffectWithVariants(shared.ProcSpellEffect{
+	{{- template "procSpellConfig" .}}
+	{{$c}}}, []shared.ItemVariant{
+		{{- range .Variants}}
+	{{$c}}	{ItemID: {{.ID}}, ItemName: "{{.Name}}"},
+		{{- end}}
+	{{$c}}})
+	{{- end}}
+{{- end}}
+
+{{- define "procSpellConfig"}}
+	{{- $c := ""}}{{if not .Supported}}{{$c = "// "}}{{end}}
+	{{- with .ProcSpell}}
+	{{$c}}	SpellID:            {{ .SpellID }},
+	{{$c}}	Type:               shared.ProcSpellType{{ .Type }},
+	{{- end}}
+	{{$c}}	Callback:           {{ .ProcInfo.Callback | asCoreCallback }},
+	{{$c}}	ProcMask:           {{ .ProcInfo.ProcMask | asCoreProcMask }},
+	{{$c}}	Outcome:            {{ .ProcInfo.Outcome | asCoreOutcome }},
+	{{$c}}	RequireDamageDealt: {{ .ProcInfo.RequireDamageDealt }},
+	{{- with .ProcSpell}}
+	{{- if gt .Rppm 0.0}}
+	{{$c}}	Rppm:               {{ . | asRppmConfig }},
+	{{- else}}
+	{{$c}}	ProcChance:         {{ .ProcChance }},
+	{{- end}}
+	{{- if gt .IcdMs 0}}
+	{{$c}}	ICD:                {{ .IcdMs | asCoreDuration }},
+	{{- end}}
+	{{- if ne .Coefficient 0.0}}
+	{{$c}}	Coefficient:        {{ .Coefficient }},
+	{{- else}}
+	{{$c}}	BaseValue:          {{ .BaseValue }},
+	{{- end}}
+	{{- if ne .Variance 0.0}}
+	{{$c}}	Variance:           {{ .Variance }},
+	{{- end}}
+	{{- if ne .BonusCoefficient 0.0}}
+	{{$c}}	BonusCoefficient:   {{ .BonusCoefficient }},
+	{{- end}}
+	{{- if eq .Type "Damage"}}
+	{{$c}}	School:             {{ .School | asCoreSchool }},
+	{{- end}}
+	{{- if eq .Type "Absorb"}}
+	{{$c}}	Duration:           {{ .DurationMs | asCoreDuration }},
+	{{- end}}
+	{{- end}}
+{{- end}}`
 
 const TmplStrEnchant = `package mop
 
@@ -158,3 +220,30 @@ export const MISSING_ENCHANT_EFFECTS = [
 {{- end }}
 ]
 `
+
+const TmplStrEffectsReport = `Effects above item level {{ .MinIlvl }} without an implementation
+{{- define "missingEffect" }}
+
+{{ .ID }} - {{ .Name }}
+{{- if .ProcInfo.Callback }}
+	Callback:           {{ .ProcInfo.Callback | asCoreCallback }}
+	ProcMask:           {{ .ProcInfo.ProcMask | asCoreProcMask }}
+	Outcome:            {{ .ProcInfo.Outcome | asCoreOutcome }}
+	RequireDamageDealt: {{ .ProcInfo.RequireDamageDealt }}
+{{- end }}
+{{- with .ProcSpell }}
+	Proc spell:         {{ .Type }} {{ .SpellID }}
+	{{- if gt .Rppm 0.0 }}, {{ . | asRppmConfig }}{{ else }}, {{ .ProcChance }} chance{{ end }}
+	{{- if gt .IcdMs 0 }}, {{ .IcdMs | asCoreDuration }} ICD{{ end }}
+{{- end }}
+{{- range .Tooltip }}
+	> {{ . }}
+{{- end }}
+{{- end }}
+
+Items ({{ len .ItemEffects }})
+{{- range .ItemEffects }}{{ template "missingEffect" . }}{{ end }}
+
+Enchants ({{ len .EnchantEffects }})
+{{- range .EnchantEffects }}{{ template "missingEffect" . }}{{ end }}
+`
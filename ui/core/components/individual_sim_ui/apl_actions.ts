@@ -17,6 +17,7 @@ import {
 	APLActionGroupReference,
 	APLActionGuardianHotwDpsRotation,
 	APLActionGuardianHotwDpsRotation_Strategy as HotwStrategy,
+	APLActionInterrupt,
 	APLActionItemSwap,
 	APLActionItemSwap_SwapSet as ItemSwapSet,
 	APLActionMove,
@@ -81,6 +82,7 @@ export class APLActionPicker extends Input<Player<any>, APLAction> {
 						eventID,
 						APLAction.create({
 							condition: newValue,
+							uuid: { value: randomUUID() },
 						}),
 					);
 				}
@@ -180,6 +182,7 @@ export class APLActionPicker extends Input<Player<any>, APLAction> {
 	getInputValue(): APLAction {
 		const actionKind = this.kindPicker.getInputValue();
 		return APLAction.create({
+			uuid: this.getSourceValue()?.uuid || { value: randomUUID() },
 			condition: this.conditionPicker.getInputValue(),
 			action: {
 				oneofKind: actionKind,
@@ -212,15 +215,26 @@ export class APLActionPicker extends Input<Player<any>, APLAction> {
 		if (newActionKind) {
 			this.actionPicker!.setInputValue((newValue.action as any)[newActionKind]);
 		}
+
+		if (!newValue.uuid || newValue.uuid.value == '') {
+			newValue.uuid = {
+				value: randomUUID(),
+			};
+		}
 	}
 
 	private makeAPLAction<K extends NonNullable<APLActionKind>>(kind: K, implVal: APLActionImplTypesUnion[K]): APLAction {
 		if (!kind) {
-			return APLAction.create();
+			return APLAction.create({
+				uuid: { value: randomUUID() },
+			});
 		}
 		const obj: any = { oneofKind: kind };
 		obj[kind] = implVal;
-		return APLAction.create({ action: obj });
+		return APLAction.create({
+			action: obj,
+			uuid: { value: randomUUID() },
+		});
 	}
 
 	private updateActionPicker(newActionKind: APLActionKind) {
@@ -271,7 +285,7 @@ function actionFieldConfig(field: string): AplHelpers.APLPickerBuilderFieldConfi
 	return {
 		field: field,
 		newValue: () =>
-			APLValue.create({
+			APLAction.create({
 				uuid: { value: randomUUID() },
 			}),
 		factory: (parent, player, config) => new APLActionPicker(parent, player, config),
@@ -290,11 +304,11 @@ function actionListFieldConfig(field: string): AplHelpers.APLPickerBuilderFieldC
 					config.setValue(
 						eventID,
 						player,
-						newValue.map(val => val || APLAction.create()),
+						newValue.map(val => val || APLAction.create({ uuid: { value: randomUUID() } })),
 					);
 				},
 				itemLabel: 'action',
-				newItem: APLAction.create,
+				newItem: () => APLAction.create({ uuid: { value: randomUUID() } }),
 				copyItem: (oldValue: APLAction) => (oldValue ? APLAction.clone(oldValue) : oldValue),
 				newItemPicker: (
 					parent: HTMLElement,
@@ -346,6 +360,12 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		fields: [AplHelpers.actionIdFieldConfig('spellId', 'friendly_spells', ''), AplHelpers.unitFieldConfig('target', 'players')],
 		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getRaid()!.size() > 1 || player.shouldEnableTargetDummies(),
 	}),
+	['interrupt']: inputBuilder({
+		label: i18n.t('rotation_tab.apl.actions.interrupt.label'),
+		shortDescription: i18n.t('rotation_tab.apl.actions.interrupt.tooltip'),
+		newValue: APLActionInterrupt.create,
+		fields: [AplHelpers.unitFieldConfig('target', 'targets')],
+	}),
 	['multidot']: inputBuilder({
 		label: i18n.t('rotation_tab.apl.actions.multi_dot.label'),
 		submenu: ['casting'],
@@ -6,6 +6,7 @@ import { Player, UnitMetadata } from '../../player.js';
 import {
 	APLActionGuardianHotwDpsRotation_Strategy as HotwStrategy,
 	APLActionItemSwap_SwapSet as ItemSwapSet,
+	APLValueChakra,
 	APLValueEclipsePhase,
 	APLValueRuneSlot,
 	APLValueRuneType,
@@ -1082,6 +1083,28 @@ class APLGroupVariablePicker extends Input<Player<any>, any> {
 	}
 }
 
+export function chakraTypeFieldConfig(field: string): APLPickerBuilderFieldConfig<any, any> {
+	const values = [
+		{ value: APLValueChakra.ChakraSerenity, label: i18n.t('rotation_tab.apl.helpers.chakra_types.serenity') },
+		{ value: APLValueChakra.ChakraSanctuary, label: i18n.t('rotation_tab.apl.helpers.chakra_types.sanctuary') },
+		{ value: APLValueChakra.ChakraChastise, label: i18n.t('rotation_tab.apl.helpers.chakra_types.chastise') },
+		{ value: APLValueChakra.NoChakra, label: i18n.t('rotation_tab.apl.helpers.chakra_types.none') },
+	];
+
+	return {
+		field: field,
+		newValue: () => APLValueChakra.ChakraSerenity,
+		factory: (parent, player, config) =>
+			new TextDropdownPicker(parent, player, {
+				id: randomUUID(),
+				...config,
+				defaultLabel: i18n.t('rotation_tab.apl.helpers.chakra_types.serenity'),
+				equals: (a, b) => a == b,
+				values: values,
+			}),
+	};
+}
+
 export function eclipseTypeFieldConfig(field: string): APLPickerBuilderFieldConfig<any, any> {
 	const values = [
 		{ value: APLValueEclipsePhase.LunarPhase, label: i18n.t('rotation_tab.apl.helpers.eclipse_types.lunar') },
@@ -67,6 +67,8 @@ import {
 	APLValueMaxRage,
 	APLValueMaxRunicPower,
 	APLValueMin,
+	APLValueHolyPriestCurrentChakra,
+	APLValueHolyPriestSerendipityStacks,
 	APLValueMonkCurrentChi,
 	APLValueMonkMaxChi,
 	APLValueNextRuneCooldown,
@@ -97,6 +99,8 @@ import {
 	APLValueSpellTimeToCharge,
 	APLValueSpellTimeToReady,
 	APLValueSpellTravelTime,
+	APLValueTargetTimeToDie,
+	APLValueTargetTimeToPercent,
 	APLValueTotemRemainingTime,
 	APLValueTrinketProcsMaxRemainingICD,
 	APLValueTrinketProcsMinRemainingTime,
@@ -638,6 +642,20 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		newValue: APLValueNumberTargets.create,
 		fields: [],
 	}),
+	targetTimeToDie: inputBuilder({
+		label: i18n.t('rotation_tab.apl.values.target_time_to_die.label'),
+		submenu: ['encounter'],
+		shortDescription: i18n.t('rotation_tab.apl.values.target_time_to_die.tooltip'),
+		newValue: APLValueTargetTimeToDie.create,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets')],
+	}),
+	targetTimeToPercent: inputBuilder({
+		label: i18n.t('rotation_tab.apl.values.target_time_to_percent.label'),
+		submenu: ['encounter'],
+		shortDescription: i18n.t('rotation_tab.apl.values.target_time_to_percent.tooltip'),
+		newValue: APLValueTargetTimeToPercent.create,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets'), valueFieldConfig('healthPercent')],
+	}),
 	frontOfTarget: inputBuilder({
 		label: i18n.t('rotation_tab.apl.values.in_front_of_target.label'),
 		submenu: ['encounter'],
@@ -909,6 +927,22 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getSpec() == Spec.SpecBalanceDruid,
 		fields: [AplHelpers.eclipseTypeFieldConfig('eclipsePhase')],
 	}),
+	holyPriestCurrentChakra: inputBuilder({
+		label: i18n.t('rotation_tab.apl.values.holy_priest_current_chakra.label'),
+		submenu: ['holy_priest'],
+		shortDescription: i18n.t('rotation_tab.apl.values.holy_priest_current_chakra.tooltip'),
+		newValue: APLValueHolyPriestCurrentChakra.create,
+		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getSpec() == Spec.SpecHolyPriest,
+		fields: [AplHelpers.chakraTypeFieldConfig('chakra')],
+	}),
+	holyPriestSerendipityStacks: inputBuilder({
+		label: i18n.t('rotation_tab.apl.values.holy_priest_serendipity_stacks.label'),
+		submenu: ['holy_priest'],
+		shortDescription: i18n.t('rotation_tab.apl.values.holy_priest_serendipity_stacks.tooltip'),
+		newValue: APLValueHolyPriestSerendipityStacks.create,
+		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getSpec() == Spec.SpecHolyPriest,
+		fields: [],
+	}),
 	currentGenericResource: inputBuilder({
 		label: i18n.t('rotation_tab.apl.values.generic_resource.label'),
 		submenu: ['resources'],
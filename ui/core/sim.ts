@@ -6,6 +6,7 @@ import { Encounter } from './encounter';
 import { Player, UnitMetadata } from './player';
 import {
 	ComputeStatsRequest,
+	ErrorCode,
 	ErrorOutcome,
 	ErrorOutcomeType,
 	Raid as RaidProto,
@@ -317,7 +318,7 @@ export class Sim {
 
 			if (result.error) {
 				if (result.error.type != ErrorOutcomeType.ErrorOutcomeError) return result.error;
-				throw new SimError(result.error.message);
+				throw new SimError(result.error.message, result.error);
 			}
 			const simResult = await SimResult.makeNew(request, result);
 			if (!options.silent) {
@@ -347,7 +348,7 @@ export class Sim {
 			const request = this.makeRaidSimRequest(true);
 			const result = await this.workerPool.raidSimAsync(request, noop, signals);
 			if (result.error) {
-				throw new SimError(result.error.message);
+				throw new SimError(result.error.message, result.error);
 			}
 			const simResult = await SimResult.makeNew(request, result);
 			if (!options.silent) {
@@ -465,7 +466,7 @@ export class Sim {
 				}
 				if (result.error) {
 					if (result.error.type != ErrorOutcomeType.ErrorOutcomeError) return result;
-					throw new SimError(result.error.message);
+					throw new SimError(result.error.message, result.error);
 				}
 				return result;
 			} catch (error) {
@@ -791,9 +792,16 @@ export class Sim {
 
 export class SimError extends Error {
 	readonly errorStr: string;
+	readonly outcome?: ErrorOutcome;
 
-	constructor(errorStr: string) {
+	constructor(errorStr: string, outcome?: ErrorOutcome) {
 		super(errorStr);
 		this.errorStr = errorStr;
+		this.outcome = outcome;
+	}
+
+	// Whether the error was caused by invalid settings rather than a sim bug.
+	get isUserError(): boolean {
+		return !!this.outcome && this.outcome.code != ErrorCode.ErrorCodeUnknown;
 	}
 }
@@ -368,9 +368,11 @@ export abstract class SimUI extends Component {
 		});
 
 		const errorStr = (error as SimError).errorStr;
-		if (errorStr.startsWith('[USER_ERROR] ')) {
-			let alertStr = errorStr.substring('[USER_ERROR] '.length);
-			alertStr = await ActionId.replaceAllInString(alertStr);
+		if (error.isUserError) {
+			let alertStr = await ActionId.replaceAllInString(errorStr);
+			if (error.outcome!.fieldPath) {
+				alertStr += `\n\n(${error.outcome!.fieldPath})`;
+			}
 			alert(alertStr);
 			return;
 		}
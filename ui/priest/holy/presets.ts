@@ -46,6 +46,7 @@ export const P1_EP_WEIGHTS = PresetUtils.makePresetEpWeights(
 export const StandardTalents = {
 	name: 'Standard',
 	data: SavedTalents.create({
+		talentsString: '111133',
 		// talentsString: '05032031--325023051223010323151301351',
 		// glyphs: Glyphs.create({
 		// 	major1: MajorGlyph.GlyphOfShadow,